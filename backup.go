@@ -0,0 +1,242 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/homielabs/golang-rss-client/internal/config"
+	"github.com/homielabs/golang-rss-client/internal/opml"
+	"github.com/spf13/viper"
+)
+
+// stateFiles are the files in the data directory a backup carries: which
+// items have been read, the starred items and the reading progress. The
+// archive (archive.db) is left out, as it can grow large.
+var stateFiles = []string{"read.json", "stars.json", "progress.json"}
+
+// subscriptionsEntry is the OPML copy of the subscriptions in a backup, for
+// other readers. Restoring goes by the config instead.
+const subscriptionsEntry = "subscriptions.opml"
+
+// configBytes is the config as saved, or as it would be saved if there's no
+// file on disk yet.
+func configBytes() ([]byte, error) {
+	if used := viper.ConfigFileUsed(); used != "" {
+		return os.ReadFile(used)
+	}
+	dir, err := os.MkdirTemp("", config.AppName)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, config.FileName)
+	if err := viper.WriteConfigAs(path); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
+
+// writeBackup zips up the config, the subscriptions as OPML and every state
+// file there is.
+func writeBackup(out io.Writer) error {
+	archive := zip.NewWriter(out)
+	add := func(name string, data []byte) error {
+		w, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     name,
+			Method:   zip.Deflate,
+			Modified: time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+
+	data, err := configBytes()
+	if err != nil {
+		return err
+	}
+	if err := add(config.FileName, data); err != nil {
+		return err
+	}
+
+	outline, err := subscriptionOutline()
+	if err != nil {
+		return err
+	}
+	var subscriptions bytes.Buffer
+	err = opml.Write(&subscriptions, opml.Document{
+		Title:       config.AppName + " subscriptions",
+		DateCreated: time.Now().Format(time.RFC1123Z),
+		Body:        outline,
+	})
+	if err != nil {
+		return err
+	}
+	if err := add(subscriptionsEntry, subscriptions.Bytes()); err != nil {
+		return err
+	}
+
+	dir, err := config.DataDir()
+	if err != nil {
+		return err
+	}
+	for _, name := range stateFiles {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if err := add(name, data); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// runBackup implements `golang-rss-client backup`, bundling the config,
+// subscriptions and read/star state into one zip file to move to another
+// machine.
+func runBackup(args []string) int {
+	flags := flag.NewFlagSet("backup", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: golang-rss-client backup <file.zip>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	// written next to the backup first, so a failed backup doesn't leave
+	// half a file where a good one was
+	path := flags.Arg(0)
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	err = writeBackup(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// restoreTargets maps the entries of a backup to where they're restored to.
+// Anything else in the archive is left alone.
+func restoreTargets() (map[string]string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return nil, err
+	}
+	targets := map[string]string{config.FileName: config.FilePath()}
+	for _, name := range stateFiles {
+		targets[name] = filepath.Join(dir, name)
+	}
+	return targets, nil
+}
+
+// runRestore implements `golang-rss-client restore`, putting a backup's
+// config and state in place. It won't overwrite anything without --force.
+func runRestore(args []string) int {
+	flags := flag.NewFlagSet("restore", flag.ContinueOnError)
+	force := flags.Bool("force", false, "replace the config and state already here")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: golang-rss-client restore [--force] <file.zip>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	archive, err := zip.OpenReader(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer archive.Close()
+	targets, err := restoreTargets()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	var entries []*zip.File
+	var existing []string
+	for _, entry := range archive.File {
+		target, ok := targets[entry.Name]
+		if !ok {
+			continue
+		}
+		entries = append(entries, entry)
+		if _, err := os.Stat(target); err == nil {
+			existing = append(existing, target)
+		}
+	}
+	if len(entries) == 0 {
+		fmt.Fprintf(os.Stderr, "%s isn't a %s backup\n", flags.Arg(0), config.AppName)
+		return 1
+	}
+	if len(existing) > 0 && !*force {
+		fmt.Fprintf(os.Stderr, "not overwriting %s without --force\n", strings.Join(existing, ", "))
+		return 1
+	}
+
+	for _, entry := range entries {
+		target := targets[entry.Name]
+		if err := restoreEntry(entry, target); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Println("restored", target)
+	}
+	return 0
+}
+
+// restoreEntry writes one file out of a backup, by way of a temporary file so
+// that a failure leaves the old one be.
+func restoreEntry(entry *zip.File, target string) error {
+	r, err := entry.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("%s: %w", entry.Name, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	tmp := target + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, target)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/homielabs/golang-rss-client/internal/config"
+	"github.com/homielabs/golang-rss-client/internal/ical"
+)
+
+// runCalendar implements `golang-rss-client calendar`, writing the events in
+// feeds that carry them (meetups, conferences and the like) out as an .ics
+// file for a calendar to import. Without feed URLs, every subscribed feed is
+// looked through.
+func runCalendar(args []string) int {
+	flags := flag.NewFlagSet("calendar", flag.ContinueOnError)
+	path := flags.String("ics", "", "file to write the calendar to, instead of stdout")
+	fetch := flags.Bool("fetch", false, "fetch feeds instead of using the cached copies")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: golang-rss-client calendar [--ics events.ics] [--fetch] [feed URL...]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	loc, err := config.Timezone()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	var feeds []subscribedFeed
+	if flags.NArg() == 0 {
+		feeds, err = loadSubscribedFeeds(*fetch)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	} else {
+		fetcher, err := newFetcher(nil)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		for _, feedUrl := range flags.Args() {
+			feed, err := fetcher.Fetch(context.Background(), feedUrl)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", feedUrl, err)
+				return 1
+			}
+			feeds = append(feeds, subscribedFeed{url: feedUrl, feed: feed})
+		}
+	}
+
+	var events []ical.Event
+	for _, subscription := range feeds {
+		events = append(events, ical.Events(subscription.url, subscription.feed, loc)...)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Start.Before(events[j].Start)
+	})
+	if len(events) == 0 {
+		fmt.Fprintln(os.Stderr, "None of the feeds have events in them")
+		return 1
+	}
+
+	var out io.Writer = os.Stdout
+	if *path != "" {
+		file, err := os.Create(*path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer file.Close()
+		out = file
+	}
+	if err := ical.Write(out, calendarName(feeds), events, time.Now()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	log.Printf("calendar: wrote %d events", len(events))
+	return 0
+}
+
+// calendarName is what the calendar's called: the feed's title if there's
+// only the one.
+func calendarName(feeds []subscribedFeed) string {
+	if len(feeds) == 1 && feeds[0].feed.Title != "" {
+		return feeds[0].feed.Title
+	}
+	return config.AppName + " events"
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/homielabs/golang-rss-client/internal/config"
+	"github.com/homielabs/golang-rss-client/internal/opml"
+	"github.com/spf13/viper"
+)
+
+// subscriptionOutline builds the OPML outline for the subscriptions: their
+// groups as folders, then the feeds that aren't in any group. Titles come from
+// the feed cache, so a feed that's never been fetched goes by its URL, unless
+// it's been renamed.
+func subscriptionOutline() ([]opml.Outline, error) {
+	fetcher, err := newFetcher(nil)
+	if err != nil {
+		return nil, err
+	}
+	titles := config.FeedTitles()
+	feedOutline := func(feedUrl string) opml.Outline {
+		outline := opml.Outline{Text: feedUrl, Type: "rss", XMLURL: feedUrl}
+		if fetcher.Cache != nil {
+			if feed, err := fetcher.Cache.Load(feedUrl); err == nil {
+				if feed.Title != "" {
+					outline.Text = feed.Title
+					outline.Title = feed.Title
+				}
+				outline.HTMLURL = feed.Link
+			}
+		}
+		if title, ok := titles[feedUrl]; ok {
+			outline.Text = title
+			outline.Title = title
+		}
+		return outline
+	}
+
+	groups := config.Groups()
+	var names []string
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var outlines []opml.Outline
+	grouped := make(map[string]bool)
+	for _, name := range names {
+		folder := opml.Outline{Text: name, Title: name}
+		for _, feedUrl := range groups[name] {
+			folder.Outlines = append(folder.Outlines, feedOutline(feedUrl))
+			grouped[feedUrl] = true
+		}
+		outlines = append(outlines, folder)
+	}
+	for _, feedUrl := range viper.GetStringSlice("feedUrls") {
+		if !grouped[feedUrl] {
+			outlines = append(outlines, feedOutline(feedUrl))
+		}
+	}
+	return outlines, nil
+}
+
+// runExport implements `golang-rss-client export`, writing the subscriptions
+// out as OPML for other readers (or --import-opml) to pick up.
+func runExport(args []string) int {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	path := flags.String("opml", "", "file to write the OPML to, instead of stdout")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	var out io.Writer = os.Stdout
+	if *path != "" {
+		file, err := os.Create(*path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer file.Close()
+		out = file
+	}
+
+	outline, err := subscriptionOutline()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	err = opml.Write(out, opml.Document{
+		Title:       config.AppName + " subscriptions",
+		DateCreated: time.Now().Format(time.RFC1123Z),
+		Body:        outline,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
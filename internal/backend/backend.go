@@ -0,0 +1,84 @@
+// Package backend is where the reader's feeds come from. A Backend lists the
+// feeds subscribed to with it, reads them, and takes the read marks, stars
+// and subscriptions made here that it keeps itself. Direct reads the feed
+// URLs in the config from the web, and does by default; the sync servers'
+// packages (greader, miniflux and nextcloud) have backends of their own, for
+// the feeds read from them.
+package backend
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// Subscription is a feed subscribed to with a backend.
+type Subscription struct {
+	// URL is what the feed's read by, like miniflux:42 for a server's.
+	URL string
+	// Groups are what it's filed under there.
+	Groups []string
+}
+
+// Backend is somewhere feeds are subscribed to and read from.
+type Backend interface {
+	// Name is the backend's in the `backend` config setting.
+	Name() string
+	// Owns reports whether a feed URL is one of the backend's.
+	Owns(feedUrl string) bool
+	// List is the feeds subscribed to.
+	List(ctx context.Context) ([]Subscription, error)
+	// Fetch reads one of the feeds, given how it was when last read if it
+	// has been. It's a fetch.Source.
+	Fetch(ctx context.Context, source *url.URL, cached *gofeed.Feed) (*gofeed.Feed, error)
+	// MarkRead marks the backend's items read, or unread, there.
+	MarkRead(ctx context.Context, items []*gofeed.Item, read bool) error
+	// Star stars or unstars the backend's items there.
+	Star(ctx context.Context, items []*gofeed.Item, starred bool) error
+	// Subscribe subscribes to a feed there, returning the URL it's read by.
+	Subscribe(ctx context.Context, feedUrl string) (string, error)
+}
+
+// Tracker is a Backend whose items say whether they were read and starred
+// there when last read from it, so whatever's different here has changed
+// here since, to send it.
+type Tracker interface {
+	Backend
+	// StateKeys are the keys in the Custom map of the backend's items that
+	// say whether they're read and starred there: "true" or "false".
+	StateKeys() (read string, starred string)
+}
+
+// Backends are the backends configured. The first is the one with the feeds
+// none of the others owns: Direct.
+type Backends []Backend
+
+// For is the backend a feed's from.
+func (b Backends) For(feedUrl string) Backend {
+	for _, backend := range b[1:] {
+		if backend.Owns(feedUrl) {
+			return backend
+		}
+	}
+	return b[0]
+}
+
+// Named is the backend of a name, or nil if there's none configured.
+func (b Backends) Named(name string) Backend {
+	for _, backend := range b {
+		if backend.Name() == name {
+			return backend
+		}
+	}
+	return nil
+}
+
+// Names are the names of the backends, in order.
+func (b Backends) Names() []string {
+	var names []string
+	for _, backend := range b {
+		names = append(names, backend.Name())
+	}
+	return names
+}
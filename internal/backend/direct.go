@@ -0,0 +1,61 @@
+package backend
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/homielabs/golang-rss-client/internal/config"
+	"github.com/homielabs/golang-rss-client/internal/fetch"
+	"github.com/mmcdole/gofeed"
+	"github.com/spf13/viper"
+)
+
+// DirectName is Direct's name.
+const DirectName = "direct"
+
+// Direct is the backend of the feed URLs in the config, read straight from
+// where they're published (or from the sources on this machine). What's read
+// and starred is only kept here.
+type Direct struct {
+	Fetcher *fetch.Fetcher
+}
+
+// Name is "direct".
+func (d *Direct) Name() string {
+	return DirectName
+}
+
+// Owns reports true: Direct has any feed no other backend owns.
+func (d *Direct) Owns(feedUrl string) bool {
+	return true
+}
+
+// List is the feedUrls in the config. Their groups are the config's, which
+// can take in any backend's feeds, so they're left to it.
+func (d *Direct) List(ctx context.Context) ([]Subscription, error) {
+	var subscriptions []Subscription
+	for _, feedUrl := range viper.GetStringSlice("feedUrls") {
+		subscriptions = append(subscriptions, Subscription{URL: feedUrl})
+	}
+	return subscriptions, nil
+}
+
+// Fetch downloads a feed, or reads it from its source.
+func (d *Direct) Fetch(ctx context.Context, source *url.URL, _ *gofeed.Feed) (*gofeed.Feed, error) {
+	return d.Fetcher.Fetch(ctx, source.String())
+}
+
+// MarkRead does nothing, as there's nowhere else items are read.
+func (d *Direct) MarkRead(ctx context.Context, items []*gofeed.Item, read bool) error {
+	return nil
+}
+
+// Star does nothing, as there's nowhere else items are starred.
+func (d *Direct) Star(ctx context.Context, items []*gofeed.Item, starred bool) error {
+	return nil
+}
+
+// Subscribe adds a feed to the config's feedUrls, saving it.
+func (d *Direct) Subscribe(ctx context.Context, feedUrl string) (string, error) {
+	return feedUrl, config.AddFeedURL(feedUrl)
+}
@@ -0,0 +1,117 @@
+// Package capture files articles away into other tools, for reading or
+// acting on later: an org-mode capture file, a folder of markdown notes like
+// an Obsidian vault, Joplin, or a task to read it in Taskwarrior or todo.txt.
+package capture
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Config is the `capture` config section: where articles can be captured
+// to. Targets left unset aren't offered.
+type Config struct {
+	Org         Org         `mapstructure:"org"`
+	Obsidian    Obsidian    `mapstructure:"obsidian"`
+	Joplin      Joplin      `mapstructure:"joplin"`
+	Taskwarrior Taskwarrior `mapstructure:"taskwarrior"`
+	TodoTxt     TodoTxt     `mapstructure:"todotxt"`
+}
+
+// Article is what's captured.
+type Article struct {
+	Title     string
+	Link      string
+	Feed      string
+	Author    string
+	Published *time.Time
+	// Markdown is the article's text, as the reader converts it.
+	Markdown string
+}
+
+// Targets lists the names of the targets that are set up, in the order
+// they're offered.
+func (c Config) Targets() []string {
+	var targets []string
+	if c.Org.File != "" {
+		targets = append(targets, "org")
+	}
+	if c.Obsidian.Folder != "" {
+		targets = append(targets, "obsidian")
+	}
+	if c.Joplin.Token != "" {
+		targets = append(targets, "joplin")
+	}
+	if c.Taskwarrior.Enabled {
+		targets = append(targets, "taskwarrior")
+	}
+	if c.TodoTxt.File != "" {
+		targets = append(targets, "todo.txt")
+	}
+	return targets
+}
+
+// Validate checks every target that's set up has what it needs.
+func (c Config) Validate() error {
+	if c.Org.File != "" {
+		if err := c.Org.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Obsidian.Folder != "" {
+		if err := c.Obsidian.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Joplin.Token != "" {
+		if err := c.Joplin.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Taskwarrior.Enabled {
+		if err := c.Taskwarrior.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.TodoTxt.File != "" {
+		if err := c.TodoTxt.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Save captures an article to the named target, returning where it went: the
+// file it was written to, the notebook or the task.
+func Save(ctx context.Context, c Config, target string, article Article) (string, error) {
+	switch target {
+	case "org":
+		return filepath.Base(c.Org.File), c.Org.Append(article, time.Now())
+	case "obsidian":
+		return c.Obsidian.Write(article, time.Now())
+	case "joplin":
+		return c.Joplin.Create(ctx, article)
+	case "taskwarrior":
+		return c.Taskwarrior.Add(ctx, article)
+	case "todo.txt":
+		return filepath.Base(c.TodoTxt.File), c.TodoTxt.Append(article, time.Now())
+	}
+	return "", fmt.Errorf("capture: unknown target %q", target)
+}
+
+// expandHome replaces a leading ~ with the home directory, as shells do, so
+// paths in the config can be written the usual way.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
@@ -0,0 +1,159 @@
+package capture
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// defaultJoplinURL is where Joplin's clipper service listens unless it's been
+// told otherwise.
+const defaultJoplinURL = "http://localhost:41184"
+
+// joplinTimeout bounds each request to Joplin, which runs locally and
+// answers quickly when it's up at all.
+const joplinTimeout = 10 * time.Second
+
+// Joplin creates notes through the clipper service of a running Joplin, the
+// one its web clipper uses (Options > Web Clipper, where the token is).
+type Joplin struct {
+	// URL is the clipper service's, localhost:41184 unless it's been moved.
+	URL   string `mapstructure:"url"`
+	Token string `mapstructure:"token"`
+	// Notebook is the title of the notebook notes go in, empty for the one
+	// Joplin has selected.
+	Notebook string `mapstructure:"notebook"`
+	// Tags are put on every note, and created in Joplin if need be.
+	Tags []string `mapstructure:"tags"`
+}
+
+// Validate checks the service's URL is one.
+func (j Joplin) Validate() error {
+	if j.URL == "" {
+		return nil
+	}
+	parsed, err := url.Parse(j.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("capture: joplin url %q isn't an http(s) URL", j.URL)
+	}
+	return nil
+}
+
+// Create adds an article to Joplin as a note, returning the notebook it went
+// into.
+func (j Joplin) Create(ctx context.Context, article Article) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, joplinTimeout)
+	defer cancel()
+
+	note := map[string]interface{}{
+		"title":      strings.Join(strings.Fields(article.Title), " "),
+		"body":       strings.TrimSpace(article.Markdown),
+		"source_url": article.Link,
+		"author":     article.Author,
+	}
+	if len(j.Tags) > 0 {
+		note["tags"] = strings.Join(j.Tags, ",")
+	}
+	where := "Joplin"
+	if j.Notebook != "" {
+		id, title, err := j.notebook(ctx)
+		if err != nil {
+			return "", err
+		}
+		note["parent_id"] = id
+		where = title + " in Joplin"
+	}
+	return where, j.call(ctx, http.MethodPost, "notes", nil, note, nil)
+}
+
+// notebook looks the notebook up by its title, ignoring case, going through
+// as many pages of them as there are. It returns its ID and title.
+func (j Joplin) notebook(ctx context.Context) (string, string, error) {
+	for page := 1; ; page++ {
+		var folders struct {
+			Items []struct {
+				ID    string `json:"id"`
+				Title string `json:"title"`
+			} `json:"items"`
+			HasMore bool `json:"has_more"`
+		}
+		query := url.Values{"fields": {"id,title"}, "limit": {"100"}, "page": {fmt.Sprint(page)}}
+		if err := j.call(ctx, http.MethodGet, "folders", query, nil, &folders); err != nil {
+			return "", "", err
+		}
+		for _, folder := range folders.Items {
+			if strings.EqualFold(folder.Title, j.Notebook) {
+				return folder.ID, folder.Title, nil
+			}
+		}
+		if !folders.HasMore {
+			return "", "", fmt.Errorf("joplin: there's no notebook called %q", j.Notebook)
+		}
+	}
+}
+
+// call makes a request to the clipper service, decoding the response into
+// out if it's given. Joplin explains failures in the body.
+func (j Joplin) call(ctx context.Context, method string, path string, query url.Values, body interface{}, out interface{}) error {
+	base := j.URL
+	if base == "" {
+		base = defaultJoplinURL
+	}
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("token", j.Token)
+	endpoint := strings.TrimSuffix(base, "/") + "/" + path + "?" + query.Encode()
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "golang-rss-client")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		// the token is part of the URL, so the error's given without it
+		reason := err.Error()
+		if urlErr, ok := err.(*url.Error); ok {
+			reason = urlErr.Err.Error()
+		}
+		return fmt.Errorf("joplin: %s (is Joplin running, with the clipper service on?)", reason)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var failure struct {
+			Error string `json:"error"`
+		}
+		reason := resp.Status
+		if json.Unmarshal(data, &failure) == nil && failure.Error != "" {
+			reason = failure.Error
+		}
+		return fmt.Errorf("joplin: %s", reason)
+	}
+	if out != nil {
+		return json.Unmarshal(data, out)
+	}
+	return nil
+}
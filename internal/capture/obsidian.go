@@ -0,0 +1,144 @@
+package capture
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v2"
+)
+
+// defaultFilename is what notes are named when no template's set.
+const defaultFilename = "{{date}} {{title}}"
+
+// maxFilenameRunes keeps note names well under the limits of filesystems,
+// with room for the .md and a number to tell notes apart.
+const maxFilenameRunes = 120
+
+// Obsidian writes articles as markdown notes with YAML front matter into a
+// folder of a vault, or any folder of notes kept Zettelkasten-style.
+type Obsidian struct {
+	// Folder is where notes are written, like ~/vault/Inbox.
+	Folder string `mapstructure:"folder"`
+	// Filename is the template notes are named with: {{title}}, {{feed}},
+	// {{date}} and {{time}} (when it's captured) and {{published}} (the
+	// article's date) are filled in.
+	Filename string `mapstructure:"filename"`
+	// Tags are put in every note's front matter.
+	Tags []string `mapstructure:"tags"`
+}
+
+// Validate checks the filename template makes for a file name.
+func (o Obsidian) Validate() error {
+	if strings.ContainsAny(o.Filename, `/\`) {
+		return fmt.Errorf("capture: obsidian filename %q is a path, it should be a name", o.Filename)
+	}
+	return nil
+}
+
+// Write saves an article as a new note, numbering its name if there's
+// already a note by it. It returns the note's name.
+func (o Obsidian) Write(article Article, now time.Time) (string, error) {
+	folder := expandHome(o.Folder)
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		return "", err
+	}
+	note, err := o.note(article, now)
+	if err != nil {
+		return "", err
+	}
+	name := o.filename(article, now)
+	for n := 2; ; n++ {
+		file, err := os.OpenFile(filepath.Join(folder, name+".md"), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			name = fmt.Sprintf("%s %d", o.filename(article, now), n)
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		_, err = file.Write(note)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		return name + ".md", err
+	}
+}
+
+// note is an article as a markdown note: its details in the front matter,
+// then its title and text.
+func (o Obsidian) note(article Article, now time.Time) ([]byte, error) {
+	title := strings.Join(strings.Fields(article.Title), " ")
+	frontMatter := yaml.MapSlice{{Key: "title", Value: title}}
+	add := func(key string, value interface{}) {
+		if value != "" {
+			frontMatter = append(frontMatter, yaml.MapItem{Key: key, Value: value})
+		}
+	}
+	add("source", article.Link)
+	add("feed", article.Feed)
+	add("author", article.Author)
+	if article.Published != nil {
+		add("published", article.Published.Format("2006-01-02"))
+	}
+	add("created", now.Format("2006-01-02T15:04:05"))
+	if len(o.Tags) > 0 {
+		add("tags", o.Tags)
+	}
+	front, err := yaml.Marshal(frontMatter)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	b.WriteString("---\n")
+	b.Write(front)
+	b.WriteString("---\n\n")
+	if title != "" {
+		b.WriteString("# " + title + "\n\n")
+	}
+	if body := strings.TrimSpace(article.Markdown); body != "" {
+		b.WriteString(body + "\n")
+	}
+	return b.Bytes(), nil
+}
+
+// filename fills in the template, leaving out what Obsidian can't link to or
+// filesystems can't store.
+func (o Obsidian) filename(article Article, now time.Time) string {
+	template := o.Filename
+	if template == "" {
+		template = defaultFilename
+	}
+	published := ""
+	if article.Published != nil {
+		published = article.Published.Format("2006-01-02")
+	}
+	name := strings.NewReplacer(
+		"{{title}}", article.Title,
+		"{{feed}}", article.Feed,
+		"{{date}}", now.Format("2006-01-02"),
+		"{{time}}", now.Format("1504"),
+		"{{published}}", published,
+	).Replace(template)
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`\/:*?"<>|#^[]`, r) || r < ' ' {
+			return ' '
+		}
+		return r
+	}, name)
+	name = strings.Trim(strings.Join(strings.Fields(name), " "), ". ")
+	for utf8.RuneCountInString(name) > maxFilenameRunes {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return now.Format("2006-01-02 150405")
+	}
+	return name
+}
@@ -0,0 +1,177 @@
+package capture
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Org appends articles to an org-mode file as entries, in the spirit of an
+// org capture template: a heading to act on, the article's details in a
+// property drawer and its text under them.
+type Org struct {
+	// File is the org file entries are appended to, like ~/org/inbox.org.
+	File string `mapstructure:"file"`
+	// Keyword is the TODO keyword the heading starts with, empty for none.
+	Keyword string `mapstructure:"keyword"`
+	// Tags are put on every heading.
+	Tags []string `mapstructure:"tags"`
+}
+
+// orgTimestamp is how org writes inactive timestamps, which date an entry
+// without putting it on the agenda.
+const orgTimestamp = "[2006-01-02 Mon 15:04]"
+
+var orgTag = regexp.MustCompile(`^[[:alnum:]_@#%]+$`)
+
+// Validate checks the keyword and tags are ones org can read back.
+func (o Org) Validate() error {
+	if strings.ContainsAny(o.Keyword, " \t") {
+		return fmt.Errorf("capture: org keyword %q has spaces in it", o.Keyword)
+	}
+	for _, tag := range o.Tags {
+		if !orgTag.MatchString(tag) {
+			return fmt.Errorf("capture: %q isn't an org tag (letters, digits, _@#%% only)", tag)
+		}
+	}
+	return nil
+}
+
+// Append adds an article to the end of the org file, creating it if need be.
+func (o Org) Append(article Article, now time.Time) error {
+	path := expandHome(o.File)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	entry := o.entry(article, now)
+	// entries start on a line of their own, whatever the file ends with
+	if info, err := file.Stat(); err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := file.ReadAt(last, info.Size()-1); err == nil && last[0] != '\n' {
+			entry = "\n" + entry
+		}
+	}
+	_, err = file.WriteString(entry)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// entry is an article as an org entry.
+func (o Org) entry(article Article, now time.Time) string {
+	var b strings.Builder
+	heading := "* "
+	if o.Keyword != "" {
+		heading += o.Keyword + " "
+	}
+	heading += strings.Join(strings.Fields(article.Title), " ")
+	if len(o.Tags) > 0 {
+		heading += " :" + strings.Join(o.Tags, ":") + ":"
+	}
+	b.WriteString(heading + "\n")
+
+	b.WriteString(":PROPERTIES:\n")
+	property := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, ":%s: %s\n", name, value)
+		}
+	}
+	property("URL", article.Link)
+	property("FEED", article.Feed)
+	property("AUTHOR", article.Author)
+	if article.Published != nil {
+		property("PUBLISHED", article.Published.Format(orgTimestamp))
+	}
+	property("CAPTURED", now.Format(orgTimestamp))
+	b.WriteString(":END:\n")
+
+	if article.Link != "" {
+		fmt.Fprintf(&b, "[[%s][%s]]\n", article.Link, orgLinkText(article.Title))
+	}
+	if body := strings.TrimSpace(markdownToOrg(article.Markdown)); body != "" {
+		b.WriteString("\n" + body + "\n")
+	}
+	return b.String()
+}
+
+// orgLinkText keeps the brackets in a link's text from ending it early.
+func orgLinkText(text string) string {
+	return strings.NewReplacer("[", "(", "]", ")").Replace(strings.Join(strings.Fields(text), " "))
+}
+
+var (
+	mdFence     = regexp.MustCompile("^```+\\s*(\\S*)")
+	mdHeading   = regexp.MustCompile(`^#{1,6}\s+(.*?)\s*#*$`)
+	mdBullet    = regexp.MustCompile(`^(\s*)[-*+]\s+`)
+	mdRule      = regexp.MustCompile(`^\s*([-*_]\s*){3,}$`)
+	mdImage     = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)[^)]*\)`)
+	mdLink      = regexp.MustCompile(`\[([^\]]*)\]\(([^)\s]+)[^)]*\)`)
+	mdStrong    = regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*`)
+	mdEmphasis  = regexp.MustCompile(`(^|[^\w])_(\S(?:.*?\S)?)_($|[^\w])`)
+	mdCode      = regexp.MustCompile("`([^`]+)`")
+	mdEscape    = regexp.MustCompile(`\\([\\` + "`" + `*_{}\[\]()#+\-.!|>~])`)
+	orgHeadline = regexp.MustCompile(`^\*+\s`)
+)
+
+// markdownToOrg rewrites the markdown the reader converts articles to in
+// org's own markup: headings in bold (they'd be headings of the outline
+// otherwise), links, emphasis, code blocks and lists, so the entry reads
+// like any other in the file.
+func markdownToOrg(markdown string) string {
+	var out []string
+	inCode := false
+	for _, line := range strings.Split(markdown, "\n") {
+		if fence := mdFence.FindStringSubmatch(line); fence != nil {
+			if inCode {
+				out = append(out, "#+end_src")
+			} else if fence[1] != "" {
+				out = append(out, "#+begin_src "+fence[1])
+			} else {
+				out = append(out, "#+begin_example")
+			}
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			// org escapes lines inside blocks that it would read as its own
+			if strings.HasPrefix(line, "*") || strings.HasPrefix(line, "#+") {
+				line = "," + line
+			}
+			out = append(out, line)
+			continue
+		}
+
+		switch {
+		case mdRule.MatchString(line):
+			line = "-----"
+		case mdHeading.MatchString(line):
+			line = "*" + mdHeading.FindStringSubmatch(line)[1] + "*"
+		default:
+			line = mdBullet.ReplaceAllString(line, "$1- ")
+		}
+		line = mdImage.ReplaceAllString(line, "[[$2][$1]]")
+		line = mdLink.ReplaceAllString(line, "[[$2][$1]]")
+		line = mdStrong.ReplaceAllString(line, "*$1*")
+		line = mdEmphasis.ReplaceAllString(line, "$1/$2/$3")
+		line = mdCode.ReplaceAllString(line, "~$1~")
+		line = mdEscape.ReplaceAllString(line, "$1")
+		// a line that would start a heading of its own, or a comment, is
+		// nudged off the margin
+		if orgHeadline.MatchString(line) || strings.HasPrefix(line, "# ") || strings.HasPrefix(line, "#+") {
+			line = " " + line
+		}
+		out = append(out, line)
+	}
+	if inCode {
+		out = append(out, "#+end_src")
+	}
+	return strings.Join(out, "\n")
+}
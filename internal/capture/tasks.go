@@ -0,0 +1,139 @@
+package capture
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// taskTimeout bounds running Taskwarrior, in case it stops to ask something
+// despite being told not to.
+const taskTimeout = 10 * time.Second
+
+// createdTask picks the ID out of what `task add` says.
+var createdTask = regexp.MustCompile(`Created task (\d+)`)
+
+// taskDescription is what a task to read an article is called.
+func taskDescription(article Article) string {
+	description := "Read: " + strings.Join(strings.Fields(article.Title), " ")
+	if article.Link != "" {
+		description += " " + article.Link
+	}
+	return description
+}
+
+// Taskwarrior adds a task to read the article with the `task` command.
+type Taskwarrior struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Command runs Taskwarrior, "task" unless it's somewhere else.
+	Command string `mapstructure:"command"`
+	// Project is the project tasks are put in, if any.
+	Project string   `mapstructure:"project"`
+	Tags    []string `mapstructure:"tags"`
+}
+
+// Validate checks the tags are ones Taskwarrior takes.
+func (t Taskwarrior) Validate() error {
+	for _, tag := range t.Tags {
+		if tag == "" || strings.ContainsAny(tag, " \t") || strings.ContainsAny(tag[:1], "+-") {
+			return fmt.Errorf("capture: taskwarrior tag %q can't have spaces or start with + or -", tag)
+		}
+	}
+	if strings.ContainsAny(t.Project, " \t") {
+		return fmt.Errorf("capture: taskwarrior project %q can't have spaces", t.Project)
+	}
+	return nil
+}
+
+// Add creates the task, returning its ID as Taskwarrior gives it.
+func (t Taskwarrior) Add(ctx context.Context, article Article) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, taskTimeout)
+	defer cancel()
+
+	command := t.Command
+	if command == "" {
+		command = "task"
+	}
+	args := []string{"rc.confirmation=off", "rc.verbose=new-id", "add"}
+	if t.Project != "" {
+		args = append(args, "project:"+t.Project)
+	}
+	for _, tag := range t.Tags {
+		args = append(args, "+"+tag)
+	}
+	// everything after -- is the description, so a title can't read as
+	// attributes like due:
+	args = append(args, "--", taskDescription(article))
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, expandHome(command), args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if reason := strings.TrimSpace(stderr.String()); reason != "" {
+			return "", fmt.Errorf("taskwarrior: %s", reason)
+		}
+		return "", fmt.Errorf("taskwarrior: %s", err)
+	}
+	if id := createdTask.FindSubmatch(out); id != nil {
+		return fmt.Sprintf("task %s in Taskwarrior", id[1]), nil
+	}
+	return "Taskwarrior", nil
+}
+
+// TodoTxt appends a task to read the article to a todo.txt file.
+type TodoTxt struct {
+	File string `mapstructure:"file"`
+	// Projects and Contexts are added to every task, as +project and
+	// @context.
+	Projects []string `mapstructure:"projects"`
+	Contexts []string `mapstructure:"contexts"`
+}
+
+// Validate checks the projects and contexts are single words, as todo.txt
+// needs them to be.
+func (t TodoTxt) Validate() error {
+	for _, word := range append(append([]string{}, t.Projects...), t.Contexts...) {
+		if word == "" || strings.ContainsAny(word, " \t") {
+			return fmt.Errorf("capture: todo.txt project or context %q should be one word", word)
+		}
+	}
+	return nil
+}
+
+// Append adds the task as a line of the file, creating it if need be.
+func (t TodoTxt) Append(article Article, now time.Time) error {
+	path := expandHome(t.File)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	line := now.Format("2006-01-02") + " " + taskDescription(article)
+	for _, project := range t.Projects {
+		line += " +" + strings.TrimPrefix(project, "+")
+	}
+	for _, where := range t.Contexts {
+		line += " @" + strings.TrimPrefix(where, "@")
+	}
+	// tasks are a line each, whatever the file ends with
+	if info, err := file.Stat(); err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := file.ReadAt(last, info.Size()-1); err == nil && last[0] != '\n' {
+			line = "\n" + line
+		}
+	}
+	_, err = file.WriteString(line + "\n")
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
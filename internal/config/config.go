@@ -0,0 +1,715 @@
+// Package config locates, loads and saves the reader's settings, and knows
+// where the rest of its files live.
+package config
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// AppName names the config, data and cache directories.
+	AppName = "golang-rss-client"
+	// FileName is both the name viper searches for and the name of the file
+	// written when settings are changed from inside the reader.
+	FileName    = AppName + ".yml"
+	logFileName = AppName + ".log"
+)
+
+// Paths lists the directories searched for the config file, in order.
+func Paths() []string {
+	var paths []string
+	if runtime.GOOS != "windows" {
+		paths = append(paths, "/etc/golang-rss-client/")
+	}
+	paths = append(paths, "$HOME/golang-rss-client/")
+	// %APPDATA% on Windows, ~/Library/Application Support on macOS and
+	// $XDG_CONFIG_HOME elsewhere
+	if dir, err := os.UserConfigDir(); err == nil {
+		paths = append(paths, filepath.Join(dir, AppName))
+	}
+	return append(paths, ".")
+}
+
+// LogFilePath is where the log is written. Windows programs don't usually
+// litter the working directory, so there it goes under %LOCALAPPDATA%.
+func LogFilePath() string {
+	if runtime.GOOS == "windows" {
+		if dir, err := os.UserCacheDir(); err == nil {
+			dir = filepath.Join(dir, AppName)
+			if err := os.MkdirAll(dir, 0755); err == nil {
+				return filepath.Join(dir, logFileName)
+			}
+		}
+	}
+	return logFileName
+}
+
+// FilePath is where the config is saved: the config file that was loaded, or
+// the user's config directory if none was found on disk.
+func FilePath() string {
+	if used := viper.ConfigFileUsed(); used != "" {
+		return used
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return FileName
+	}
+	return filepath.Join(dir, AppName, FileName)
+}
+
+// Save persists the current viper settings back to FilePath.
+func Save() error {
+	path := FilePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return viper.WriteConfigAs(path)
+}
+
+// DataDir is where state worth keeping (like which items have been read)
+// lives: $XDG_DATA_HOME on unix, %APPDATA% on Windows and Application Support
+// on macOS.
+func DataDir() (string, error) {
+	var dir string
+	switch runtime.GOOS {
+	case "windows", "darwin":
+		configDir, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+		dir = configDir
+	default:
+		dir = os.Getenv("XDG_DATA_HOME")
+		if dir == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", err
+			}
+			dir = filepath.Join(home, ".local", "share")
+		}
+	}
+	dir = filepath.Join(dir, AppName)
+	return dir, os.MkdirAll(dir, 0755)
+}
+
+// CacheDir is where anything that can be safely thrown away goes.
+func CacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, AppName)
+	return dir, os.MkdirAll(dir, 0755)
+}
+
+// Load sets up defaults, config file locations and environment variables,
+// then reads the config file if there is one.
+func Load() error {
+	// defaults for color in reader
+	viper.SetDefault("accent", "33")
+	// empty means the theme's
+	viper.SetDefault("textColor", "")
+	viper.SetDefault("backgroundColor", "")
+	viper.SetDefault("theme", "dark")
+	viper.SetDefault("horzPadding", 2)
+	viper.SetDefault("vertPadding", 0)
+	viper.SetDefault("fetchTimeout", 15)
+	viper.SetDefault("maxConcurrentFetches", 5)
+	viper.SetDefault("lowBandwidth", false)
+	viper.SetDefault("accessible", false)
+	viper.SetDefault("asciiOnly", false)
+	viper.SetDefault("setWindowTitle", false)
+	viper.SetDefault("maxItems", 0)
+	viper.SetDefault("collapseDuplicates", true)
+	viper.SetDefault("archive", false)
+	viper.SetDefault("archiveHistory", 100)
+	defaultFeedUrls := []string{"https://github.com/homielabs.atom"}
+	viper.SetDefault("feedUrls", defaultFeedUrls)
+	viper.SetDefault("pausedFeeds", []string{})
+	viper.SetDefault("notifyFeeds", []string{})
+	viper.SetDefault("priorityFeeds", []string{})
+	viper.SetDefault("followedAuthors", []string{})
+	viper.SetDefault("refreshInterval", 0)
+	viper.SetDefault("priorityRefreshInterval", 5)
+	viper.SetDefault("updateMovedFeeds", false)
+	viper.SetDefault("quietHours", []map[string]string{})
+	viper.SetDefault("quietRefreshInterval", 60)
+	viper.SetDefault("importOpml", "")
+	viper.SetDefault("timezone", "")
+	viper.SetDefault("feedTimezones", []map[string]string{})
+	viper.SetDefault("httpProxy", "")
+	viper.SetDefault("httpsProxy", "")
+	viper.SetDefault("socks5Proxy", "")
+	viper.SetDefault("feedProxies", []map[string]string{})
+	viper.SetDefault("installCommands", []map[string]string{})
+	viper.SetDefault("scoreRules", []map[string]interface{}{})
+	viper.SetDefault("browserCommand", "")
+	viper.SetDefault("downloadDir", "")
+	viper.SetDefault("player", "mpv")
+	viper.SetDefault("expireUnread", []map[string]interface{}{})
+	viper.SetDefault("languages", []string{})
+	viper.SetDefault("feedLanguages", []map[string]interface{}{})
+	viper.SetDefault("mutedLanguages", []string{})
+	viper.SetDefault("pressReleases.action", "flag")
+	viper.SetDefault("pressReleases.minFeeds", 2)
+	viper.SetDefault("paywall.domains", []string{})
+	viper.SetDefault("paywall.archiveUrl", "")
+	viper.SetDefault("markReadOnScroll", false)
+	viper.SetDefault("markReadAfter", 0)
+	viper.SetDefault("feedTitles", []map[string]string{})
+	viper.SetDefault("desktopNotifications", true)
+	viper.SetDefault("notifications.enabled", false)
+	viper.SetDefault("notifications.feeds", []map[string]interface{}{})
+	viper.SetDefault("push", []map[string]interface{}{})
+	viper.SetDefault("share", []map[string]interface{}{})
+	viper.SetDefault("capture.org.file", "")
+	viper.SetDefault("capture.org.keyword", "TODO")
+	viper.SetDefault("capture.org.tags", []string{})
+	viper.SetDefault("capture.obsidian.folder", "")
+	viper.SetDefault("capture.obsidian.filename", "{{date}} {{title}}")
+	viper.SetDefault("capture.obsidian.tags", []string{})
+	viper.SetDefault("capture.joplin.url", "http://localhost:41184")
+	viper.SetDefault("capture.joplin.token", "")
+	viper.SetDefault("capture.joplin.notebook", "")
+	viper.SetDefault("capture.joplin.tags", []string{})
+	viper.SetDefault("capture.taskwarrior.enabled", false)
+	viper.SetDefault("capture.taskwarrior.command", "task")
+	viper.SetDefault("capture.taskwarrior.project", "")
+	viper.SetDefault("capture.taskwarrior.tags", []string{})
+	viper.SetDefault("capture.todotxt.file", "")
+	viper.SetDefault("capture.todotxt.projects", []string{})
+	viper.SetDefault("capture.todotxt.contexts", []string{})
+	viper.SetDefault("readLater.service", "")
+	viper.SetDefault("readingLog.repo", "")
+	viper.SetDefault("readingLog.file", "{{year}}/{{date}}.md")
+	viper.SetDefault("readingLog.commitEvery", 15)
+	viper.SetDefault("readingLog.push", false)
+	viper.SetDefault("backend", "direct")
+	viper.SetDefault("greader.url", "")
+	viper.SetDefault("greader.username", "")
+	viper.SetDefault("greader.password", "")
+	viper.SetDefault("greader.appId", "")
+	viper.SetDefault("greader.appKey", "")
+	viper.SetDefault("greader.syncEvery", 5)
+	viper.SetDefault("miniflux.url", "")
+	viper.SetDefault("miniflux.token", "")
+	viper.SetDefault("miniflux.entries", 100)
+	viper.SetDefault("nextcloud.url", "")
+	viper.SetDefault("nextcloud.username", "")
+	viper.SetDefault("nextcloud.password", "")
+	viper.SetDefault("nextcloud.passwordCommand", "")
+	viper.SetDefault("nextcloud.items", 100)
+	viper.SetDefault("keys", map[string][]string{})
+
+	// config file locations
+	viper.SetConfigName(FileName)
+	viper.SetConfigType("yaml")
+	for _, path := range Paths() {
+		viper.AddConfigPath(path)
+	}
+
+	// read env vars
+	viper.SetEnvPrefix("golangrssclient")
+	viper.BindEnv("accent")
+	viper.BindEnv("textColor")
+	viper.BindEnv("backgroundColor")
+	viper.BindEnv("horzPadding")
+	viper.BindEnv("vertPadding")
+
+	if err := viper.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			// Config file was found but another error was produced
+			return err
+		}
+		// Config file not found; ignore error since we have defaults
+		log.Println("Found no configs on disk")
+	}
+	return nil
+}
+
+// Reload reads the config from scratch, so that edits made to the file since
+// it was loaded take effect. Settings changed from inside the program since
+// then were saved to the file already; flags have to be set again.
+func Reload() error {
+	viper.Reset()
+	return Load()
+}
+
+// Set reads a list of strings from the config as a set.
+func Set(key string) map[string]bool {
+	set := make(map[string]bool)
+	for _, value := range viper.GetStringSlice(key) {
+		set[value] = true
+	}
+	return set
+}
+
+// feedLists are the config's lists of feed URLs.
+var feedLists = []string{"feedUrls", "pausedFeeds", "notifyFeeds", "priorityFeeds"}
+
+// feedSections are the config's lists of per-feed settings, each entry
+// naming its feed by url.
+var feedSections = []string{
+	"feedTitles", "feedColors", "feedTimezones", "feedProxies", "feedLanguages",
+	"expireUnread", "installCommands", "notifications.feeds", "markdown.feeds",
+}
+
+// rewriteFeedURL points everything the config has for a feed at a new URL,
+// or drops it all if newUrl is empty: the feed lists, groups and per-feed
+// sections.
+func rewriteFeedURL(oldUrl string, newUrl string) error {
+	rewrite := func(urls []string) []string {
+		kept := []string{}
+		for _, u := range urls {
+			if u == oldUrl {
+				u = newUrl
+			}
+			if u != "" && !contains(kept, u) {
+				kept = append(kept, u)
+			}
+		}
+		return kept
+	}
+	for _, key := range feedLists {
+		viper.Set(key, rewrite(viper.GetStringSlice(key)))
+	}
+	groups := Groups()
+	for name, members := range groups {
+		groups[name] = rewrite(members)
+	}
+	viper.Set("groups", groups)
+
+	for _, key := range feedSections {
+		var entries []map[string]interface{}
+		if err := viper.UnmarshalKey(key, &entries); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		changed := false
+		kept := []map[string]interface{}{}
+		for _, entry := range entries {
+			if entry["url"] == oldUrl {
+				changed = true
+				if newUrl == "" {
+					continue
+				}
+				entry["url"] = newUrl
+			}
+			kept = append(kept, entry)
+		}
+		if changed {
+			setKey(key, kept)
+		}
+	}
+	return nil
+}
+
+// setKey sets a key, one inside a section by setting the whole section:
+// viper would otherwise take the key for all there is to the section.
+func setKey(key string, value interface{}) {
+	dot := strings.LastIndex(key, ".")
+	if dot < 0 {
+		viper.Set(key, value)
+		return
+	}
+	section := make(map[string]interface{})
+	for name, setting := range viper.GetStringMap(key[:dot]) {
+		section[name] = setting
+	}
+	section[key[dot+1:]] = value
+	viper.Set(key[:dot], section)
+}
+
+// ReplaceFeedURL swaps a feed's URL for a new one everywhere the config
+// mentions it, then saves the config.
+func ReplaceFeedURL(oldUrl string, newUrl string) error {
+	if err := rewriteFeedURL(oldUrl, newUrl); err != nil {
+		return err
+	}
+	return Save()
+}
+
+// AddFeedURL subscribes to a feed, saving the config.
+func AddFeedURL(feedUrl string) error {
+	viper.Set("feedUrls", append(viper.GetStringSlice("feedUrls"), feedUrl))
+	return Save()
+}
+
+// RemoveFeedURL unsubscribes from a feed, dropping it everywhere the config
+// mentions it, then saves the config.
+func RemoveFeedURL(feedUrl string) error {
+	if err := rewriteFeedURL(feedUrl, ""); err != nil {
+		return err
+	}
+	return Save()
+}
+
+// FeedTitles are the titles feeds have been renamed to, by URL. Entries that
+// can't be read are ignored.
+func FeedTitles() map[string]string {
+	var entries []struct {
+		Url   string `mapstructure:"url"`
+		Title string `mapstructure:"title"`
+	}
+	viper.UnmarshalKey("feedTitles", &entries)
+	titles := make(map[string]string)
+	for _, entry := range entries {
+		titles[entry.Url] = entry.Title
+	}
+	return titles
+}
+
+// SetFeedTitle renames a feed, or goes back to the feed's own title if title
+// is empty, then saves the config.
+func SetFeedTitle(feedUrl string, title string) error {
+	titles := FeedTitles()
+	if title == "" {
+		delete(titles, feedUrl)
+	} else {
+		titles[feedUrl] = title
+	}
+	setFeedTitles(titles)
+	return Save()
+}
+
+func setFeedTitles(titles map[string]string) {
+	var urls []string
+	for feedUrl := range titles {
+		urls = append(urls, feedUrl)
+	}
+	// keep the file stable from one save to the next
+	sort.Strings(urls)
+	entries := []map[string]string{}
+	for _, feedUrl := range urls {
+		entries = append(entries, map[string]string{"url": feedUrl, "title": titles[feedUrl]})
+	}
+	viper.Set("feedTitles", entries)
+}
+
+// MergeFeeds adds to the subscriptions and groups, skipping any already
+// there, and saves the config. It reports how many feeds were new.
+func MergeFeeds(feedUrls []string, groups map[string][]string) (int, error) {
+	subscribed := viper.GetStringSlice("feedUrls")
+	added := 0
+	for _, feedUrl := range feedUrls {
+		if !contains(subscribed, feedUrl) {
+			subscribed = append(subscribed, feedUrl)
+			added++
+		}
+	}
+	viper.Set("feedUrls", subscribed)
+
+	merged := Groups()
+	for name, members := range groups {
+		// viper would lowercase the name on the next load anyway
+		name = strings.ToLower(name)
+		for _, feedUrl := range members {
+			if !contains(merged[name], feedUrl) {
+				merged[name] = append(merged[name], feedUrl)
+			}
+		}
+	}
+	viper.Set("groups", merged)
+	return added, Save()
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// FeedColors reads the `feedColors` section, a list of feed URLs with the
+// accent color each should use, as a map of URL to color.
+func FeedColors() (map[string]string, error) {
+	var entries []struct {
+		Url   string `mapstructure:"url"`
+		Color string `mapstructure:"color"`
+	}
+	if err := viper.UnmarshalKey("feedColors", &entries); err != nil {
+		return nil, err
+	}
+	colors := make(map[string]string)
+	for _, entry := range entries {
+		colors[entry.Url] = entry.Color
+	}
+	return colors, nil
+}
+
+// FeedProxies reads the `feedProxies` section, a list of feed URLs with the
+// proxy each is fetched through, as a map of URL to proxy.
+func FeedProxies() (map[string]string, error) {
+	var entries []struct {
+		Url   string `mapstructure:"url"`
+		Proxy string `mapstructure:"proxy"`
+	}
+	if err := viper.UnmarshalKey("feedProxies", &entries); err != nil {
+		return nil, err
+	}
+	proxies := make(map[string]string)
+	for _, entry := range entries {
+		proxies[entry.Url] = entry.Proxy
+	}
+	return proxies, nil
+}
+
+// InstallCommands reads the `installCommands` section, a list of feed URLs of
+// GitHub releases with the command that installs one, as a map of URL to
+// command.
+func InstallCommands() (map[string]string, error) {
+	var entries []struct {
+		Url     string `mapstructure:"url"`
+		Command string `mapstructure:"command"`
+	}
+	if err := viper.UnmarshalKey("installCommands", &entries); err != nil {
+		return nil, err
+	}
+	commands := make(map[string]string)
+	for _, entry := range entries {
+		if strings.TrimSpace(entry.Command) == "" {
+			return nil, fmt.Errorf("installCommands: %s has no command", entry.Url)
+		}
+		commands[entry.Url] = entry.Command
+	}
+	return commands, nil
+}
+
+// Notifications reads the `notifications` section: whether every feed's new
+// items are notified about, and the feeds whose are or aren't anyway, as a
+// map of URL to whether they are.
+func Notifications() (bool, map[string]bool, error) {
+	var entries []struct {
+		Url     string `mapstructure:"url"`
+		Enabled bool   `mapstructure:"enabled"`
+	}
+	if err := viper.UnmarshalKey("notifications.feeds", &entries); err != nil {
+		return false, nil, err
+	}
+	feeds := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.Url == "" {
+			return false, nil, fmt.Errorf("notifications: a feed has no url")
+		}
+		feeds[entry.Url] = entry.Enabled
+	}
+	return viper.GetBool("notifications.enabled"), feeds, nil
+}
+
+// NotifyMinScore reads `notifications.minScore`, the score from which new
+// items are notified about whichever feed they're in, and whether it's set.
+func NotifyMinScore() (float64, bool) {
+	return viper.GetFloat64("notifications.minScore"), viper.IsSet("notifications.minScore")
+}
+
+// TimeWindow is a daily stretch of time, in local time. It may wrap past
+// midnight, as in 23:00–07:00.
+type TimeWindow struct {
+	// From and To are offsets from midnight
+	From time.Duration
+	To   time.Duration
+}
+
+// Contains reports whether t falls inside the window.
+func (w TimeWindow) Contains(t time.Time) bool {
+	since := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	if w.From <= w.To {
+		return since >= w.From && since < w.To
+	}
+	return since >= w.From || since < w.To
+}
+
+// QuietHours reads the `quietHours` section, a list of from/to times like
+// "23:00".
+func QuietHours() ([]TimeWindow, error) {
+	var entries []struct {
+		From string `mapstructure:"from"`
+		To   string `mapstructure:"to"`
+	}
+	if err := viper.UnmarshalKey("quietHours", &entries); err != nil {
+		return nil, err
+	}
+	var windows []TimeWindow
+	for _, entry := range entries {
+		from, err := time.Parse("15:04", entry.From)
+		if err != nil {
+			return nil, fmt.Errorf("quietHours: %w", err)
+		}
+		to, err := time.Parse("15:04", entry.To)
+		if err != nil {
+			return nil, fmt.Errorf("quietHours: %w", err)
+		}
+		windows = append(windows, TimeWindow{
+			From: time.Duration(from.Hour())*time.Hour + time.Duration(from.Minute())*time.Minute,
+			To:   time.Duration(to.Hour())*time.Hour + time.Duration(to.Minute())*time.Minute,
+		})
+	}
+	return windows, nil
+}
+
+// DownloadDir is where enclosures are downloaded: `downloadDir`, or else the
+// Downloads folder in the home directory.
+func DownloadDir() (string, error) {
+	dir := viper.GetString("downloadDir")
+	home, err := os.UserHomeDir()
+	if dir == "" {
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, "Downloads"), nil
+	}
+	if err == nil && (dir == "~" || strings.HasPrefix(dir, "~/")) {
+		dir = filepath.Join(home, strings.TrimPrefix(dir, "~"))
+	}
+	return dir, nil
+}
+
+// Timezone is the configured display time zone, an IANA name like
+// "Europe/Berlin". Left empty, it's the system's.
+func Timezone() (*time.Location, error) {
+	name := viper.GetString("timezone")
+	if name == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("timezone: %w", err)
+	}
+	return loc, nil
+}
+
+// Themes are the built-in themes: glamour's dark, light and notty styles, and
+// auto, which is dark or light to suit the terminal's background.
+var Themes = []string{"dark", "light", "auto", "notty"}
+
+// Theme reads `theme`, one of Themes or the path to a glamour JSON style.
+func Theme() (string, error) {
+	theme := viper.GetString("theme")
+	if contains(Themes, theme) {
+		return theme, nil
+	}
+	if _, err := os.Stat(theme); err != nil {
+		return "", fmt.Errorf("theme: %q is neither one of %s nor a glamour style file", theme, strings.Join(Themes, ", "))
+	}
+	return theme, nil
+}
+
+// FeedTimezones reads the `feedTimezones` section, a list of feed URLs with
+// the time zone their dates are in when they don't say, as a map of URL to
+// location.
+func FeedTimezones() (map[string]*time.Location, error) {
+	var entries []struct {
+		Url      string `mapstructure:"url"`
+		Timezone string `mapstructure:"timezone"`
+	}
+	if err := viper.UnmarshalKey("feedTimezones", &entries); err != nil {
+		return nil, err
+	}
+	locations := make(map[string]*time.Location)
+	for _, entry := range entries {
+		loc, err := time.LoadLocation(entry.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("feedTimezones: %s: %w", entry.Url, err)
+		}
+		locations[entry.Url] = loc
+	}
+	return locations, nil
+}
+
+// PressReleaseActions are what can be done with press releases: nothing,
+// marking them, keeping one copy, or marking every copy read.
+var PressReleaseActions = []string{"off", "flag", "collapse", "mute"}
+
+// PressReleases reads the `pressReleases` section: what's done with items
+// whose text turns up in a few feeds, and in how many feeds it has to.
+func PressReleases() (string, int, error) {
+	action := viper.GetString("pressReleases.action")
+	if !contains(PressReleaseActions, action) {
+		return "", 0, fmt.Errorf("pressReleases: action %q isn't one of %s", action, strings.Join(PressReleaseActions, ", "))
+	}
+	minFeeds := viper.GetInt("pressReleases.minFeeds")
+	if minFeeds < 2 {
+		return "", 0, fmt.Errorf("pressReleases: minFeeds should be at least 2, not %d", minFeeds)
+	}
+	return action, minFeeds, nil
+}
+
+// Paywall reads the `paywall` section: the domains behind paywalls, and the
+// archive service URL their articles are opened through, if any.
+func Paywall() (map[string]bool, string, error) {
+	domains := make(map[string]bool)
+	for _, domain := range viper.GetStringSlice("paywall.domains") {
+		domains[strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "www.")] = true
+	}
+	archiveUrl := viper.GetString("paywall.archiveUrl")
+	if archiveUrl == "" {
+		return domains, "", nil
+	}
+	parsed, err := url.Parse(strings.ReplaceAll(archiveUrl, "{{url}}", "x"))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || !strings.Contains(archiveUrl, "{{url}}") {
+		return nil, "", fmt.Errorf("paywall: archiveUrl %q should be an http(s) URL with {{url}} in it", archiveUrl)
+	}
+	return domains, archiveUrl, nil
+}
+
+// FeedLanguages reads the `feedLanguages` section, a list of feed URLs with
+// the languages their items are read in, as a map of URL to a set of
+// language codes.
+func FeedLanguages() (map[string]map[string]bool, error) {
+	var entries []struct {
+		Url       string   `mapstructure:"url"`
+		Languages []string `mapstructure:"languages"`
+	}
+	if err := viper.UnmarshalKey("feedLanguages", &entries); err != nil {
+		return nil, err
+	}
+	languages := make(map[string]map[string]bool)
+	for _, entry := range entries {
+		if len(entry.Languages) == 0 {
+			return nil, fmt.Errorf("feedLanguages: %s: no languages given", entry.Url)
+		}
+		languages[entry.Url] = make(map[string]bool)
+		for _, language := range entry.Languages {
+			languages[entry.Url][strings.ToLower(language)] = true
+		}
+	}
+	return languages, nil
+}
+
+// ExpireAfter reads the `expireUnread` section, a list of feed URLs with the
+// number of days after which their items count as read, as a map of URL to
+// age.
+func ExpireAfter() (map[string]time.Duration, error) {
+	var entries []struct {
+		Url  string `mapstructure:"url"`
+		Days int    `mapstructure:"days"`
+	}
+	if err := viper.UnmarshalKey("expireUnread", &entries); err != nil {
+		return nil, err
+	}
+	expiry := make(map[string]time.Duration)
+	for _, entry := range entries {
+		if entry.Days <= 0 {
+			return nil, fmt.Errorf("expireUnread: %s: days must be positive", entry.Url)
+		}
+		expiry[entry.Url] = time.Duration(entry.Days) * 24 * time.Hour
+	}
+	return expiry, nil
+}
+
+// Groups reads the `groups` section, which names groups (or categories) of
+// feeds: a map of group name to feed URLs. viper lowercases the names.
+func Groups() map[string][]string {
+	return viper.GetStringMapStringSlice("groups")
+}
@@ -0,0 +1,188 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestTimeWindowContains(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 3, 1, hour, minute, 30, 0, time.Local)
+	}
+	daytime := TimeWindow{From: 9 * time.Hour, To: 17 * time.Hour}
+	overnight := TimeWindow{From: 23 * time.Hour, To: 7 * time.Hour}
+	tests := []struct {
+		name   string
+		window TimeWindow
+		t      time.Time
+		want   bool
+	}{
+		{"inside", daytime, at(12, 0), true},
+		{"at the start", daytime, at(9, 0), true},
+		{"just before the end", daytime, at(16, 59), true},
+		{"at the end", daytime, at(17, 0), false},
+		{"before", daytime, at(8, 59), false},
+		{"overnight before midnight", overnight, at(23, 30), true},
+		{"overnight at midnight", overnight, at(0, 0), true},
+		{"overnight after midnight", overnight, at(6, 59), true},
+		{"overnight at the end", overnight, at(7, 0), false},
+		{"overnight outside", overnight, at(12, 0), false},
+		{"overnight just before the start", overnight, at(22, 59), false},
+		{"empty", TimeWindow{From: 8 * time.Hour, To: 8 * time.Hour}, at(8, 0), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.window.Contains(test.t); got != test.want {
+				t.Errorf("%v–%v contains %s = %v, want %v", test.window.From, test.window.To, test.t.Format("15:04"), got, test.want)
+			}
+		})
+	}
+}
+
+func TestQuietHours(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []map[string]interface{}
+		want    []TimeWindow
+		wantErr bool
+	}{
+		{"none", nil, nil, false},
+		{
+			"windows",
+			[]map[string]interface{}{{"from": "23:00", "to": "07:30"}, {"from": "12:15", "to": "13:00"}},
+			[]TimeWindow{{From: 23 * time.Hour, To: 7*time.Hour + 30*time.Minute}, {From: 12*time.Hour + 15*time.Minute, To: 13 * time.Hour}},
+			false,
+		},
+		{"bad from", []map[string]interface{}{{"from": "11pm", "to": "07:00"}}, nil, true},
+		{"bad to", []map[string]interface{}{{"from": "23:00", "to": "25:00"}}, nil, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer viper.Reset()
+			if test.entries != nil {
+				viper.Set("quietHours", test.entries)
+			}
+			got, err := QuietHours()
+			if (err != nil) != test.wantErr {
+				t.Fatalf("QuietHours() error = %v, want error %v", err, test.wantErr)
+			}
+			if len(got) != len(test.want) {
+				t.Fatalf("QuietHours() = %v, want %v", got, test.want)
+			}
+			for i := range got {
+				if got[i] != test.want[i] {
+					t.Errorf("window %d is %v, want %v", i, got[i], test.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRewriteFeedURL(t *testing.T) {
+	const yml = `
+feedUrls: [http://old/feed, http://new/feed, http://other/feed]
+pausedFeeds: [http://old/feed]
+groups:
+  tech: [http://old/feed, http://other/feed]
+feedTitles:
+  - url: http://old/feed
+    title: Old
+  - url: http://other/feed
+    title: Other
+notifications:
+  enabled: true
+  feeds:
+    - url: http://old/feed
+      enabled: false
+markdown:
+  plugins: [table]
+  feeds:
+    - url: http://old/feed
+      display: plain
+`
+	tests := []struct {
+		name   string
+		newUrl string
+		want   map[string]interface{}
+	}{
+		{
+			"moved",
+			"http://new/feed",
+			map[string]interface{}{
+				"feedUrls":    []string{"http://new/feed", "http://other/feed"},
+				"pausedFeeds": []string{"http://new/feed"},
+				"groups":      map[string][]string{"tech": {"http://new/feed", "http://other/feed"}},
+				"feedTitles": []map[string]interface{}{
+					{"url": "http://new/feed", "title": "Old"},
+					{"url": "http://other/feed", "title": "Other"},
+				},
+				"notifications.feeds": []map[string]interface{}{{"url": "http://new/feed", "enabled": false}},
+				"markdown.feeds":      []map[string]interface{}{{"url": "http://new/feed", "display": "plain"}},
+			},
+		},
+		{
+			"removed",
+			"",
+			map[string]interface{}{
+				"feedUrls":            []string{"http://new/feed", "http://other/feed"},
+				"pausedFeeds":         []string{},
+				"groups":              map[string][]string{"tech": {"http://other/feed"}},
+				"feedTitles":          []map[string]interface{}{{"url": "http://other/feed", "title": "Other"}},
+				"notifications.feeds": []map[string]interface{}{},
+				"markdown.feeds":      []map[string]interface{}{},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer viper.Reset()
+			viper.SetConfigType("yaml")
+			if err := viper.ReadConfig(strings.NewReader(yml)); err != nil {
+				t.Fatal(err)
+			}
+			if err := rewriteFeedURL("http://old/feed", test.newUrl); err != nil {
+				t.Fatal(err)
+			}
+
+			for key, want := range test.want {
+				var got interface{}
+				switch want.(type) {
+				case []string:
+					got = viper.GetStringSlice(key)
+				case map[string][]string:
+					got = viper.GetStringMapStringSlice(key)
+				default:
+					var entries []map[string]interface{}
+					if err := viper.UnmarshalKey(key, &entries); err != nil {
+						t.Fatal(err)
+					}
+					if entries == nil {
+						entries = []map[string]interface{}{}
+					}
+					got = entries
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("%s = %v, want %v", key, got, want)
+				}
+			}
+			// the rest of a section is kept when one of its keys is set
+			if !viper.GetBool("notifications.enabled") {
+				t.Error("notifications.enabled = false, want it kept")
+			}
+			all, feeds, err := Notifications()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if enabled, ok := feeds[test.newUrl]; !all || enabled || ok != (test.newUrl != "") {
+				t.Errorf("Notifications() = %v, %v, want every feed but the moved one notified about", all, feeds)
+			}
+			if got := viper.GetStringSlice("markdown.plugins"); !reflect.DeepEqual(got, []string{"table"}) {
+				t.Errorf("markdown.plugins = %v, want it kept", got)
+			}
+		})
+	}
+}
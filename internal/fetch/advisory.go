@@ -0,0 +1,102 @@
+package fetch
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// SeverityKey is set in an item's Custom map to how severe the security
+// advisory it is says it is: "low", "medium", "high" or "critical". CVSSKey
+// is set to its CVSS base score, like "9.8", when it gives one. Items that
+// aren't advisories (don't name a CVE or GHSA) have neither.
+const (
+	SeverityKey = "golang-rss-client:severity"
+	CVSSKey     = "golang-rss-client:cvss"
+)
+
+// Severities are the severities an advisory can have, least severe first.
+var Severities = []string{"low", "medium", "high", "critical"}
+
+// advisoryID matches the IDs advisories go by: CVE-2021-44228 or
+// GHSA-jfh8-c2jp-5v3q.
+var advisoryID = regexp.MustCompile(`(?i)\bCVE-\d{4}-\d{4,}\b|\bGHSA(?:-[23456789cfghjmpqrvwx]{4}){3}\b`)
+
+// cvssScore matches a CVSS base score, as "CVSS v3.1 Base Score: 9.8",
+// "CVSS: 7.5" or "Base score 5.3", even at the end of a sentence. The version
+// of a vector (CVSS:3.1/AV:N/…) isn't taken for a score.
+var cvssScore = regexp.MustCompile(`(?i)(?:\bCVSS(?:[:\s]*v?[234](?:\.[01])?)?|\bbase score)[^0-9\n/]{0,30}?\b(10(?:\.0)?|\d\.\d)(?:[^/\d.]|\.(?:[^/\d]|$)|$)`)
+
+// severityWord matches a severity as advisories write it: "Severity: High",
+// "high severity", "9.8 CRITICAL" or a title starting "[Moderate]".
+var severityWord = regexp.MustCompile(`(?i)\bseverity(?:\s+level)?\s*[:=]?\s*(critical|high|moderate|medium|low)\b|\b(critical|high|moderate|medium|low)\s+severity\b|\b(?:10(?:\.0)?|\d\.\d)\s+(critical|high|medium|low)\b|^\s*\[(critical|high|moderate|medium|low)\]`)
+
+// SeverityRank is where a severity comes in Severities, or -1 for none.
+func SeverityRank(severity string) int {
+	for i, s := range Severities {
+		if s == severity {
+			return i
+		}
+	}
+	return -1
+}
+
+// scoreSeverity is the severity CVSS v3 gives a base score.
+func scoreSeverity(score float64) string {
+	switch {
+	case score >= 9:
+		return "critical"
+	case score >= 7:
+		return "high"
+	case score >= 4:
+		return "medium"
+	case score > 0:
+		return "low"
+	}
+	return ""
+}
+
+// rateAdvisories notes the severity and CVSS score of each of a feed's items
+// that's a security advisory, from its title and text. A severity the
+// advisory states outranks the one its score would give, as vendors rate
+// for their own products.
+func rateAdvisories(feed *gofeed.Feed) {
+	for _, item := range feed.Items {
+		text := item.Title + "\n" + bodyText(item)
+		if !advisoryID.MatchString(text + "\n" + item.Link) {
+			continue
+		}
+		var score string
+		severity := ""
+		if match := cvssScore.FindStringSubmatch(text); match != nil {
+			score = match[1]
+			if value, err := strconv.ParseFloat(score, 64); err == nil {
+				severity = scoreSeverity(value)
+			}
+		}
+		if match := severityWord.FindStringSubmatch(text); match != nil {
+			for _, word := range match[1:] {
+				if word == "" {
+					continue
+				}
+				severity = strings.ToLower(word)
+				if severity == "moderate" {
+					severity = "medium"
+				}
+				break
+			}
+		}
+		if severity == "" {
+			continue
+		}
+		if item.Custom == nil {
+			item.Custom = make(map[string]string)
+		}
+		item.Custom[SeverityKey] = severity
+		if score != "" {
+			item.Custom[CVSSKey] = score
+		}
+	}
+}
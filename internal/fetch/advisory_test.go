@@ -0,0 +1,84 @@
+package fetch
+
+import (
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestRateAdvisories(t *testing.T) {
+	tests := []struct {
+		name         string
+		item         gofeed.Item
+		wantSeverity string
+		wantCVSS     string
+	}{
+		{
+			"score gives the severity",
+			gofeed.Item{Title: "CVE-2021-44228 in log4j", Description: "CVSS v3.1 Base Score: 10.0"},
+			"critical", "10.0",
+		},
+		{
+			"stated severity outranks the score",
+			gofeed.Item{Title: "CVE-2022-0001", Description: "CVSS: 9.1. Severity: Moderate"},
+			"medium", "9.1",
+		},
+		{
+			"severity word after the score",
+			gofeed.Item{Title: "GHSA-jfh8-c2jp-5v3q", Description: "Base score 7.5 HIGH"},
+			"high", "7.5",
+		},
+		{
+			"bracketed severity",
+			gofeed.Item{Title: "[Low] CVE-2023-12345: info leak"},
+			"low", "",
+		},
+		{
+			"vector version isn't a score",
+			gofeed.Item{Title: "CVE-2023-12345", Description: "CVSS:3.1/AV:N/AC:L, score 5.3, medium severity"},
+			"medium", "",
+		},
+		{
+			"ID only in the link",
+			gofeed.Item{Title: "Advisory", Link: "https://nvd.nist.gov/vuln/detail/CVE-2024-3094", Description: "CVSS 4.0: 6.1"},
+			"medium", "6.1",
+		},
+		{
+			"no rating",
+			gofeed.Item{Title: "CVE-2024-3094", Description: "Details to follow"},
+			"", "",
+		},
+		{
+			"not an advisory",
+			gofeed.Item{Title: "Release 1.2", Description: "Severity: High, CVSS 9.8"},
+			"", "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			item := test.item
+			rateAdvisories(&gofeed.Feed{Items: []*gofeed.Item{&item}})
+			if got := item.Custom[SeverityKey]; got != test.wantSeverity {
+				t.Errorf("severity %q, want %q", got, test.wantSeverity)
+			}
+			if got := item.Custom[CVSSKey]; got != test.wantCVSS {
+				t.Errorf("CVSS %q, want %q", got, test.wantCVSS)
+			}
+		})
+	}
+}
+
+func TestScoreSeverity(t *testing.T) {
+	tests := []struct {
+		score float64
+		want  string
+	}{
+		{0, ""}, {0.1, "low"}, {3.9, "low"}, {4, "medium"}, {6.9, "medium"},
+		{7, "high"}, {8.9, "high"}, {9, "critical"}, {10, "critical"},
+	}
+	for _, test := range tests {
+		if got := scoreSeverity(test.score); got != test.want {
+			t.Errorf("scoreSeverity(%v) = %q, want %q", test.score, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,350 @@
+package fetch
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// bookmarksTitle is what the feed of bookmarks is called.
+const bookmarksTitle = "Saved from browser"
+
+// chromiumEpoch is where Chromium's timestamps count from, in seconds before
+// the Unix epoch: 1601-01-01.
+const chromiumEpoch = 11644473600
+
+// chromiumDirs are where each Chromium-based browser keeps its profiles,
+// under the user config directory (the local app data one on Windows).
+var chromiumDirs = map[string]map[string]string{
+	"chromium": {"linux": "chromium", "darwin": "Chromium", "windows": `Chromium\User Data`},
+	"chrome":   {"linux": "google-chrome", "darwin": "Google/Chrome", "windows": `Google\Chrome\User Data`},
+	"brave":    {"linux": "BraveSoftware/Brave-Browser", "darwin": "BraveSoftware/Brave-Browser", "windows": `BraveSoftware\Brave-Browser\User Data`},
+}
+
+// bookmark is a bookmark in a folder being watched.
+type bookmark struct {
+	id    string
+	title string
+	url   string
+	added time.Time
+}
+
+// bookmarksRead are the bookmarks last read from each file, and when the
+// file was changed then, so an unchanged file isn't read again.
+var bookmarksRead = struct {
+	sync.Mutex
+	files map[string]bookmarksFile
+}{files: make(map[string]bookmarksFile)}
+
+type bookmarksFile struct {
+	modified  time.Time
+	folder    string
+	bookmarks []bookmark
+}
+
+// readBookmarks is a feed of the bookmarks in a folder of a browser's,
+// newest first, for URLs like bookmarks:firefox?folder=Read%20later. The
+// browser's file is found in its usual place, the most recently used
+// profile's, unless file gives its path. Without a folder, every bookmark is
+// in the feed.
+func readBookmarks(ctx context.Context, source *url.URL, _ *gofeed.Feed) (*gofeed.Feed, error) {
+	browser := source.Opaque
+	query := source.Query()
+	folder := query.Get("folder")
+	path := localPath(query.Get("file"))
+	var err error
+	if query.Get("file") == "" {
+		path, err = bookmarksFileOf(browser)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	modified, err := bookmarksModified(browser, path)
+	if err != nil {
+		return nil, err
+	}
+	bookmarksRead.Lock()
+	read, ok := bookmarksRead.files[path]
+	bookmarksRead.Unlock()
+	if !ok || !read.modified.Equal(modified) || read.folder != folder {
+		var bookmarks []bookmark
+		if browser == "firefox" {
+			bookmarks, err = firefoxBookmarks(ctx, path, folder)
+		} else {
+			bookmarks, err = chromiumBookmarks(path, folder)
+		}
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(bookmarks, func(i, j int) bool {
+			return bookmarks[i].added.After(bookmarks[j].added)
+		})
+		read = bookmarksFile{modified: modified, folder: folder, bookmarks: bookmarks}
+		bookmarksRead.Lock()
+		bookmarksRead.files[path] = read
+		bookmarksRead.Unlock()
+	}
+
+	feed := &gofeed.Feed{Title: bookmarksTitle, FeedLink: source.String()}
+	if folder != "" {
+		feed.Description = "Bookmarks in " + folder
+	}
+	browserName := strings.Title(browser)
+	for _, b := range read.bookmarks {
+		added := b.added
+		link := html.EscapeString(b.url)
+		title := b.title
+		if strings.TrimSpace(title) == "" {
+			title = b.url
+		}
+		feed.Items = append(feed.Items, &gofeed.Item{
+			Title:           title,
+			Link:            b.url,
+			GUID:            "bookmark:" + b.id,
+			Description:     fmt.Sprintf(`<p>Bookmarked in %s: <a href="%s">%s</a></p>`, browserName, link, link),
+			Published:       added.Format(time.RFC3339),
+			PublishedParsed: &added,
+		})
+	}
+	return feed, nil
+}
+
+// bookmarksFileOf finds where a browser keeps its bookmarks.
+func bookmarksFileOf(browser string) (string, error) {
+	if browser == "firefox" {
+		return firefoxPlaces()
+	}
+	dirs, ok := chromiumDirs[browser]
+	if !ok {
+		return "", fmt.Errorf("bookmarks: unknown browser %q (firefox, chromium, chrome or brave)", browser)
+	}
+	base, err := os.UserConfigDir()
+	if runtime.GOOS == "windows" {
+		base, err = os.Getenv("LOCALAPPDATA"), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, filepath.FromSlash(dirs[runtime.GOOS]), "Default", "Bookmarks"), nil
+}
+
+// firefoxPlaces finds the places database of the Firefox profile used last.
+func firefoxPlaces() (string, error) {
+	var base string
+	switch runtime.GOOS {
+	case "windows":
+		base = filepath.Join(os.Getenv("APPDATA"), "Mozilla", "Firefox", "Profiles")
+	case "darwin":
+		base = localPath("~/Library/Application Support/Firefox/Profiles")
+	default:
+		base = localPath("~/.mozilla/firefox")
+	}
+	matches, _ := filepath.Glob(filepath.Join(base, "*", "places.sqlite"))
+	var newest string
+	var newestTime time.Time
+	for _, match := range matches {
+		if modified, err := bookmarksModified("firefox", match); err == nil && modified.After(newestTime) {
+			newest, newestTime = match, modified
+		}
+	}
+	if newest == "" {
+		return "", fmt.Errorf("bookmarks: no Firefox profile in %s; give the path to places.sqlite as file", base)
+	}
+	return newest, nil
+}
+
+// bookmarksModified is when a browser's bookmarks last changed. Firefox
+// writes changes to the database's write-ahead log first.
+func bookmarksModified(browser string, path string) (time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	modified := info.ModTime()
+	if browser == "firefox" {
+		if wal, err := os.Stat(path + "-wal"); err == nil && wal.ModTime().After(modified) {
+			modified = wal.ModTime()
+		}
+	}
+	return modified, nil
+}
+
+// firefoxBookmarks reads the bookmarks in a folder (and the folders in it)
+// from a copy of Firefox's places database, which Firefox keeps locked while
+// it runs.
+func firefoxBookmarks(ctx context.Context, path string, folder string) ([]bookmark, error) {
+	if !haveSQLite {
+		return nil, errors.New("bookmarks: reading Firefox's bookmarks needs SQLite, which this build (without cgo) doesn't have")
+	}
+	dir, err := os.MkdirTemp("", "golang-rss-client-places")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	copied := filepath.Join(dir, "places.sqlite")
+	if err := copyFile(path, copied); err != nil {
+		return nil, err
+	}
+	// what hasn't reached the database yet is in its log
+	if err := copyFile(path+"-wal", copied+"-wal"); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite3", copied)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	// every folder by that name, and the folders inside those
+	query := `
+		WITH RECURSIVE folders(id) AS (
+			SELECT id FROM moz_bookmarks WHERE type = 2 AND title = ?
+			UNION SELECT b.id FROM moz_bookmarks b JOIN folders f ON b.parent = f.id WHERE b.type = 2
+		)
+		SELECT b.guid, COALESCE(b.title, p.title, ''), p.url, b.dateAdded
+		FROM moz_bookmarks b JOIN moz_places p ON p.id = b.fk
+		WHERE b.type = 1 AND b.parent IN folders AND p.url NOT LIKE 'place:%'`
+	args := []interface{}{folder}
+	if folder == "" {
+		query = `
+			SELECT b.guid, COALESCE(b.title, p.title, ''), p.url, b.dateAdded
+			FROM moz_bookmarks b JOIN moz_places p ON p.id = b.fk
+			WHERE b.type = 1 AND p.url NOT LIKE 'place:%'`
+		args = nil
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("bookmarks: %s: %w", path, err)
+	}
+	defer rows.Close()
+
+	var bookmarks []bookmark
+	for rows.Next() {
+		var b bookmark
+		var added int64
+		if err := rows.Scan(&b.id, &b.title, &b.url, &added); err != nil {
+			return nil, err
+		}
+		// microseconds since the Unix epoch
+		b.added = time.Unix(0, added*int64(time.Microsecond))
+		bookmarks = append(bookmarks, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if folder != "" && len(bookmarks) == 0 && !firefoxHasFolder(ctx, db, folder) {
+		return nil, fmt.Errorf("bookmarks: Firefox has no folder called %q", folder)
+	}
+	return bookmarks, nil
+}
+
+func firefoxHasFolder(ctx context.Context, db *sql.DB, folder string) bool {
+	var count int
+	err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM moz_bookmarks WHERE type = 2 AND title = ?`, folder).Scan(&count)
+	return err == nil && count > 0
+}
+
+// chromiumNode is a bookmark or folder in Chromium's Bookmarks file.
+type chromiumNode struct {
+	Type      string         `json:"type"`
+	Name      string         `json:"name"`
+	URL       string         `json:"url"`
+	GUID      string         `json:"guid"`
+	ID        string         `json:"id"`
+	DateAdded string         `json:"date_added"`
+	Children  []chromiumNode `json:"children"`
+}
+
+// chromiumBookmarks reads the bookmarks in a folder (and the folders in it)
+// from a Chromium-based browser's Bookmarks file.
+func chromiumBookmarks(path string, folder string) ([]bookmark, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file struct {
+		Roots map[string]json.RawMessage `json:"roots"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("bookmarks: %s: %w", path, err)
+	}
+
+	var bookmarks []bookmark
+	found := folder == ""
+	var walk func(node chromiumNode, inFolder bool)
+	walk = func(node chromiumNode, inFolder bool) {
+		switch node.Type {
+		case "url":
+			if inFolder {
+				bookmarks = append(bookmarks, chromiumBookmark(node))
+			}
+		case "folder":
+			if node.Name == folder {
+				found = true
+				inFolder = true
+			}
+			for _, child := range node.Children {
+				walk(child, inFolder)
+			}
+		}
+	}
+	// roots holds the bookmark bar, other bookmarks and mobile bookmarks, and
+	// a checksum-like entry or two that aren't folders
+	for _, raw := range file.Roots {
+		var root chromiumNode
+		if json.Unmarshal(raw, &root) == nil {
+			walk(root, folder == "")
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("bookmarks: there's no folder called %q", folder)
+	}
+	return bookmarks, nil
+}
+
+func chromiumBookmark(node chromiumNode) bookmark {
+	b := bookmark{id: node.GUID, title: node.Name, url: node.URL}
+	if b.id == "" {
+		b.id = node.ID
+	}
+	if added, err := strconv.ParseInt(node.DateAdded, 10, 64); err == nil && added > 0 {
+		// microseconds since 1601
+		b.added = time.Unix(added/1e6-chromiumEpoch, added%1e6*1e3)
+	}
+	return b
+}
+
+// copyFile copies a file's contents to a new one.
+func copyFile(from string, to string) error {
+	in, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(to)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,54 @@
+package fetch
+
+import (
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// earliestDate is older than any real feed item; dates before it are
+// placeholders like the zero time or the Unix epoch.
+var earliestDate = time.Date(1995, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// zoneSuffix matches the time zone at the end of a date: Z, an offset or an
+// abbreviation like GMT or PST.
+var zoneSuffix = regexp.MustCompile(`(?i)(z|[+-]\d{2}:?\d{2}|\b[a-z]{1,5})$`)
+
+// normalizeDates makes the dates of a feed and its items comparable across
+// feeds. Dates written without a time zone are read in the feed's own zone,
+// if one is configured; placeholder dates are dropped; dates in the future,
+// usually a wrong time zone, are pulled back to now. Everything ends up in the
+// display time zone.
+func (f Fetcher) normalizeDates(feedUrl string, feed *gofeed.Feed) {
+	now := time.Now()
+	normalize := func(raw string, parsed *time.Time) *time.Time {
+		if parsed == nil {
+			return nil
+		}
+		t := *parsed
+		if loc, ok := f.FeedTimezones[feedUrl]; ok && !zoneSuffix.MatchString(strings.TrimSpace(raw)) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+		}
+		if t.Before(earliestDate) {
+			return nil
+		}
+		if t.After(now) {
+			log.Printf("dates: %s has %q in the future, using now", feedUrl, raw)
+			t = now
+		}
+		if f.Timezone != nil {
+			t = t.In(f.Timezone)
+		}
+		return &t
+	}
+
+	feed.PublishedParsed = normalize(feed.Published, feed.PublishedParsed)
+	feed.UpdatedParsed = normalize(feed.Updated, feed.UpdatedParsed)
+	for _, item := range feed.Items {
+		item.PublishedParsed = normalize(item.Published, item.PublishedParsed)
+		item.UpdatedParsed = normalize(item.Updated, item.UpdatedParsed)
+	}
+}
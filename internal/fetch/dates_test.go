@@ -0,0 +1,67 @@
+package fetch
+
+import (
+	"io"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestNormalizeDates(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	tokyo := time.FixedZone("JST", 9*60*60)
+	newYork := time.FixedZone("EST", -5*60*60)
+	fetcher := Fetcher{
+		Timezone:      time.UTC,
+		FeedTimezones: map[string]*time.Location{"http://tokyo": tokyo},
+	}
+	noon := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		feedUrl string
+		raw     string
+		parsed  time.Time
+		// zero for a dropped date, and nowish for one pulled back to now
+		want   time.Time
+		nowish bool
+	}{
+		{"converted to the display zone", "http://other", "Sun, 01 Mar 2026 07:00:00 EST", noon.In(newYork), noon, false},
+		{"no zone read in the feed's", "http://tokyo", "2026-03-01 21:00:00", time.Date(2026, 3, 1, 21, 0, 0, 0, time.UTC), noon, false},
+		{"explicit zone wins over the feed's", "http://tokyo", "2026-03-01T12:00:00Z", noon, noon, false},
+		{"explicit offset wins over the feed's", "http://tokyo", "2026-03-01T07:00:00-05:00", noon.In(newYork), noon, false},
+		{"no zone and no feed zone left as is", "http://other", "2026-03-01 12:00:00", noon, noon, false},
+		{"zero time dropped", "http://other", "", time.Time{}, time.Time{}, false},
+		{"epoch dropped", "http://other", "1970-01-01T00:00:00Z", time.Unix(0, 0), time.Time{}, false},
+		{"future pulled back to now", "http://other", "2099-01-01T00:00:00Z", time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC), time.Time{}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parsed := test.parsed
+			feed := &gofeed.Feed{Items: []*gofeed.Item{{Published: test.raw, PublishedParsed: &parsed}}}
+			before := time.Now()
+			fetcher.normalizeDates(test.feedUrl, feed)
+			got := feed.Items[0].PublishedParsed
+
+			switch {
+			case test.nowish:
+				if got == nil || got.Before(before) || got.After(time.Now()) {
+					t.Errorf("got %v, want now", got)
+				}
+			case test.want.IsZero():
+				if got != nil {
+					t.Errorf("got %v, want the date dropped", got)
+				}
+			case got == nil:
+				t.Errorf("date dropped, want %v", test.want)
+			case !got.Equal(test.want) || got.Location() != time.UTC:
+				t.Errorf("got %v, want %v in UTC", got, test.want)
+			}
+		})
+	}
+}
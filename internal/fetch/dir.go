@@ -0,0 +1,180 @@
+package fetch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mmcdole/gofeed"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"gopkg.in/yaml.v2"
+)
+
+// dirExtensions are the files in a directory that are items, by what they're
+// written in.
+var dirExtensions = map[string]string{
+	".html":     "html",
+	".htm":      "html",
+	".md":       "markdown",
+	".markdown": "markdown",
+}
+
+// markdown renders markdown files to the HTML items are made of.
+var markdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// readDir is a feed of the HTML and markdown files in a directory, for URLs
+// like dir:~/scraped or dir:/var/lib/scraper/out, each file an item dated by
+// when it was last changed. Files in the directory's own directories, and
+// hidden ones, are left out.
+func readDir(ctx context.Context, source *url.URL, _ *gofeed.Feed) (*gofeed.Feed, error) {
+	dir := source.Opaque
+	if dir == "" {
+		dir = source.Path
+	} else if unescaped, err := url.PathUnescape(dir); err == nil {
+		dir = unescaped
+	}
+	if dir == "" {
+		return nil, fmt.Errorf("dir: no directory given in %s", source)
+	}
+	dir = localPath(dir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &gofeed.Feed{
+		Title:    filepath.Base(dir),
+		FeedLink: source.String(),
+		Link:     fileURL(dir),
+	}
+	for _, entry := range entries {
+		kind, ok := dirExtensions[strings.ToLower(filepath.Ext(entry.Name()))]
+		if !ok || entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			// gone since the directory was read
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		item, err := fileItem(data, kind)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if item.Title == "" {
+			item.Title = strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		}
+		modified := info.ModTime()
+		item.Link = fileURL(path)
+		item.GUID = item.Link
+		item.Updated = modified.Format("2006-01-02T15:04:05Z07:00")
+		item.UpdatedParsed = &modified
+		item.Published = item.Updated
+		item.PublishedParsed = &modified
+		feed.Items = append(feed.Items, item)
+	}
+	sort.SliceStable(feed.Items, func(i, j int) bool {
+		return feed.Items[i].PublishedParsed.After(*feed.Items[j].PublishedParsed)
+	})
+	return feed, nil
+}
+
+// fileItem is an item made of an HTML or markdown file, titled by the HTML's
+// title or first heading, or by the markdown's front matter or first
+// heading.
+func fileItem(data []byte, kind string) (*gofeed.Item, error) {
+	item := &gofeed.Item{}
+	if kind == "markdown" {
+		data, item.Title = frontMatter(data)
+		var rendered bytes.Buffer
+		if err := markdown.Convert(data, &rendered); err != nil {
+			return nil, err
+		}
+		data = rendered.Bytes()
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if item.Title == "" {
+		item.Title = strings.TrimSpace(doc.Find("title").First().Text())
+	}
+	if item.Title == "" {
+		item.Title = strings.TrimSpace(doc.Find("h1").First().Text())
+	}
+	if author := authorMeta(doc); author != nil {
+		item.Authors = []*gofeed.Person{author}
+	}
+	if description, ok := doc.Find(`meta[name="description"]`).Attr("content"); ok {
+		item.Description = strings.TrimSpace(description)
+	}
+	item.Content, err = doc.Find("body").Html()
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// authorMeta is who an HTML page says wrote it.
+func authorMeta(doc *goquery.Document) *gofeed.Person {
+	if name, ok := doc.Find(`meta[name="author"]`).Attr("content"); ok && strings.TrimSpace(name) != "" {
+		return &gofeed.Person{Name: strings.TrimSpace(name)}
+	}
+	return nil
+}
+
+// frontMatter takes the YAML front matter off the top of a markdown file,
+// returning the rest and the title it gives.
+func frontMatter(data []byte) ([]byte, string) {
+	normalized := bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	if !bytes.HasPrefix(normalized, []byte("---\n")) {
+		return data, ""
+	}
+	end := bytes.Index(normalized[len("---\n"):], []byte("\n---"))
+	if end < 0 {
+		return data, ""
+	}
+	header := normalized[len("---\n") : len("---\n")+end]
+	rest := normalized[len("---\n")+end+len("\n---"):]
+	if newline := bytes.IndexByte(rest, '\n'); newline >= 0 {
+		rest = rest[newline+1:]
+	} else {
+		rest = nil
+	}
+	var fields struct {
+		Title string `yaml:"title"`
+	}
+	if yaml.Unmarshal(header, &fields) != nil {
+		// not front matter after all, just a rule
+		return data, ""
+	}
+	return rest, strings.TrimSpace(fields.Title)
+}
+
+// fileURL is a file:// URL for a path.
+func fileURL(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		// C:/... on Windows
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
@@ -0,0 +1,89 @@
+package fetch
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/homielabs/golang-rss-client/internal/render"
+)
+
+// feedTypes are the <link rel="alternate"> types that mean a feed.
+var feedTypes = map[string]string{
+	"application/rss+xml":   "RSS",
+	"application/atom+xml":  "Atom",
+	"application/feed+json": "JSON Feed",
+}
+
+// DiscoveredFeed is a feed a web page advertises.
+type DiscoveredFeed struct {
+	URL   string
+	Title string
+	// Format is RSS, Atom or JSON Feed
+	Format string
+}
+
+// PageError is what fetching a web page rather than a feed fails with. Feeds
+// are the ones the page advertises, if any.
+type PageError struct {
+	URL   string
+	Feeds []DiscoveredFeed
+}
+
+func (e *PageError) Error() string {
+	switch len(e.Feeds) {
+	case 0:
+		return fmt.Sprintf("%s is a web page, not a feed", e.URL)
+	case 1:
+		return fmt.Sprintf("%s is a web page with a feed at %s", e.URL, e.Feeds[0].URL)
+	}
+	return fmt.Sprintf("%s is a web page with %d feeds", e.URL, len(e.Feeds))
+}
+
+// DiscoverFeeds finds the feeds a page advertises with <link rel="alternate">
+// in its <head>, resolved against the page's URL. Each is only listed once.
+func DiscoverFeeds(doc *goquery.Document, page string) []DiscoveredFeed {
+	base, _ := url.Parse(page)
+	var feeds []DiscoveredFeed
+	seen := make(map[string]bool)
+	doc.Find(`link[rel~="alternate"]`).Each(func(_ int, s *goquery.Selection) {
+		format, ok := feedTypes[strings.ToLower(strings.TrimSpace(s.AttrOr("type", "")))]
+		if !ok {
+			return
+		}
+		href, err := url.Parse(strings.TrimSpace(s.AttrOr("href", "")))
+		if err != nil || href.String() == "" {
+			return
+		}
+		if base != nil {
+			href = base.ResolveReference(href)
+		}
+		if seen[href.String()] {
+			return
+		}
+		seen[href.String()] = true
+		feeds = append(feeds, DiscoveredFeed{
+			URL:    href.String(),
+			Title:  render.StripControl(strings.Join(strings.Fields(s.AttrOr("title", "")), " ")),
+			Format: format,
+		})
+	})
+	return feeds
+}
+
+// pageError turns a body that didn't parse as a feed into a *PageError if
+// it's HTML, or nil if it isn't.
+func pageError(page string, body []byte, contentType string) error {
+	if !strings.HasPrefix(strings.ToLower(contentType), "text/html") &&
+		!strings.HasPrefix(http.DetectContentType(body), "text/html") {
+		return nil
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil
+	}
+	return &PageError{URL: page, Feeds: DiscoverFeeds(doc, page)}
+}
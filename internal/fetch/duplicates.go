@@ -0,0 +1,70 @@
+package fetch
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// RepublishedKey is set in an item's Custom map to the number of extra
+// copies of it that were collapsed away.
+const RepublishedKey = "golang-rss-client:republished"
+
+// duplicateKey identifies an item by what a reader sees rather than its GUID,
+// since some feeds hand out a new GUID every time an item is edited.
+func duplicateKey(item *gofeed.Item) string {
+	title := strings.ToLower(strings.Join(strings.Fields(item.Title), " "))
+	link := strings.TrimSpace(item.Link)
+	if u, err := url.Parse(link); err == nil {
+		u.Host = strings.ToLower(u.Host)
+		u.Fragment = ""
+		u.Path = strings.TrimSuffix(u.Path, "/")
+		link = u.String()
+	}
+	if title == "" && link == "" {
+		return ""
+	}
+	return title + "\x00" + link
+}
+
+// itemTime is when an item was last touched, as far as the feed says.
+func itemTime(item *gofeed.Item) time.Time {
+	if item.UpdatedParsed != nil {
+		return *item.UpdatedParsed
+	}
+	if item.PublishedParsed != nil {
+		return *item.PublishedParsed
+	}
+	return time.Time{}
+}
+
+// collapseDuplicates keeps one copy of every item that appears more than
+// once with the same title and link: the most recent one, in the place of the
+// first. The copy kept is badged with how many others there were.
+func collapseDuplicates(feed *gofeed.Feed) {
+	first := make(map[string]int)
+	var items []*gofeed.Item
+	for _, item := range feed.Items {
+		key := duplicateKey(item)
+		i, seen := first[key]
+		if key == "" || !seen {
+			first[key] = len(items)
+			items = append(items, item)
+			continue
+		}
+
+		kept := items[i]
+		copies, _ := strconv.Atoi(kept.Custom[RepublishedKey])
+		if itemTime(item).After(itemTime(kept)) {
+			kept, items[i] = item, item
+		}
+		if kept.Custom == nil {
+			kept.Custom = make(map[string]string)
+		}
+		kept.Custom[RepublishedKey] = strconv.Itoa(copies + 1)
+	}
+	feed.Items = items
+}
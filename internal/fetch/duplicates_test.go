@@ -0,0 +1,88 @@
+package fetch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestCollapseDuplicates(t *testing.T) {
+	day := func(d int) *time.Time {
+		date := time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+	item := func(guid, title, link string, published *time.Time) *gofeed.Item {
+		return &gofeed.Item{GUID: guid, Title: title, Link: link, PublishedParsed: published}
+	}
+
+	tests := []struct {
+		name  string
+		items []*gofeed.Item
+		// GUIDs left, and how many copies each was badged with
+		want   []string
+		copies []string
+	}{
+		{
+			"no duplicates",
+			[]*gofeed.Item{item("a", "A", "http://x/a", nil), item("b", "B", "http://x/b", nil)},
+			[]string{"a", "b"},
+			[]string{"", ""},
+		},
+		{
+			"newest copy kept in the first's place",
+			[]*gofeed.Item{
+				item("a1", "A", "http://x/a", day(1)),
+				item("b", "B", "http://x/b", day(2)),
+				item("a2", "A", "http://x/a", day(3)),
+			},
+			[]string{"a2", "b"},
+			[]string{"1", ""},
+		},
+		{
+			"older copy dropped",
+			[]*gofeed.Item{item("a1", "A", "http://x/a", day(3)), item("a2", "A", "http://x/a", day(1))},
+			[]string{"a1"},
+			[]string{"1"},
+		},
+		{
+			"title whitespace, case, host case, fragment and trailing slash ignored",
+			[]*gofeed.Item{
+				item("a1", "Hello  World", "http://X/a/#top", nil),
+				item("a2", " hello world", "http://x/a", nil),
+				item("a3", "HELLO WORLD", "http://x/a/", nil),
+			},
+			[]string{"a1"},
+			[]string{"2"},
+		},
+		{
+			"different links kept",
+			[]*gofeed.Item{item("a", "A", "http://x/a", nil), item("b", "A", "http://x/b", nil)},
+			[]string{"a", "b"},
+			[]string{"", ""},
+		},
+		{
+			"items with neither title nor link kept",
+			[]*gofeed.Item{item("a", "", "", nil), item("b", "", "", nil)},
+			[]string{"a", "b"},
+			[]string{"", ""},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			feed := &gofeed.Feed{Items: test.items}
+			collapseDuplicates(feed)
+			if len(feed.Items) != len(test.want) {
+				t.Fatalf("got %d items, want %d", len(feed.Items), len(test.want))
+			}
+			for i, item := range feed.Items {
+				if item.GUID != test.want[i] {
+					t.Errorf("item %d is %q, want %q", i, item.GUID, test.want[i])
+				}
+				if got := item.Custom[RepublishedKey]; got != test.copies[i] {
+					t.Errorf("item %q badged %q, want %q", item.GUID, got, test.copies[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+// Package fetch downloads and parses feeds.
+package fetch
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/homielabs/golang-rss-client/internal/render"
+	"github.com/homielabs/golang-rss-client/internal/store"
+	"github.com/mmcdole/gofeed"
+)
+
+// Fetcher holds the settings every feed is fetched with.
+type Fetcher struct {
+	// Timeout is how long a fetch may take before it's given up on.
+	Timeout time.Duration
+	// MaxItems is how many items of each feed are kept; 0 keeps everything.
+	MaxItems int
+	// CollapseDuplicates keeps only the latest copy of items that appear
+	// more than once with the same title and link.
+	CollapseDuplicates bool
+	// Stats, if set, has every request and the bytes read recorded in it.
+	Stats *Stats
+	// Cache, if set, has every successfully fetched feed saved to it.
+	Cache *store.FeedCache
+	// Timezone, if set, is the zone every date is converted to.
+	Timezone *time.Location
+	// FeedTimezones are the zones dates without one are read in, by feed URL.
+	FeedTimezones map[string]*time.Location
+	// Proxy, if set, has feeds fetched through the proxies configured rather
+	// than the environment's.
+	Proxy *Proxy
+	// Limiter, if set, bounds how many feeds are fetched at once. Time spent
+	// waiting for a turn doesn't count towards the timeout.
+	Limiter *Limiter
+	// Archive, if set, keeps every item fetched, and has feeds that only
+	// carry their latest items filled in with the older ones it has. Feeds
+	// that can't be fetched are read from it instead.
+	Archive *store.Archive
+	// ArchiveHistory is how many items a feed is filled in to from the
+	// archive; 0 takes all it has.
+	ArchiveHistory int
+	// Sources read the feeds of URL schemes of their own, like the greader:
+	// feeds of a Google Reader API server, along with the bookmarks: and
+	// dir: ones on this machine.
+	Sources map[string]Source
+}
+
+// MoveFeed carries what's kept by a feed's URL over to the new URL it's moved
+// to, or forgets it if newUrl is empty: its proxy, its stats and its time
+// zone. Fetches already running on copies of f still read the zones they
+// started with, so those are replaced rather than changed in place.
+func (f *Fetcher) MoveFeed(oldUrl string, newUrl string) {
+	f.Proxy.MoveFeed(oldUrl, newUrl)
+	f.Stats.MoveFeed(oldUrl, newUrl)
+	if loc, ok := f.FeedTimezones[oldUrl]; ok {
+		timezones := make(map[string]*time.Location, len(f.FeedTimezones))
+		for feedUrl, zone := range f.FeedTimezones {
+			if feedUrl != oldUrl {
+				timezones[feedUrl] = zone
+			}
+		}
+		if newUrl != "" {
+			timezones[newUrl] = loc
+		}
+		f.FeedTimezones = timezones
+	}
+}
+
+// Fetch downloads and parses a single feed, giving up when ctx is done or the
+// timeout passes. Everything in it that ends up on screen is stripped of
+// control characters.
+func (f Fetcher) Fetch(ctx context.Context, feedUrl string) (*gofeed.Feed, error) {
+	feed, _, err := f.FetchMoved(ctx, feedUrl)
+	return feed, err
+}
+
+// FetchMoved is Fetch, also reporting the URL the feed has permanently moved
+// to. That's empty unless every redirect on the way was a permanent one.
+//
+// With a Cache, feeds are only downloaded if they've changed since they were
+// cached; otherwise the cached copy is returned.
+//
+// With an Archive, a feed that fails to fetch comes back from the archive
+// along with the error, if the archive has it. Feeds read from a Source come
+// back from the cache that way even without one.
+//
+// Feeds made from things on this machine, like bookmarks: and dir: URLs, are read
+// rather than downloaded, without waiting on the Limiter.
+func (f Fetcher) FetchMoved(ctx context.Context, feedUrl string) (*gofeed.Feed, string, error) {
+	var feed *gofeed.Feed
+	var movedTo string
+	var err error
+	if f.IsLocal(feedUrl) {
+		feed, err = f.fetchLocal(ctx, feedUrl)
+	} else {
+		if err := f.Limiter.acquire(ctx); err != nil {
+			return nil, "", err
+		}
+		feed, movedTo, err = f.fetch(ctx, feedUrl, f.Cache != nil)
+		f.Limiter.release()
+	}
+	if f.Archive == nil {
+		return feed, movedTo, err
+	}
+	if err != nil {
+		if archived, archiveErr := f.Archive.Feed(feedUrl, f.ArchiveHistory); archiveErr == nil {
+			return archived, "", err
+		}
+		return feed, "", err
+	}
+	f.addHistory(feedUrl, feed)
+	return feed, movedTo, nil
+}
+
+// addHistory saves a fetched feed to the archive, then adds the items the
+// archive has that the feed no longer does after its own.
+func (f Fetcher) addHistory(feedUrl string, feed *gofeed.Feed) {
+	if err := f.Archive.Save(feedUrl, feed, time.Now()); err != nil {
+		log.Printf("%s: couldn't archive: %s", feedUrl, err)
+		return
+	}
+	if f.ArchiveHistory > 0 && len(feed.Items) >= f.ArchiveHistory {
+		return
+	}
+	archived, err := f.Archive.Items(feedUrl, f.ArchiveHistory)
+	if err != nil {
+		log.Printf("%s: couldn't read the archive: %s", feedUrl, err)
+		return
+	}
+	present := make(map[string]bool, len(feed.Items))
+	for _, item := range feed.Items {
+		present[store.ItemKey(item)] = true
+	}
+	for _, item := range archived {
+		if f.ArchiveHistory > 0 && len(feed.Items) >= f.ArchiveHistory {
+			break
+		}
+		if !present[store.ItemKey(item)] {
+			feed.Items = append(feed.Items, item)
+		}
+	}
+}
+
+func (f Fetcher) fetch(ctx context.Context, feedUrl string, conditional bool) (*gofeed.Feed, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, f.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedUrl, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("User-Agent", "golang-rss-client")
+	if conditional {
+		validators := f.Cache.Validators(feedUrl)
+		if validators.ETag != "" {
+			req.Header.Set("If-None-Match", validators.ETag)
+		}
+		if validators.LastModified != "" {
+			req.Header.Set("If-Modified-Since", validators.LastModified)
+		}
+	}
+
+	f.Stats.AddRequest(feedUrl)
+	start := time.Now()
+	redirects := &redirectTracker{}
+	resp, err := redirects.client(f.Proxy.Transport(feedUrl)).Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && conditional {
+		feed, err := f.Cache.Load(feedUrl)
+		if err != nil {
+			// the cached copy went missing, so ask for the whole feed
+			log.Printf("%s: not modified, but the cached copy can't be read: %s", feedUrl, err)
+			return f.fetch(ctx, feedUrl, false)
+		}
+		log.Printf("timing: %s not modified, checked in %s", feedUrl, time.Since(start))
+		// the copy may have been saved by a version that didn't note these
+		detectLanguages(feed)
+		rateAdvisories(feed)
+		noteIncidents(feed)
+		fingerprintItems(feed)
+		f.Stats.markFetched(feedUrl)
+		return feed, redirects.movedTo(), nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, "", gofeed.HTTPError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+		}
+	}
+
+	// the whole body is needed in case the feed has to be patched up to
+	// parse, but truncating still stops the download early
+	body, err := io.ReadAll(truncateFeed(
+		countingReader{reader: resp.Body, feedUrl: feedUrl, stats: f.Stats},
+		f.MaxItems,
+	))
+	if err != nil {
+		return nil, "", err
+	}
+	fetched := time.Now()
+	feed, err := parseFeed(feedUrl, body, resp.Header.Get("Content-Type"))
+	if err != nil {
+		// a web page may advertise the feeds to try instead
+		if pageErr := pageError(resp.Request.URL.String(), body, resp.Header.Get("Content-Type")); pageErr != nil {
+			return nil, "", pageErr
+		}
+		return nil, "", err
+	}
+	log.Printf(
+		"timing: %s fetched in %s, parsed in %s",
+		feedUrl, fetched.Sub(start), time.Since(fetched),
+	)
+	f.Stats.markFetched(feedUrl)
+	addJSONFeedFields(body, feed)
+	render.SanitizeFeed(feed)
+	detectLanguages(feed)
+	rateAdvisories(feed)
+	noteIncidents(feed)
+	fingerprintItems(feed)
+	f.normalizeDates(feedUrl, feed)
+	if f.Cache != nil {
+		// the cached copy is the version re-published items are compared with
+		cached, _ := f.Cache.Load(feedUrl)
+		keepRevisions(feed, cached)
+	}
+	if f.CollapseDuplicates {
+		collapseDuplicates(feed)
+	}
+	if f.MaxItems > 0 && len(feed.Items) > f.MaxItems {
+		feed.Items = feed.Items[:f.MaxItems]
+	}
+	if f.Cache != nil {
+		// the validators only go with the copy they were served with
+		if err := f.Cache.Save(feedUrl, feed); err != nil {
+			log.Println(err)
+		} else if err := f.Cache.SaveValidators(feedUrl, store.Validators{
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+		}); err != nil {
+			log.Println(err)
+		}
+	}
+	return feed, redirects.movedTo(), nil
+}
+
+// Placeholder stands in for a feed that hasn't been fetched, so it still has
+// a slot (and a recognisable title) in a list of feeds.
+func Placeholder(feedUrl string) gofeed.Feed {
+	return gofeed.Feed{Title: feedUrl, FeedLink: feedUrl}
+}
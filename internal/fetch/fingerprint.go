@@ -0,0 +1,123 @@
+package fetch
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"hash/fnv"
+	"strings"
+	"unicode"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mmcdole/gofeed"
+)
+
+// FingerprintKey is set in an item's Custom map to its Fingerprint, as hex.
+// Items too short to tell apart by one don't have it.
+const FingerprintKey = "golang-rss-client:fingerprint"
+
+// fingerprintWords is how many words an item needs for a fingerprint; shorter
+// ones look alike anyway.
+const fingerprintWords = 40
+
+// fingerprintShingle is how many words in a row make up each of the runs of
+// words a text is compared by.
+const fingerprintShingle = 3
+
+// NearIdentical is how alike two fingerprints are, at least, when their
+// items have the same text give or take a heading or footer: a press release
+// syndicated to a few feeds, say.
+const NearIdentical = 0.75
+
+// Fingerprint sums up an item's text as the least hashes of its runs of
+// words, under a few hash functions (a MinHash). The share of them two
+// fingerprints have in common is about the share of the runs of words their
+// texts have in common.
+type Fingerprint [16]uint32
+
+// Similarity is the share of two fingerprints' hashes that are the same.
+func (f Fingerprint) Similarity(other Fingerprint) float64 {
+	same := 0
+	for i := range f {
+		if f[i] == other[i] {
+			same++
+		}
+	}
+	return float64(same) / float64(len(f))
+}
+
+// ItemFingerprint is an item's fingerprint, if it has one.
+func ItemFingerprint(item *gofeed.Item) (Fingerprint, bool) {
+	var fingerprint Fingerprint
+	data, err := hex.DecodeString(item.Custom[FingerprintKey])
+	if err != nil || len(data) != 4*len(fingerprint) {
+		return fingerprint, false
+	}
+	for i := range fingerprint {
+		fingerprint[i] = binary.BigEndian.Uint32(data[4*i:])
+	}
+	return fingerprint, true
+}
+
+// fingerprintItems notes the fingerprint of each of a feed's items.
+func fingerprintItems(feed *gofeed.Feed) {
+	for _, item := range feed.Items {
+		fingerprint, ok := fingerprintText(bodyText(item))
+		if !ok {
+			continue
+		}
+		data := make([]byte, 4*len(fingerprint))
+		for i, hash := range fingerprint {
+			binary.BigEndian.PutUint32(data[4*i:], hash)
+		}
+		if item.Custom == nil {
+			item.Custom = make(map[string]string)
+		}
+		item.Custom[FingerprintKey] = hex.EncodeToString(data)
+	}
+}
+
+// bodyText is an item's text without the markup.
+func bodyText(item *gofeed.Item) string {
+	text := item.Content
+	if text == "" {
+		text = item.Description
+	}
+	if doc, err := goquery.NewDocumentFromReader(strings.NewReader(text)); err == nil {
+		text = doc.Text()
+	}
+	return text
+}
+
+// fingerprintText is the fingerprint of a text, if it's long enough to have
+// one.
+func fingerprintText(text string) (Fingerprint, bool) {
+	var fingerprint Fingerprint
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	if len(words) < fingerprintWords {
+		return fingerprint, false
+	}
+	for i := range fingerprint {
+		fingerprint[i] = ^uint32(0)
+	}
+	for i := 0; i+fingerprintShingle <= len(words); i++ {
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(words[i:i+fingerprintShingle], " ")))
+		shingle := h.Sum64()
+		for j := range fingerprint {
+			if hash := uint32(mix(shingle + uint64(j)*0x9e3779b97f4a7c15)); hash < fingerprint[j] {
+				fingerprint[j] = hash
+			}
+		}
+	}
+	return fingerprint, true
+}
+
+// mix scrambles a number's bits (splitmix64's finalizer), making one hash
+// function of many out of a single hash and an offset.
+func mix(x uint64) uint64 {
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
@@ -0,0 +1,49 @@
+package fetch
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+)
+
+const pressRelease = `ACME Corporation today announced the general availability of its
+next generation widget platform, which gives customers around the world a faster and
+more reliable way to build, ship and monitor their widgets at any scale. The platform
+is available starting today in every region, with pricing that starts at nothing for
+small teams and grows with usage as their widget fleets grow over the coming years.`
+
+func TestFingerprintSimilarity(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		similar bool
+	}{
+		{"same text", pressRelease, pressRelease, true},
+		{"heading and footer added", "<h1>Syndicated</h1><p>" + pressRelease + "</p><p>Read more at Example News.</p>", pressRelease, true},
+		{"case and punctuation ignored", strings.ToUpper(strings.ReplaceAll(pressRelease, ",", ";")), pressRelease, true},
+		{"different text", strings.Repeat("The quick brown fox jumps over the lazy dog while cats watch. ", 8), pressRelease, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			feed := &gofeed.Feed{Items: []*gofeed.Item{{Description: test.a}, {Content: test.b}}}
+			fingerprintItems(feed)
+			a, okA := ItemFingerprint(feed.Items[0])
+			b, okB := ItemFingerprint(feed.Items[1])
+			if !okA || !okB {
+				t.Fatal("no fingerprint")
+			}
+			if similar := a.Similarity(b) >= NearIdentical; similar != test.similar {
+				t.Errorf("similarity %v, want near-identical %v", a.Similarity(b), test.similar)
+			}
+		})
+	}
+}
+
+func TestFingerprintTooShort(t *testing.T) {
+	item := &gofeed.Item{Description: "<p>Just a few words.</p>"}
+	fingerprintItems(&gofeed.Feed{Items: []*gofeed.Item{item}})
+	if _, ok := ItemFingerprint(item); ok {
+		t.Error("a short item got a fingerprint")
+	}
+}
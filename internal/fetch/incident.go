@@ -0,0 +1,64 @@
+package fetch
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// IncidentKey is set in an item's Custom map to the state of the incident
+// it is, from the latest update a status page (statuspage.io's history
+// feeds, or ones like them) posted about it: "investigating", "identified",
+// "monitoring", "resolved" and so on. Items that aren't incidents don't have
+// it.
+const IncidentKey = "golang-rss-client:incident"
+
+// incidentStates are the states status pages give incidents and
+// maintenance, and whether each is of one that's still going on.
+var incidentStates = map[string]bool{
+	"investigating": true,
+	"identified":    true,
+	"monitoring":    true,
+	"update":        true,
+	"in progress":   true,
+	"verifying":     true,
+	"resolved":      false,
+	"postmortem":    false,
+	"scheduled":     false,
+	"completed":     false,
+}
+
+// incidentUpdate matches the state heading an update to an incident, as in
+// "<strong>Investigating</strong> - We're looking into it". Status pages
+// put the latest update first.
+var incidentUpdate = regexp.MustCompile(`(?i)<(?:strong|b)>\s*([a-z][a-z ]*?)\s*</(?:strong|b)>\s*(?:-|&#8211;|&ndash;|–)`)
+
+// IncidentOpen reports whether an item is an incident that hasn't been
+// resolved, or maintenance that's under way.
+func IncidentOpen(item *gofeed.Item) bool {
+	return incidentStates[item.Custom[IncidentKey]]
+}
+
+// noteIncidents notes the state of each of a feed's items that's an update
+// on a status page's incident.
+func noteIncidents(feed *gofeed.Feed) {
+	for _, item := range feed.Items {
+		text := item.Description
+		if text == "" {
+			text = item.Content
+		}
+		match := incidentUpdate.FindStringSubmatch(text)
+		if match == nil {
+			continue
+		}
+		state := strings.ToLower(strings.Join(strings.Fields(match[1]), " "))
+		if _, ok := incidentStates[state]; !ok {
+			continue
+		}
+		if item.Custom == nil {
+			item.Custom = make(map[string]string)
+		}
+		item.Custom[IncidentKey] = state
+	}
+}
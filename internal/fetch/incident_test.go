@@ -0,0 +1,59 @@
+package fetch
+
+import (
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestNoteIncidents(t *testing.T) {
+	tests := []struct {
+		name     string
+		item     gofeed.Item
+		want     string
+		wantOpen bool
+	}{
+		{
+			"latest update first",
+			gofeed.Item{Description: "<p><strong>Monitoring</strong> - A fix is out.</p><p><strong>Investigating</strong> - We're looking into it.</p>"},
+			"monitoring", true,
+		},
+		{
+			"resolved",
+			gofeed.Item{Description: "<p><b>Resolved</b> &ndash; All good.</p>"},
+			"resolved", false,
+		},
+		{
+			"maintenance under way",
+			gofeed.Item{Description: "<strong> In  progress </strong> – Scheduled maintenance is under way."},
+			"in progress", true,
+		},
+		{
+			"content when there's no description",
+			gofeed.Item{Content: "<strong>Identified</strong> &#8211; Found it."},
+			"identified", true,
+		},
+		{
+			"unknown state",
+			gofeed.Item{Description: "<strong>Note</strong> - Not an incident."},
+			"", false,
+		},
+		{
+			"not an incident",
+			gofeed.Item{Description: "<p>Release <strong>1.2</strong> is out.</p>"},
+			"", false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			item := test.item
+			noteIncidents(&gofeed.Feed{Items: []*gofeed.Item{&item}})
+			if got := item.Custom[IncidentKey]; got != test.want {
+				t.Errorf("state %q, want %q", got, test.want)
+			}
+			if got := IncidentOpen(&item); got != test.wantOpen {
+				t.Errorf("IncidentOpen() = %v, want %v", got, test.wantOpen)
+			}
+		})
+	}
+}
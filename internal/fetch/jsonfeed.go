@@ -0,0 +1,79 @@
+package fetch
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/mmcdole/gofeed"
+	jsonfeed "github.com/mmcdole/gofeed/json"
+)
+
+// ExternalURLKey, AvatarKey and DurationKey are set in an item's Custom map
+// to the JSON Feed fields gofeed has nowhere to put: the page a linkblog item
+// is about, the URL of its author's avatar, and how many seconds its
+// attachment plays for.
+const (
+	ExternalURLKey = "golang-rss-client:external-url"
+	AvatarKey      = "golang-rss-client:avatar"
+	DurationKey    = "golang-rss-client:duration"
+)
+
+// addJSONFeedFields reads a JSON feed again for what translating it to a
+// gofeed.Feed drops, and puts it on the items. gofeed also gives attachments
+// their duration as the length, which is the size in RSS; that's put right.
+func addJSONFeedFields(body []byte, feed *gofeed.Feed) {
+	if feed.FeedType != "json" {
+		return
+	}
+	parsed, err := (&jsonfeed.Parser{}).Parse(bytes.NewReader(body))
+	if err != nil || len(parsed.Items) != len(feed.Items) {
+		return
+	}
+	feedAvatar := avatarOf(parsed.Author, parsed.Authors)
+	for i, jsonItem := range parsed.Items {
+		item := feed.Items[i]
+		setCustom := func(key, value string) {
+			if value == "" {
+				return
+			}
+			if item.Custom == nil {
+				item.Custom = make(map[string]string)
+			}
+			item.Custom[key] = value
+		}
+		if jsonItem.ExternalURL != jsonItem.URL {
+			setCustom(ExternalURLKey, jsonItem.ExternalURL)
+		}
+		// items without an author of their own are by the feed's
+		if avatar := avatarOf(jsonItem.Author, jsonItem.Authors); avatar != "" {
+			setCustom(AvatarKey, avatar)
+		} else if jsonItem.Author == nil && len(jsonItem.Authors) == 0 {
+			setCustom(AvatarKey, feedAvatar)
+		}
+		if jsonItem.Attachments == nil || len(*jsonItem.Attachments) != len(item.Enclosures) {
+			continue
+		}
+		for j, attachment := range *jsonItem.Attachments {
+			item.Enclosures[j].Length = ""
+			if attachment.SizeInBytes > 0 {
+				item.Enclosures[j].Length = strconv.FormatInt(attachment.SizeInBytes, 10)
+			}
+			if attachment.DurationInSeconds > 0 && item.Custom[DurationKey] == "" {
+				setCustom(DurationKey, strconv.FormatInt(attachment.DurationInSeconds, 10))
+			}
+		}
+	}
+}
+
+// avatarOf is the avatar of the first author that has one.
+func avatarOf(author *jsonfeed.Author, authors []*jsonfeed.Author) string {
+	if author != nil && author.Avatar != "" {
+		return author.Avatar
+	}
+	for _, a := range authors {
+		if a != nil && a.Avatar != "" {
+			return a.Avatar
+		}
+	}
+	return ""
+}
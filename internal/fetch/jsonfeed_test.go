@@ -0,0 +1,75 @@
+package fetch
+
+import (
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestAddJSONFeedFields(t *testing.T) {
+	const body = `{
+		"version": "https://jsonfeed.org/version/1.1",
+		"title": "Linkblog",
+		"authors": [{"name": "Feed author", "avatar": "https://x/feed.png"}],
+		"items": [
+			{
+				"id": "1",
+				"url": "https://x/1",
+				"external_url": "https://elsewhere/article",
+				"content_text": "A link",
+				"authors": [{"name": "Bob", "avatar": "https://x/bob.png"}]
+			},
+			{
+				"id": "2",
+				"url": "https://x/2",
+				"external_url": "https://x/2",
+				"content_text": "An episode",
+				"attachments": [
+					{"url": "https://x/2.mp3", "mime_type": "audio/mpeg", "size_in_bytes": 1234, "duration_in_seconds": 600},
+					{"url": "https://x/2.ogg", "mime_type": "audio/ogg", "duration_in_seconds": 601}
+				]
+			},
+			{
+				"id": "3",
+				"url": "https://x/3",
+				"content_text": "By someone without an avatar",
+				"authors": [{"name": "Alice"}]
+			}
+		]
+	}`
+	feed, err := gofeed.NewParser().ParseString(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addJSONFeedFields([]byte(body), feed)
+
+	tests := []struct {
+		item int
+		key  string
+		want string
+	}{
+		{0, ExternalURLKey, "https://elsewhere/article"},
+		{0, AvatarKey, "https://x/bob.png"},
+		{1, ExternalURLKey, ""},
+		{1, AvatarKey, "https://x/feed.png"},
+		{1, DurationKey, "600"},
+		{2, AvatarKey, ""},
+	}
+	for _, test := range tests {
+		if got := feed.Items[test.item].Custom[test.key]; got != test.want {
+			t.Errorf("item %d's %s = %q, want %q", test.item, test.key, got, test.want)
+		}
+	}
+	enclosures := feed.Items[1].Enclosures
+	if len(enclosures) != 2 || enclosures[0].Length != "1234" || enclosures[1].Length != "" {
+		t.Errorf("enclosure lengths aren't the sizes: %+v", enclosures)
+	}
+}
+
+func TestAddJSONFeedFieldsNotJSON(t *testing.T) {
+	feed := &gofeed.Feed{FeedType: "rss", Items: []*gofeed.Item{{Title: "t"}}}
+	addJSONFeedFields([]byte(`<rss/>`), feed)
+	if feed.Items[0].Custom != nil {
+		t.Errorf("an RSS feed got JSON Feed fields: %v", feed.Items[0].Custom)
+	}
+}
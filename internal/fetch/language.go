@@ -0,0 +1,95 @@
+package fetch
+
+import (
+	"strings"
+
+	"github.com/abadojack/whatlanggo"
+	"github.com/mmcdole/gofeed"
+)
+
+// LanguageKey is set in an item's Custom map to the language it's written
+// in, as an ISO 639-1 code like "en" (or ISO 639-3 for languages without
+// one). Items whose language couldn't be told don't have it.
+const LanguageKey = "golang-rss-client:language"
+
+// languageSample is how much of an item's text its language is told from;
+// more takes longer without telling it any better.
+const languageSample = 1000
+
+// languageNames are the languages that can be told, by code.
+var languageNames = func() map[string]string {
+	names := make(map[string]string)
+	for lang, name := range whatlanggo.Langs {
+		names[languageCode(lang)] = name
+	}
+	return names
+}()
+
+// languageCode is a language's ISO 639-1 code, or its ISO 639-3 one if it
+// has no shorter one.
+func languageCode(lang whatlanggo.Lang) string {
+	if code := lang.Iso6391(); code != "" {
+		return code
+	}
+	return lang.Iso6393()
+}
+
+// LanguageName is the English name of a language by code, or the code itself
+// for one that can't be told.
+func LanguageName(code string) string {
+	if name, ok := languageNames[code]; ok {
+		return name
+	}
+	return code
+}
+
+// declaredLanguage is the language a feed or item says it's in, like en-US,
+// down to the language's code.
+func declaredLanguage(language string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if i := strings.IndexAny(language, "-_"); i >= 0 {
+		language = language[:i]
+	}
+	return language
+}
+
+// detectLanguages notes the language of each of a feed's items, told from
+// its title and text. When the text doesn't give it away (too short, or
+// split between languages), what the item or feed says it's in is taken
+// instead.
+func detectLanguages(feed *gofeed.Feed) {
+	for _, item := range feed.Items {
+		language := ""
+		info := whatlanggo.Detect(itemText(item))
+		if info.IsReliable() {
+			language = languageCode(info.Lang)
+		}
+		if language == "" && item.DublinCoreExt != nil && len(item.DublinCoreExt.Language) > 0 {
+			language = declaredLanguage(item.DublinCoreExt.Language[0])
+		}
+		if language == "" {
+			language = declaredLanguage(feed.Language)
+		}
+		if language == "" {
+			continue
+		}
+		if item.Custom == nil {
+			item.Custom = make(map[string]string)
+		}
+		item.Custom[LanguageKey] = language
+	}
+}
+
+// itemText is the start of an item's title and text, without the markup.
+func itemText(item *gofeed.Item) string {
+	text := item.Title + "\n" + strings.Join(strings.Fields(bodyText(item)), " ")
+	if len(text) > languageSample {
+		// cut at a rune boundary
+		cut := languageSample
+		for cut > 0 && text[cut]&0xc0 == 0x80 {
+			cut--
+		}
+		text = text[:cut]
+	}
+	return text
+}
@@ -0,0 +1,65 @@
+package fetch
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/mmcdole/gofeed"
+	ext "github.com/mmcdole/gofeed/extensions"
+)
+
+func TestDeclaredLanguage(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"en", "en"},
+		{"en-US", "en"},
+		{" pt_BR ", "pt"},
+		{"DE", "de"},
+		{"", ""},
+	}
+	for _, test := range tests {
+		if got := declaredLanguage(test.in); got != test.want {
+			t.Errorf("declaredLanguage(%q) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
+
+func TestDetectLanguages(t *testing.T) {
+	german := "Die Bundesregierung hat am Mittwoch beschlossen, dass die neuen Regeln für den Straßenverkehr ab dem kommenden Jahr gelten sollen."
+	english := "The government decided on Wednesday that the new rules for road traffic should apply from next year onwards."
+	tests := []struct {
+		name         string
+		item         gofeed.Item
+		feedLanguage string
+		want         string
+	}{
+		{"told from the text", gofeed.Item{Title: "Neue Regeln", Description: "<p>" + german + "</p>"}, "en-US", "de"},
+		{"told over the feed's", gofeed.Item{Title: "New rules", Content: english}, "de", "en"},
+		{"item's own when the text is too short", gofeed.Item{Title: "Ok", DublinCoreExt: &ext.DublinCoreExtension{Language: []string{"fr-CA"}}}, "en", "fr"},
+		{"feed's when the text is too short", gofeed.Item{Title: "Ok"}, "nl-BE", "nl"},
+		{"none", gofeed.Item{Title: "Ok"}, "", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			item := test.item
+			detectLanguages(&gofeed.Feed{Language: test.feedLanguage, Items: []*gofeed.Item{&item}})
+			if got := item.Custom[LanguageKey]; got != test.want {
+				t.Errorf("language %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestItemText(t *testing.T) {
+	item := &gofeed.Item{Title: "T", Description: strings.Repeat("é", languageSample)}
+	text := itemText(item)
+	if len(text) > languageSample {
+		t.Errorf("sample is %d bytes, more than %d", len(text), languageSample)
+	}
+	if !strings.HasPrefix(text, "T\né") || !utf8.ValidString(text) {
+		t.Errorf("sample isn't cut at a character: %q", text[len(text)-4:])
+	}
+}
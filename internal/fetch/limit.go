@@ -0,0 +1,111 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// errStopping is what a fetch fails with when it would start once the
+// fetches running are being waited for.
+var errStopping = errors.New("not fetching, the reader is stopping")
+
+// Limiter bounds how many fetches run at once, and keeps count of the ones
+// running so they can be waited for. It's shared by every copy of the Fetcher
+// it's set on; a nil *Limiter doesn't limit or count anything.
+type Limiter struct {
+	// slots is nil when there's no limit
+	slots chan struct{}
+
+	mu      sync.Mutex
+	running int
+	// stopping is set once Wait has begun, after which no fetch starts
+	stopping bool
+	// idle is closed when the last fetch running finishes, if Wait is
+	// waiting for it
+	idle chan struct{}
+}
+
+// NewLimiter lets n fetches run at once, or any number if n isn't positive.
+func NewLimiter(n int) *Limiter {
+	if n <= 0 {
+		return &Limiter{}
+	}
+	return &Limiter{slots: make(chan struct{}, n)}
+}
+
+// acquire waits for a free slot, giving up when ctx is done or once Wait has
+// begun.
+func (l *Limiter) acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	err := ctx.Err()
+	l.mu.Lock()
+	if err == nil && l.stopping {
+		err = errStopping
+	}
+	if err == nil {
+		l.running++
+	}
+	l.mu.Unlock()
+	if err != nil {
+		// a slot and the end of ctx can come at once
+		if l.slots != nil {
+			<-l.slots
+		}
+		return err
+	}
+	return nil
+}
+
+func (l *Limiter) release() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	l.running--
+	if l.running == 0 && l.idle != nil {
+		close(l.idle)
+		l.idle = nil
+	}
+	l.mu.Unlock()
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// Wait waits up to timeout for the fetches running to finish, so the feeds
+// they were caching aren't cut off halfway. It reports whether they did. No
+// fetch starts once it's begun.
+func (l *Limiter) Wait(timeout time.Duration) bool {
+	if l == nil {
+		return true
+	}
+	l.mu.Lock()
+	l.stopping = true
+	if l.running == 0 {
+		l.mu.Unlock()
+		return true
+	}
+	if l.idle == nil {
+		l.idle = make(chan struct{})
+	}
+	idle := l.idle
+	l.mu.Unlock()
+
+	select {
+	case <-idle:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
@@ -0,0 +1,99 @@
+package fetch
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLimiterLimits(t *testing.T) {
+	l := NewLimiter(2)
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := l.acquire(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	short, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := l.acquire(short); err != context.DeadlineExceeded {
+		t.Errorf("a third fetch started with two slots: %v", err)
+	}
+	l.release()
+	if err := l.acquire(ctx); err != nil {
+		t.Errorf("a freed slot wasn't taken: %v", err)
+	}
+}
+
+func TestLimiterWait(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+	}{
+		{"limited", 2},
+		{"unlimited", 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l := NewLimiter(test.n)
+			ctx := context.Background()
+			if err := l.acquire(ctx); err != nil {
+				t.Fatal(err)
+			}
+			if l.Wait(10 * time.Millisecond) {
+				t.Error("Wait didn't wait for the fetch running")
+			}
+			if err := l.acquire(ctx); err != errStopping {
+				t.Errorf("a fetch started once Wait had begun: %v", err)
+			}
+
+			go func() {
+				time.Sleep(10 * time.Millisecond)
+				l.release()
+			}()
+			if !l.Wait(time.Second) {
+				t.Error("Wait gave up on a fetch that finished")
+			}
+			if !l.Wait(time.Millisecond) {
+				t.Error("Wait with nothing running didn't return at once")
+			}
+		})
+	}
+}
+
+func TestLimiterWaitRace(t *testing.T) {
+	l := NewLimiter(4)
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if l.acquire(ctx) == nil {
+				time.Sleep(time.Millisecond)
+				l.release()
+			}
+		}()
+	}
+	if !l.Wait(5 * time.Second) {
+		t.Error("Wait gave up")
+	}
+	wg.Wait()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.running != 0 {
+		t.Errorf("%d fetches still counted as running", l.running)
+	}
+}
+
+func TestNilLimiter(t *testing.T) {
+	var l *Limiter
+	if err := l.acquire(context.Background()); err != nil {
+		t.Error(err)
+	}
+	l.release()
+	if !l.Wait(0) {
+		t.Error("a nil Limiter waited")
+	}
+}
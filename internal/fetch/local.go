@@ -0,0 +1,96 @@
+package fetch
+
+import (
+	"context"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/homielabs/golang-rss-client/internal/render"
+	"github.com/mmcdole/gofeed"
+)
+
+// Source reads a feed that isn't downloaded from its URL, but made from what
+// the URL names. cached is the feed as it was last read, if it's in the
+// cache, for sources that only read what's new.
+type Source func(ctx context.Context, source *url.URL, cached *gofeed.Feed) (*gofeed.Feed, error)
+
+// localSources are the feeds made from things on this machine, by the scheme
+// of the URL they're subscribed to by.
+var localSources = map[string]Source{
+	"bookmarks": readBookmarks,
+	"dir":       readDir,
+}
+
+// source is the Source for a feed URL's scheme, if it's not one to download.
+func (f Fetcher) source(feedUrl string) (Source, *url.URL, bool) {
+	u, err := url.Parse(feedUrl)
+	if err != nil {
+		return nil, nil, false
+	}
+	if source, ok := f.Sources[u.Scheme]; ok {
+		return source, u, true
+	}
+	source, ok := localSources[u.Scheme]
+	return source, u, ok
+}
+
+// IsLocal reports whether a feed URL is one of the sources' rather than one
+// to download.
+func (f Fetcher) IsLocal(feedUrl string) bool {
+	_, _, ok := f.source(feedUrl)
+	return ok
+}
+
+// fetchLocal reads a feed from its source, then treats it the way downloaded
+// ones are. A source that can't be read gives back the cached copy along with
+// the error, if there is one.
+func (f Fetcher) fetchLocal(ctx context.Context, feedUrl string) (*gofeed.Feed, error) {
+	source, u, _ := f.source(feedUrl)
+	ctx, cancel := context.WithTimeout(ctx, f.Timeout)
+	defer cancel()
+
+	var cached *gofeed.Feed
+	if f.Cache != nil {
+		cached, _ = f.Cache.Load(feedUrl)
+	}
+	start := time.Now()
+	feed, err := source(ctx, u, cached)
+	if err != nil {
+		return cached, err
+	}
+	log.Printf("timing: %s read in %s", feedUrl, time.Since(start))
+	f.Stats.markFetched(feedUrl)
+	render.SanitizeFeed(feed)
+	detectLanguages(feed)
+	rateAdvisories(feed)
+	noteIncidents(feed)
+	fingerprintItems(feed)
+	f.normalizeDates(feedUrl, feed)
+	keepRevisions(feed, cached)
+	if f.MaxItems > 0 && len(feed.Items) > f.MaxItems {
+		feed.Items = feed.Items[:f.MaxItems]
+	}
+	if f.Cache != nil {
+		if err := f.Cache.Save(feedUrl, feed); err != nil {
+			log.Println(err)
+		}
+	}
+	return feed, nil
+}
+
+// localPath is the path a local source's URL names, with a leading ~ for the
+// home directory.
+func localPath(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return filepath.FromSlash(path)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, filepath.FromSlash(strings.TrimPrefix(path, "~")))
+}
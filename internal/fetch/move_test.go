@@ -0,0 +1,82 @@
+package fetch
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestMoveFeedWhileFetching moves a feed back and forth while other fetches
+// of it are running, for go test -race to catch them sharing a map unguarded.
+func TestMoveFeedWhileFetching(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(time.Millisecond)
+		io.WriteString(w, `<rss version="2.0"><channel><title>t</title>`+
+			`<item><title>i</title><pubDate>Sun, 01 Mar 2026 12:00:00</pubDate></item></channel></rss>`)
+	}))
+	defer server.Close()
+	oldUrl, newUrl := server.URL+"/old", server.URL+"/new"
+
+	proxy, err := NewProxy(ProxyConfig{Feeds: map[string]string{oldUrl: DirectProxy}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := Fetcher{
+		Timeout:       5 * time.Second,
+		Stats:         NewStats(),
+		Proxy:         proxy,
+		FeedTimezones: map[string]*time.Location{oldUrl: time.FixedZone("JST", 9*60*60)},
+	}
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		// each fetch runs on its own copy, as the UI's commands do
+		fetcher := f
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				for _, feedUrl := range []string{oldUrl, newUrl} {
+					if _, err := fetcher.Fetch(context.Background(), feedUrl); err != nil {
+						t.Error(err)
+						return
+					}
+				}
+			}
+		}()
+	}
+	from, to := oldUrl, newUrl
+	for i := 0; i < 51; i++ {
+		f.MoveFeed(from, to)
+		from, to = to, from
+		time.Sleep(time.Millisecond)
+	}
+	close(stop)
+	wg.Wait()
+
+	if _, ok := f.FeedTimezones[newUrl]; !ok || len(f.FeedTimezones) != 1 {
+		t.Errorf("time zones %v after moving, want just %s's", f.FeedTimezones, newUrl)
+	}
+	if proxy.Transport(newUrl) == proxy.transport {
+		t.Errorf("%s isn't fetched through the proxy it was moved with", newUrl)
+	}
+	f.MoveFeed(newUrl, "")
+	if len(f.FeedTimezones) != 0 || proxy.Transport(newUrl) != proxy.transport {
+		t.Error("a dropped feed's time zone or proxy was kept")
+	}
+}
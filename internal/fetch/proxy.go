@@ -0,0 +1,144 @@
+package fetch
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// DirectProxy, as a feed's proxy, fetches it without one.
+const DirectProxy = "direct"
+
+// ProxyConfig names the proxies feeds are fetched through. Those left empty
+// are the environment's: HTTP_PROXY, HTTPS_PROXY and NO_PROXY, or their
+// lowercase forms.
+type ProxyConfig struct {
+	// HTTP and HTTPS are the proxies of http: and https: feeds, as URLs or
+	// host:port.
+	HTTP  string
+	HTTPS string
+	// SOCKS5 is a SOCKS5 proxy, like Tor's at 127.0.0.1:9050, taking the
+	// place of both.
+	SOCKS5 string
+	// Feeds are the proxies of particular feeds, by URL: http://, https://
+	// or socks5:// URLs, or DirectProxy for none. The environment's NO_PROXY
+	// doesn't apply to them.
+	Feeds map[string]string
+}
+
+// Proxy has what feeds are fetched over, through the proxies configured for
+// them. Fetches run in their own goroutines while feeds move, hence the lock.
+// A nil Proxy leaves it to the environment, as net/http does.
+type Proxy struct {
+	transport http.RoundTripper
+	mu        sync.RWMutex
+	feeds     map[string]http.RoundTripper
+}
+
+// NewProxy checks the proxies configured are ones that can be used, and sets
+// up fetching through them.
+func NewProxy(config ProxyConfig) (*Proxy, error) {
+	env := httpproxy.FromEnvironment()
+	for _, configured := range []struct {
+		name  string
+		value string
+		proxy *string
+	}{
+		{"httpProxy", config.HTTP, &env.HTTPProxy},
+		{"httpsProxy", config.HTTPS, &env.HTTPSProxy},
+	} {
+		if configured.value == "" {
+			continue
+		}
+		if _, err := proxyURL(configured.value); err != nil {
+			return nil, fmt.Errorf("%s: %w", configured.name, err)
+		}
+		*configured.proxy = configured.value
+	}
+	if config.SOCKS5 != "" {
+		socks := config.SOCKS5
+		if !strings.Contains(socks, "://") {
+			socks = "socks5://" + socks
+		}
+		if u, err := proxyURL(socks); err != nil {
+			return nil, fmt.Errorf("socks5Proxy: %w", err)
+		} else if u.Scheme != "socks5" {
+			return nil, fmt.Errorf("socks5Proxy: %q isn't a SOCKS5 proxy", config.SOCKS5)
+		}
+		env.HTTPProxy, env.HTTPSProxy = socks, socks
+	}
+	proxyFunc := env.ProxyFunc()
+	p := &Proxy{
+		transport: newTransport(func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}),
+		feeds: make(map[string]http.RoundTripper),
+	}
+	for feedUrl, proxy := range config.Feeds {
+		if proxy == DirectProxy {
+			p.feeds[feedUrl] = newTransport(nil)
+			continue
+		}
+		u, err := proxyURL(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("feedProxies: %s: %w", feedUrl, err)
+		}
+		p.feeds[feedUrl] = newTransport(http.ProxyURL(u))
+	}
+	return p, nil
+}
+
+// proxyURL parses a proxy's URL, taking host:port for an HTTP proxy.
+func proxyURL(proxy string) (*url.URL, error) {
+	if !strings.Contains(proxy, "://") {
+		proxy = "http://" + proxy
+	}
+	u, err := url.Parse(proxy)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("%q isn't a proxy's URL", proxy)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5" {
+		return nil, fmt.Errorf("%q isn't an http, https or socks5 proxy", proxy)
+	}
+	return u, nil
+}
+
+// newTransport is the default transport, going through the proxy given.
+func newTransport(proxy func(*http.Request) (*url.URL, error)) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+	return transport
+}
+
+// Transport is what a feed is fetched over, or nil for the default.
+func (p *Proxy) Transport(feedUrl string) http.RoundTripper {
+	if p == nil {
+		return nil
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if transport, ok := p.feeds[feedUrl]; ok {
+		return transport
+	}
+	return p.transport
+}
+
+// MoveFeed has a feed that's moved to a new URL fetched through the proxy its
+// old URL was, if it had one of its own. An empty newUrl forgets the feed's.
+func (p *Proxy) MoveFeed(oldUrl string, newUrl string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if transport, ok := p.feeds[oldUrl]; ok {
+		delete(p.feeds, oldUrl)
+		if newUrl != "" {
+			p.feeds[newUrl] = transport
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package fetch
+
+import (
+	"bytes"
+	"log"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mmcdole/gofeed"
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// recoveryPass is one way of patching up a feed that doesn't parse.
+type recoveryPass struct {
+	name string
+	fix  func(body []byte, contentType string) []byte
+}
+
+// recoveryPasses are tried in order, each on top of the ones before it.
+var recoveryPasses = []recoveryPass{
+	{"stripping control characters", stripControlChars},
+	{"escaping stray ampersands", escapeAmpersands},
+	{"detecting the charset", convertCharset},
+}
+
+// parseFeed parses a feed, falling back to the recovery passes if it's
+// broken. The error is the one from the unpatched feed, since that's what's
+// actually wrong with it.
+func parseFeed(feedUrl string, body []byte, contentType string) (*gofeed.Feed, error) {
+	feed, err := gofeed.NewParser().Parse(bytes.NewReader(body))
+	if err == nil {
+		return feed, nil
+	}
+
+	var applied []string
+	for _, pass := range recoveryPasses {
+		fixed := pass.fix(body, contentType)
+		if bytes.Equal(fixed, body) {
+			continue
+		}
+		body = fixed
+		applied = append(applied, pass.name)
+		if recovered, retryErr := gofeed.NewParser().Parse(bytes.NewReader(body)); retryErr == nil {
+			log.Printf("recovered: %s (%s) parsed after %s", feedUrl, err, strings.Join(applied, ", "))
+			return recovered, nil
+		}
+	}
+	return nil, err
+}
+
+// stripControlChars drops the C0 control characters XML doesn't allow
+// anywhere, even escaped. Tab, newline and carriage return stay. It works
+// byte by byte, since bytes that aren't valid UTF-8 are left to convertCharset.
+func stripControlChars(body []byte, _ string) []byte {
+	stripped := make([]byte, 0, len(body))
+	for _, b := range body {
+		if b < 0x20 && b != '\t' && b != '\n' && b != '\r' {
+			continue
+		}
+		stripped = append(stripped, b)
+	}
+	return stripped
+}
+
+// entityRef matches what may follow an & that's the start of a reference.
+var entityRef = regexp.MustCompile(`^(#[0-9]+|#x[0-9a-fA-F]+|[A-Za-z][A-Za-z0-9._-]*);`)
+
+// escapeAmpersands escapes every & that doesn't start an entity or character
+// reference, as in unescaped query strings.
+func escapeAmpersands(body []byte, _ string) []byte {
+	var out bytes.Buffer
+	for {
+		i := bytes.IndexByte(body, '&')
+		if i < 0 {
+			out.Write(body)
+			return out.Bytes()
+		}
+		out.Write(body[:i])
+		if entityRef.Match(body[i+1:]) {
+			out.WriteByte('&')
+		} else {
+			out.WriteString("&amp;")
+		}
+		body = body[i+1:]
+	}
+}
+
+// xmlEncoding matches the encoding in the XML declaration.
+var xmlEncoding = regexp.MustCompile(`^(\s*<\?xml[^>]*encoding=)("[^"]*"|'[^']*')`)
+
+// convertCharset re-decodes a feed that isn't valid UTF-8 despite claiming
+// to be, going by the Content-Type and a sniff of the bytes, and falling back
+// to Windows-1252 (which covers Latin-1). The declaration is rewritten so
+// the converted feed isn't decoded again.
+func convertCharset(body []byte, contentType string) []byte {
+	if utf8.Valid(body) {
+		return body
+	}
+	encoding, name, _ := charset.DetermineEncoding(body, contentType)
+	if name == "utf-8" {
+		encoding = charmap.Windows1252
+	}
+	decoded, err := encoding.NewDecoder().Bytes(body)
+	if err != nil {
+		return body
+	}
+	return xmlEncoding.ReplaceAll(decoded, []byte(`${1}"utf-8"`))
+}
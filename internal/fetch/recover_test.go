@@ -0,0 +1,102 @@
+package fetch
+
+import (
+	"io"
+	"log"
+	"os"
+	"testing"
+)
+
+func TestStripControlChars(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"clean", "<a>b</a>", "<a>b</a>"},
+		{"whitespace kept", "<a>\tb\r\n</a>", "<a>\tb\r\n</a>"},
+		{"controls dropped", "<a>\x00b\x0b\x1f</a>", "<a>b</a>"},
+		{"invalid UTF-8 left alone", "<a>\xe9</a>", "<a>\xe9</a>"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := string(stripControlChars([]byte(test.in), "")); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestEscapeAmpersands(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"no ampersands", "no ampersands"},
+		{"?a=1&b=2", "?a=1&amp;b=2"},
+		{"fish & chips", "fish &amp; chips"},
+		{"&amp; &lt; &#38; &#x26; &nbsp;", "&amp; &lt; &#38; &#x26; &nbsp;"},
+		{"&#xZZ; &;", "&amp;#xZZ; &amp;;"},
+		{"trailing &", "trailing &amp;"},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			if got := string(escapeAmpersands([]byte(test.in), "")); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestConvertCharset(t *testing.T) {
+	tests := []struct {
+		name        string
+		in          string
+		contentType string
+		want        string
+	}{
+		{"valid UTF-8 untouched", `<?xml version="1.0" encoding="iso-8859-1"?><a>é</a>`, "", `<?xml version="1.0" encoding="iso-8859-1"?><a>é</a>`},
+		{"Latin-1 by default", "<a>caf\xe9</a>", "", "<a>café</a>"},
+		{"Windows-1252 quotes", "<a>\x93hi\x94</a>", "", "<a>“hi”</a>"},
+		{"declaration rewritten", `<?xml version="1.0" encoding='utf-8'?><a>caf` + "\xe9</a>", "", `<?xml version="1.0" encoding="utf-8"?><a>café</a>`},
+		{"charset from the Content-Type", "<a>\xc1\xe2</a>", "text/xml; charset=koi8-r", "<a>аБ</a>"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := string(convertCharset([]byte(test.in), test.contentType)); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseFeedRecovers(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"valid", `<rss version="2.0"><channel><title>ok</title></channel></rss>`, "ok"},
+		{"control characters", "<rss version=\"2.0\"><channel><title>o\x01k</title></channel></rss>", "ok"},
+		{"stray ampersand", `<rss version="2.0"><channel><title>a & b</title></channel></rss>`, "a & b"},
+		{"undeclared Latin-1", "<rss version=\"2.0\"><channel><title>caf\xe9 & co</title></channel></rss>", "café & co"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			feed, err := parseFeed("http://example.com", []byte(test.body), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if feed.Title != test.want {
+				t.Errorf("title %q, want %q", feed.Title, test.want)
+			}
+		})
+	}
+
+	if _, err := parseFeed("http://example.com", []byte("<html><body>not a feed"), ""); err == nil {
+		t.Error("a page that isn't a feed parsed")
+	}
+}
@@ -0,0 +1,48 @@
+package fetch
+
+import (
+	"errors"
+	"log"
+	"net/http"
+)
+
+// maxRedirects is as many redirects as net/http follows by default.
+const maxRedirects = 10
+
+// redirectTracker follows redirects like the default client does, logging
+// each one and remembering whether they were all permanent.
+type redirectTracker struct {
+	hops      int
+	temporary bool
+	last      string
+}
+
+// client follows redirects with the tracker, over transport (nil for the
+// default).
+func (t *redirectTracker) client(transport http.RoundTripper) *http.Client {
+	return &http.Client{CheckRedirect: t.checkRedirect, Transport: transport}
+}
+
+func (t *redirectTracker) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return errors.New("stopped after 10 redirects")
+	}
+	status := req.Response.StatusCode
+	log.Printf("redirect: %s -> %s (%d)", via[len(via)-1].URL, req.URL, status)
+
+	t.hops++
+	if status != http.StatusMovedPermanently && status != http.StatusPermanentRedirect {
+		t.temporary = true
+	}
+	t.last = req.URL.String()
+	return nil
+}
+
+// movedTo is where the feed now lives, if only permanent redirects were
+// followed to get there.
+func (t *redirectTracker) movedTo() string {
+	if t.hops == 0 || t.temporary {
+		return ""
+	}
+	return t.last
+}
@@ -0,0 +1,82 @@
+package fetch
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// PreviousKey is set in an item's Custom map to its Revision before the feed
+// last re-published it, as JSON. Only items re-published under the same GUID
+// with a later date and different text have it.
+const PreviousKey = "golang-rss-client:previous"
+
+// Revision is a version of an item, as a feed once had it.
+type Revision struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Content     string `json:"content,omitempty"`
+	// Updated is when the feed said the version was last changed
+	Updated time.Time `json:"updated"`
+}
+
+// PreviousRevision is the version of an item before it was re-published, if
+// it has been.
+func PreviousRevision(item *gofeed.Item) (Revision, bool) {
+	var revision Revision
+	data, ok := item.Custom[PreviousKey]
+	if !ok || json.Unmarshal([]byte(data), &revision) != nil {
+		return revision, false
+	}
+	return revision, true
+}
+
+// keepRevisions notes, in each item of a fetched feed that's been
+// re-published since the cached copy, the version the cached copy has. Items
+// that haven't changed keep the version noted in the cached copy, if there
+// was one.
+func keepRevisions(feed *gofeed.Feed, cached *gofeed.Feed) {
+	if cached == nil {
+		return
+	}
+	before := make(map[string]*gofeed.Item, len(cached.Items))
+	for _, item := range cached.Items {
+		if item.GUID != "" {
+			before[item.GUID] = item
+		}
+	}
+	for _, item := range feed.Items {
+		old, ok := before[item.GUID]
+		if item.GUID == "" || !ok {
+			continue
+		}
+		changed := item.Title != old.Title || item.Description != old.Description || item.Content != old.Content
+		var previous string
+		switch {
+		case changed && itemTime(item).After(itemTime(old)):
+			data, err := json.Marshal(Revision{
+				Title:       old.Title,
+				Description: old.Description,
+				Content:     old.Content,
+				Updated:     itemTime(old),
+			})
+			if err != nil {
+				continue
+			}
+			previous = string(data)
+		case changed:
+			// changed without a later date, which leaves what it replaced
+			// in doubt
+			continue
+		default:
+			if previous, ok = old.Custom[PreviousKey]; !ok {
+				continue
+			}
+		}
+		if item.Custom == nil {
+			item.Custom = make(map[string]string)
+		}
+		item.Custom[PreviousKey] = previous
+	}
+}
@@ -0,0 +1,10 @@
+//go:build cgo
+// +build cgo
+
+package fetch
+
+// Firefox keeps its bookmarks in a SQLite database, and go-sqlite3 needs cgo
+import _ "github.com/mattn/go-sqlite3"
+
+// haveSQLite is whether this build can open SQLite databases.
+const haveSQLite = true
@@ -0,0 +1,7 @@
+//go:build !cgo
+// +build !cgo
+
+package fetch
+
+// haveSQLite is whether this build can open SQLite databases.
+const haveSQLite = false
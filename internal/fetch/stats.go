@@ -0,0 +1,104 @@
+package fetch
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// FeedStats is what's been downloaded for a single feed.
+type FeedStats struct {
+	Requests int
+	Bytes    int64
+	// LastFetched is when the feed was last fetched successfully.
+	LastFetched time.Time
+}
+
+// Stats tallies requests and downloaded bytes per feed URL for the current
+// session. Fetches run in their own goroutines, hence the lock. A nil *Stats
+// records nothing.
+type Stats struct {
+	mu    sync.Mutex
+	feeds map[string]*FeedStats
+}
+
+func NewStats() *Stats {
+	return &Stats{feeds: make(map[string]*FeedStats)}
+}
+
+func (s *Stats) entry(feedUrl string) *FeedStats {
+	stats, ok := s.feeds[feedUrl]
+	if !ok {
+		stats = &FeedStats{}
+		s.feeds[feedUrl] = stats
+	}
+	return stats
+}
+
+func (s *Stats) AddRequest(feedUrl string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entry(feedUrl).Requests++
+}
+
+func (s *Stats) AddBytes(feedUrl string, n int64) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entry(feedUrl).Bytes += n
+}
+
+func (s *Stats) markFetched(feedUrl string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entry(feedUrl).LastFetched = time.Now()
+}
+
+func (s *Stats) Get(feedUrl string) FeedStats {
+	if s == nil {
+		return FeedStats{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if stats, ok := s.feeds[feedUrl]; ok {
+		return *stats
+	}
+	return FeedStats{}
+}
+
+// countingReader reports every chunk read from a response body to the stats.
+type countingReader struct {
+	reader  io.Reader
+	feedUrl string
+	stats   *Stats
+}
+
+func (r countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.stats.AddBytes(r.feedUrl, int64(n))
+	return n, err
+}
+
+// MoveFeed carries a feed's stats over to the new URL it's moved to, or
+// forgets them if newUrl is empty.
+func (s *Stats) MoveFeed(oldUrl string, newUrl string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if stats, ok := s.feeds[oldUrl]; ok {
+		delete(s.feeds, oldUrl)
+		if newUrl != "" {
+			s.feeds[newUrl] = stats
+		}
+	}
+}
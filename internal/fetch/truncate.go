@@ -0,0 +1,88 @@
+package fetch
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"unicode"
+)
+
+// truncateFeed stops reading an XML feed once maxItems <item>/<entry>
+// elements have gone by, closing whatever elements are still open so the
+// result is still a well-formed document. That way huge archive feeds are
+// never read (or parsed) past the point we'd throw items away anyway.
+//
+// The original bytes are passed through untouched; if the feed isn't XML, or
+// the tokenizer trips over something, the whole body is handed back for gofeed
+// to deal with.
+func truncateFeed(body io.Reader, maxItems int) io.Reader {
+	if maxItems <= 0 {
+		return body
+	}
+
+	br := bufio.NewReader(body)
+	// JSON feeds can't be cut at the token level; they're capped after parsing
+	if isJSON(br) {
+		return br
+	}
+
+	var consumed bytes.Buffer
+	decoder := xml.NewDecoder(io.TeeReader(br, &consumed))
+	// the bytes are only being scanned for element boundaries, so there's no
+	// need to actually decode other charsets
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+
+	var open []xml.Name
+	items := 0
+	for items < maxItems {
+		token, err := decoder.RawToken()
+		if err != nil {
+			return io.MultiReader(&consumed, br)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			open = append(open, t.Name)
+		case xml.EndElement:
+			if len(open) > 0 {
+				open = open[:len(open)-1]
+			}
+			if t.Name.Local == "item" || t.Name.Local == "entry" {
+				items++
+			}
+		}
+	}
+
+	// the decoder reads ahead, so only keep what it has actually tokenized
+	var closing bytes.Buffer
+	for i := len(open) - 1; i >= 0; i-- {
+		name := open[i].Local
+		if open[i].Space != "" {
+			name = open[i].Space + ":" + name
+		}
+		fmt.Fprintf(&closing, "</%s>", name)
+	}
+	return io.MultiReader(
+		bytes.NewReader(consumed.Bytes()[:decoder.InputOffset()]),
+		&closing,
+	)
+}
+
+// isJSON peeks past any leading whitespace (and a BOM) to see if the body
+// starts like a JSON document.
+func isJSON(br *bufio.Reader) bool {
+	for n := 1; ; n++ {
+		peeked, err := br.Peek(n)
+		if err != nil {
+			return false
+		}
+		r := peeked[n-1]
+		if unicode.IsSpace(rune(r)) || r == 0xef || r == 0xbb || r == 0xbf {
+			continue
+		}
+		return r == '{'
+	}
+}
@@ -0,0 +1,60 @@
+package fetch
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestTruncateFeed(t *testing.T) {
+	rss := `<?xml version="1.0"?><rss version="2.0"><channel><title>t</title>` +
+		`<item><title>1</title></item><item><title>2</title></item><item><title>3</title></item>` +
+		`</channel></rss>`
+	atom := `<feed xmlns="http://www.w3.org/2005/Atom"><title>t</title>` +
+		`<entry><title>1</title></entry><entry><title>2</title></entry>` +
+		`</feed>`
+	json := `  {"version": "https://jsonfeed.org/version/1.1", "title": "t",` +
+		` "items": [{"id": "1"}, {"id": "2"}]}`
+
+	tests := []struct {
+		name     string
+		body     string
+		maxItems int
+		want     int
+	}{
+		{"no limit", rss, 0, 3},
+		{"rss cut", rss, 2, 2},
+		{"rss under limit", rss, 5, 3},
+		{"rss exactly at limit", rss, 3, 3},
+		{"atom cut", atom, 1, 1},
+		{"json passed through", json, 1, 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			body, err := io.ReadAll(truncateFeed(strings.NewReader(test.body), test.maxItems))
+			if err != nil {
+				t.Fatal(err)
+			}
+			feed, err := gofeed.NewParser().ParseString(string(body))
+			if err != nil {
+				t.Fatalf("truncated feed doesn't parse: %v\n%s", err, body)
+			}
+			if len(feed.Items) != test.want {
+				t.Errorf("got %d items, want %d", len(feed.Items), test.want)
+			}
+		})
+	}
+}
+
+func TestTruncateFeedNotXML(t *testing.T) {
+	const body = "<html><p>unclosed & broken"
+	got, err := io.ReadAll(truncateFeed(strings.NewReader(body), 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != body {
+		t.Errorf("got %q, want the body handed back untouched", got)
+	}
+}
@@ -0,0 +1,69 @@
+package greader
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/homielabs/golang-rss-client/internal/backend"
+	"github.com/mmcdole/gofeed"
+)
+
+// Name is "greader". The Syncer is the backend of the server's feeds, as it
+// keeps the list of them for starting up without the server.
+func (s *Syncer) Name() string {
+	return Scheme
+}
+
+// Owns reports whether a feed URL is one of the server's.
+func (s *Syncer) Owns(feedUrl string) bool {
+	return IsFeedURL(feedUrl)
+}
+
+// List is the feeds subscribed to on the server, in their folders. When the
+// server can't be reached, the ones it listed last time come back with the
+// error.
+func (s *Syncer) List(ctx context.Context) ([]backend.Subscription, error) {
+	listed, err := s.Subscriptions(ctx)
+	var subscriptions []backend.Subscription
+	for _, subscription := range listed {
+		subscriptions = append(subscriptions, backend.Subscription{
+			URL:    FeedURL(subscription.ID),
+			Groups: subscription.Folders,
+		})
+	}
+	return subscriptions, err
+}
+
+// Fetch reads one of the server's feeds.
+func (s *Syncer) Fetch(ctx context.Context, source *url.URL, cached *gofeed.Feed) (*gofeed.Feed, error) {
+	return s.client.ReadFeed(ctx, source, cached)
+}
+
+// itemIDs are the IDs of items read from the server.
+func itemIDs(items []*gofeed.Item) []string {
+	var ids []string
+	for _, item := range items {
+		ids = append(ids, item.GUID)
+	}
+	return ids
+}
+
+// MarkRead marks items read, or unread, on the server straight away, rather
+// than at the next sync.
+func (s *Syncer) MarkRead(ctx context.Context, items []*gofeed.Item, read bool) error {
+	return s.client.SetRead(ctx, itemIDs(items), read)
+}
+
+// Star stars or unstars items on the server.
+func (s *Syncer) Star(ctx context.Context, items []*gofeed.Item, starred bool) error {
+	return s.client.SetStarred(ctx, itemIDs(items), starred)
+}
+
+// Subscribe subscribes to a feed on the server.
+func (s *Syncer) Subscribe(ctx context.Context, feedUrl string) (string, error) {
+	streamID, err := s.client.Subscribe(ctx, feedUrl)
+	if err != nil {
+		return "", err
+	}
+	return FeedURL(streamID), nil
+}
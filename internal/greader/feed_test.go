@@ -0,0 +1,56 @@
+package greader
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestMerge(t *testing.T) {
+	at := func(guid string, day int) *gofeed.Item {
+		published := time.Date(2026, 3, day, 12, 0, 0, 0, time.UTC)
+		return &gofeed.Item{GUID: guid, PublishedParsed: &published}
+	}
+	undated := &gofeed.Item{GUID: "undated"}
+	tests := []struct {
+		name   string
+		fresh  []*gofeed.Item
+		cached []*gofeed.Item
+		want   []string
+	}{
+		{"nothing cached", []*gofeed.Item{at("b", 2), at("a", 1)}, nil, []string{"b", "a"}},
+		{"cached items kept", []*gofeed.Item{at("c", 3)}, []*gofeed.Item{at("b", 2), at("a", 1)}, []string{"c", "b", "a"}},
+		{"fresh copy wins", []*gofeed.Item{at("b", 5)}, []*gofeed.Item{at("b", 2), at("a", 1)}, []string{"b", "a"}},
+		{"newest first", []*gofeed.Item{at("a", 1)}, []*gofeed.Item{at("b", 2)}, []string{"b", "a"}},
+		{"undated last", []*gofeed.Item{undated, at("a", 1)}, []*gofeed.Item{at("b", 2)}, []string{"b", "a", "undated"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			for _, item := range merge(test.fresh, test.cached) {
+				got = append(got, item.GUID)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+
+	t.Run("capped", func(t *testing.T) {
+		var fresh, cached []*gofeed.Item
+		for i := 0; i < streamItems; i++ {
+			fresh = append(fresh, at(fmt.Sprint("fresh", i), 20))
+			cached = append(cached, at(fmt.Sprint("cached", i), 10))
+		}
+		items := merge(fresh, cached)
+		if len(items) != streamItems {
+			t.Fatalf("got %d items, want %d", len(items), streamItems)
+		}
+		if last := items[len(items)-1].GUID; last != fmt.Sprint("fresh", streamItems-1) {
+			t.Errorf("last item %q, want the oldest fresh one", last)
+		}
+	})
+}
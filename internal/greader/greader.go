@@ -0,0 +1,396 @@
+// Package greader talks to servers with the Google Reader API, like FreshRSS,
+// Inoreader and The Old Reader: it reads the feeds subscribed to there and
+// keeps which of their items have been read in step with the server.
+package greader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// The streams and tags that stand for the reader's own states.
+const (
+	readingList = "user/-/state/com.google/reading-list"
+	readTag     = "user/-/state/com.google/read"
+	starredTag  = "user/-/state/com.google/starred"
+)
+
+// itemIDPrefix is what the long form of an item ID starts with; the short
+// form is the same number in decimal.
+const itemIDPrefix = "tag:google.com,2005:reader/item/"
+
+// Config is the `greader` config section.
+type Config struct {
+	// URL is the API's root: https://freshrss.example.net/api/greader.php
+	// for FreshRSS, https://theoldreader.com for The Old Reader and
+	// https://www.inoreader.com for Inoreader. Empty keeps no sync.
+	URL string `mapstructure:"url"`
+	// Username and Password log in; FreshRSS wants its API password here
+	// rather than the web one.
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	// AppID and AppKey identify the reader to servers that want it to,
+	// like Inoreader.
+	AppID  string `mapstructure:"appId"`
+	AppKey string `mapstructure:"appKey"`
+	// SyncEvery is how many minutes go between syncs of the read state.
+	SyncEvery int `mapstructure:"syncEvery"`
+}
+
+// Validate checks there's a server to log in to, and what to log in with.
+func (c Config) Validate() error {
+	if c.URL == "" {
+		return nil
+	}
+	parsed, err := url.Parse(c.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("greader: url %q isn't an http(s) URL", c.URL)
+	}
+	if c.Username == "" || c.Password == "" {
+		return fmt.Errorf("greader: %s needs a username and password", c.URL)
+	}
+	if c.SyncEvery < 1 {
+		return fmt.Errorf("greader: syncEvery should be at least a minute, not %d", c.SyncEvery)
+	}
+	return nil
+}
+
+// Client is logged in to a server, logging in again when the server forgets
+// it.
+type Client struct {
+	config Config
+	http   *http.Client
+
+	mu sync.Mutex
+	// auth is the token logging in gave, token the one edits are made with
+	auth  string
+	token string
+}
+
+// NewClient makes a client for the configured server, reached over
+// transport (nil for the default). It logs in when it's first used.
+func NewClient(config Config, transport http.RoundTripper) *Client {
+	config.URL = strings.TrimSuffix(config.URL, "/")
+	return &Client{config: config, http: &http.Client{Transport: transport}}
+}
+
+// Subscription is a feed subscribed to on the server.
+type Subscription struct {
+	// ID is the feed's stream, like feed/https://example.com/feed.xml.
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	// URL is the feed's own, HTMLURL its site's.
+	URL     string `json:"url"`
+	HTMLURL string `json:"htmlUrl"`
+	// Folders are the labels the feed's filed under.
+	Folders []string `json:"folders,omitempty"`
+}
+
+// Subscriptions lists the feeds subscribed to on the server.
+func (c *Client) Subscriptions(ctx context.Context) ([]Subscription, error) {
+	var list struct {
+		Subscriptions []struct {
+			ID         string `json:"id"`
+			Title      string `json:"title"`
+			URL        string `json:"url"`
+			HTMLURL    string `json:"htmlUrl"`
+			Categories []struct {
+				ID    string `json:"id"`
+				Label string `json:"label"`
+			} `json:"categories"`
+		} `json:"subscriptions"`
+	}
+	if err := c.get(ctx, "/reader/api/0/subscription/list", url.Values{"output": {"json"}}, &list); err != nil {
+		return nil, err
+	}
+	var subscriptions []Subscription
+	for _, s := range list.Subscriptions {
+		subscription := Subscription{ID: s.ID, Title: s.Title, URL: s.URL, HTMLURL: s.HTMLURL}
+		if subscription.URL == "" {
+			subscription.URL = strings.TrimPrefix(s.ID, "feed/")
+		}
+		for _, category := range s.Categories {
+			label := category.Label
+			if label == "" {
+				label = labelName(category.ID)
+			}
+			if label != "" {
+				subscription.Folders = append(subscription.Folders, label)
+			}
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, nil
+}
+
+// UnreadIDs are the long form IDs of the items not read yet, per the server.
+func (c *Client) UnreadIDs(ctx context.Context) (map[string]bool, error) {
+	unread := make(map[string]bool)
+	query := url.Values{
+		"output": {"json"},
+		"s":      {readingList},
+		"xt":     {readTag},
+		"n":      {"10000"},
+	}
+	for {
+		var ids struct {
+			ItemRefs []struct {
+				ID string `json:"id"`
+			} `json:"itemRefs"`
+			Continuation string `json:"continuation"`
+		}
+		if err := c.get(ctx, "/reader/api/0/stream/items/ids", query, &ids); err != nil {
+			return nil, err
+		}
+		for _, ref := range ids.ItemRefs {
+			unread[longID(ref.ID)] = true
+		}
+		if ids.Continuation == "" || len(ids.ItemRefs) == 0 {
+			return unread, nil
+		}
+		query.Set("c", ids.Continuation)
+	}
+}
+
+// editBatch is how many items are marked in one request.
+const editBatch = 250
+
+// SetRead marks items read, or unread, on the server.
+func (c *Client) SetRead(ctx context.Context, ids []string, read bool) error {
+	return c.tag(ctx, readTag, ids, read)
+}
+
+// SetStarred stars or unstars items on the server.
+func (c *Client) SetStarred(ctx context.Context, ids []string, starred bool) error {
+	return c.tag(ctx, starredTag, ids, starred)
+}
+
+// tag adds a tag to items, or takes it off.
+func (c *Client) tag(ctx context.Context, tag string, ids []string, add bool) error {
+	action := "a"
+	if !add {
+		action = "r"
+	}
+	for len(ids) > 0 {
+		batch := ids
+		if len(batch) > editBatch {
+			batch = batch[:editBatch]
+		}
+		ids = ids[len(batch):]
+		form := url.Values{action: {tag}, "i": batch}
+		if _, err := c.edit(ctx, "/reader/api/0/edit-tag", form); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Subscribe subscribes to a feed on the server, returning its stream's ID.
+func (c *Client) Subscribe(ctx context.Context, feedUrl string) (string, error) {
+	body, err := c.edit(ctx, "/reader/api/0/subscription/quickadd", url.Values{"quickadd": {feedUrl}})
+	if err != nil {
+		return "", err
+	}
+	var added struct {
+		StreamID string `json:"streamId"`
+	}
+	if err := json.Unmarshal(body, &added); err != nil {
+		return "", fmt.Errorf("greader: subscribing to %s: %w", feedUrl, err)
+	}
+	if added.StreamID == "" {
+		return "", fmt.Errorf("greader: the server couldn't subscribe to %s", feedUrl)
+	}
+	return added.StreamID, nil
+}
+
+// get fetches an API endpoint's JSON.
+func (c *Client) get(ctx context.Context, path string, query url.Values, into interface{}) error {
+	body, err := c.do(ctx, http.MethodGet, path+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, into); err != nil {
+		return fmt.Errorf("greader: %s: %w", path, err)
+	}
+	return nil
+}
+
+// edit posts a change, with the token edits need, returning the response. A
+// token that's gone stale is swapped for a new one.
+func (c *Client) edit(ctx context.Context, path string, form url.Values) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		token, err := c.editToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		form.Set("T", token)
+		body, err := c.do(ctx, http.MethodPost, path, form)
+		var status statusError
+		if attempt == 0 && asStatus(err, &status) && status.badToken {
+			c.mu.Lock()
+			c.token = ""
+			c.mu.Unlock()
+			continue
+		}
+		return body, err
+	}
+}
+
+// editToken is the token edits are posted with, asked for when there isn't
+// one yet.
+func (c *Client) editToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	token := c.token
+	c.mu.Unlock()
+	if token != "" {
+		return token, nil
+	}
+	body, err := c.do(ctx, http.MethodGet, "/reader/api/0/token", nil)
+	if err != nil {
+		return "", err
+	}
+	token = strings.TrimSpace(string(body))
+	c.mu.Lock()
+	c.token = token
+	c.mu.Unlock()
+	return token, nil
+}
+
+// login swaps the username and password for the auth token requests are
+// made with.
+func (c *Client) login(ctx context.Context) (string, error) {
+	form := url.Values{"Email": {c.config.Username}, "Passwd": {c.config.Password}}
+	req, err := c.request(ctx, http.MethodPost, "/accounts/ClientLogin", form)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("greader: logging in to %s: %s", c.config.URL, resp.Status)
+	}
+	for _, line := range strings.Split(string(body), "\n") {
+		if auth := strings.TrimPrefix(strings.TrimSpace(line), "Auth="); auth != strings.TrimSpace(line) {
+			c.mu.Lock()
+			c.auth = auth
+			c.mu.Unlock()
+			return auth, nil
+		}
+	}
+	return "", fmt.Errorf("greader: logging in to %s gave no auth token", c.config.URL)
+}
+
+// do makes an authenticated request, logging in first if need be, and again
+// if the server has forgotten the login.
+func (c *Client) do(ctx context.Context, method string, path string, form url.Values) ([]byte, error) {
+	c.mu.Lock()
+	auth := c.auth
+	c.mu.Unlock()
+	for attempt := 0; ; attempt++ {
+		if auth == "" {
+			var err error
+			if auth, err = c.login(ctx); err != nil {
+				return nil, err
+			}
+		}
+		req, err := c.request(ctx, method, path, form)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "GoogleLogin auth="+auth)
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		badToken := resp.Header.Get("X-Reader-Google-Bad-Token") == "true"
+		if resp.StatusCode == http.StatusUnauthorized && !badToken && attempt == 0 {
+			auth = ""
+			continue
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return nil, statusError{path: path, status: resp.Status, badToken: badToken}
+		}
+		return body, nil
+	}
+}
+
+func (c *Client) request(ctx context.Context, method string, path string, form url.Values) (*http.Request, error) {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.config.URL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "golang-rss-client")
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if c.config.AppID != "" {
+		req.Header.Set("AppId", c.config.AppID)
+		req.Header.Set("AppKey", c.config.AppKey)
+	}
+	return req, nil
+}
+
+// statusError is a request the server turned down.
+type statusError struct {
+	path   string
+	status string
+	// badToken is set when it was the edit token it didn't take
+	badToken bool
+}
+
+func (e statusError) Error() string {
+	return fmt.Sprintf("greader: %s: %s", e.path, e.status)
+}
+
+func asStatus(err error, target *statusError) bool {
+	status, ok := err.(statusError)
+	if ok {
+		*target = status
+	}
+	return ok
+}
+
+// longID is an item ID in its long form, which stream contents use, from
+// either form.
+func longID(id string) string {
+	if strings.HasPrefix(id, itemIDPrefix) {
+		return id
+	}
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return id
+	}
+	return fmt.Sprintf("%s%016x", itemIDPrefix, uint64(n))
+}
+
+// labelName is the name of a user/-/label/... folder, or empty for any
+// other stream.
+func labelName(id string) string {
+	if i := strings.Index(id, "/label/"); i >= 0 && strings.HasPrefix(id, "user/") {
+		return id[i+len("/label/"):]
+	}
+	return ""
+}
@@ -0,0 +1,24 @@
+package greader
+
+import "testing"
+
+func TestLongID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want string
+	}{
+		{"short", "1", "tag:google.com,2005:reader/item/0000000000000001"},
+		{"short hex digits", "1234567890", "tag:google.com,2005:reader/item/00000000499602d2"},
+		{"negative short", "-1", "tag:google.com,2005:reader/item/ffffffffffffffff"},
+		{"long already", "tag:google.com,2005:reader/item/00000000499602d2", "tag:google.com,2005:reader/item/00000000499602d2"},
+		{"neither", "abc", "abc"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := longID(test.id); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package greader
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// syncState is what was last agreed with the server, kept between runs: the
+// read state of each item as of the last sync, and the subscriptions, for
+// starting up without the server.
+type syncState struct {
+	Read          map[string]bool `json:"read"`
+	Subscriptions []Subscription  `json:"subscriptions"`
+}
+
+// Syncer keeps the read state here and on the server in step, both ways. An
+// item's state is compared with what it was at the last sync to tell which
+// side changed it; when both did, the change made here wins. A nil *Syncer
+// syncs nothing.
+type Syncer struct {
+	client *Client
+	path   string
+	every  time.Duration
+
+	mu     sync.Mutex
+	state  syncState
+	synced time.Time
+}
+
+// NewSyncer syncs through client, keeping what it agreed with the server at
+// path. A missing file just means it hasn't synced yet.
+func NewSyncer(client *Client, path string, every time.Duration) (*Syncer, error) {
+	s := &Syncer{client: client, path: path, every: every, state: syncState{Read: make(map[string]bool)}}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &s.state); err != nil {
+		return nil, err
+	}
+	if s.state.Read == nil {
+		s.state.Read = make(map[string]bool)
+	}
+	return s, nil
+}
+
+// Subscriptions lists the feeds subscribed to on the server. When the server
+// can't be reached, the ones it listed last time come back with the error.
+func (s *Syncer) Subscriptions(ctx context.Context) ([]Subscription, error) {
+	subscriptions, err := s.client.Subscriptions(ctx)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		return s.state.Subscriptions, err
+	}
+	s.state.Subscriptions = subscriptions
+	return subscriptions, s.save()
+}
+
+// Due reports whether it's time to sync again: it hasn't this run, or not for
+// a while.
+func (s *Syncer) Due(now time.Time) bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return now.Sub(s.synced) >= s.every
+}
+
+// Sync sends the server what's been read or unread here since the last sync,
+// given the read state of every item of its feeds here by ID, and returns
+// what's been read or unread there: the items to mark read (true) or unread
+// (false) here.
+func (s *Syncer) Sync(ctx context.Context, local map[string]bool) (map[string]bool, error) {
+	if s == nil {
+		return nil, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.synced = time.Now()
+
+	unread, err := s.client.UnreadIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var toRead, toUnread []string
+	changes := make(map[string]bool)
+	agreed := make(map[string]bool, len(local))
+	for id, readHere := range local {
+		readThere := !unread[id]
+		last, known := s.state.Read[id]
+		switch {
+		case readHere == readThere:
+		case known && readHere == last:
+			// changed there
+			changes[id] = readThere
+		case readHere:
+			// changed here, or read here before it was ever synced
+			toRead = append(toRead, id)
+		default:
+			if known {
+				toUnread = append(toUnread, id)
+			} else {
+				// new here and read there
+				changes[id] = readThere
+			}
+		}
+		agreed[id] = readHere
+		if change, ok := changes[id]; ok {
+			agreed[id] = change
+		}
+	}
+	sort.Strings(toRead)
+	sort.Strings(toUnread)
+	if err := s.client.SetRead(ctx, toRead, true); err != nil {
+		return nil, err
+	}
+	if err := s.client.SetRead(ctx, toUnread, false); err != nil {
+		return nil, err
+	}
+	// items no longer in any feed are forgotten
+	s.state.Read = agreed
+	return changes, s.save()
+}
+
+// Flush sends the server what's been read or unread here since the last
+// sync, without asking what's changed there, for when the reader's closing.
+// isRead tells the read state here of an item by ID.
+func (s *Syncer) Flush(ctx context.Context, isRead func(id string) bool) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var toRead, toUnread []string
+	for id, last := range s.state.Read {
+		switch readHere := isRead(id); {
+		case readHere == last:
+		case readHere:
+			toRead = append(toRead, id)
+		default:
+			toUnread = append(toUnread, id)
+		}
+	}
+	if len(toRead) == 0 && len(toUnread) == 0 {
+		return nil
+	}
+	sort.Strings(toRead)
+	sort.Strings(toUnread)
+	if err := s.client.SetRead(ctx, toRead, true); err != nil {
+		return err
+	}
+	if err := s.client.SetRead(ctx, toUnread, false); err != nil {
+		return err
+	}
+	for _, id := range toRead {
+		s.state.Read[id] = true
+	}
+	for _, id := range toUnread {
+		s.state.Read[id] = false
+	}
+	return s.save()
+}
+
+func (s *Syncer) save() error {
+	data, err := json.Marshal(s.state)
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
@@ -0,0 +1,224 @@
+package greader
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeServer is just enough of the Google Reader API for syncing: logging
+// in, the unread items' IDs (in their short form, as servers give them) and
+// marking items read or unread.
+type fakeServer struct {
+	mu     sync.Mutex
+	unread map[string]bool
+	// edits are the edit-tag requests made, as "a:<ids>" or "r:<ids>"
+	edits    []string
+	requests int
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests++
+	switch r.URL.Path {
+	case "/accounts/ClientLogin":
+		io.WriteString(w, "SID=x\nAuth=secret\n")
+		return
+	}
+	if r.Header.Get("Authorization") != "GoogleLogin auth=secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	switch r.URL.Path {
+	case "/reader/api/0/token":
+		io.WriteString(w, "token\n")
+	case "/reader/api/0/stream/items/ids":
+		var refs []string
+		for id := range f.unread {
+			refs = append(refs, `{"id":"`+id+`"}`)
+		}
+		io.WriteString(w, `{"itemRefs":[`+strings.Join(refs, ",")+`]}`)
+	case "/reader/api/0/edit-tag":
+		r.ParseForm()
+		if r.PostForm.Get("T") != "token" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		action := "a"
+		if r.PostForm.Get("r") == readTag {
+			action = "r"
+		}
+		f.edits = append(f.edits, action+":"+strings.Join(r.PostForm["i"], ","))
+		io.WriteString(w, "OK")
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestSyncer(t *testing.T, server *fakeServer, agreed map[string]bool) *Syncer {
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+	client := NewClient(Config{URL: ts.URL, Username: "me", Password: "pw", SyncEvery: 1}, nil)
+	s, err := NewSyncer(client, filepath.Join(t.TempDir(), "greader.json"), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.state.Read = agreed
+	return s
+}
+
+func TestSync(t *testing.T) {
+	one, two := longID("1"), longID("2")
+	tests := []struct {
+		name string
+		// agreed is the read state as of the last sync, local the one here
+		// and unread the items unread on the server, by short ID
+		agreed  map[string]bool
+		local   map[string]bool
+		unread  []string
+		changes map[string]bool
+		edits   []string
+		state   map[string]bool
+	}{
+		{
+			"changed here",
+			map[string]bool{one: false, two: true},
+			map[string]bool{one: true, two: false},
+			[]string{"1"},
+			map[string]bool{},
+			[]string{"a:" + one, "r:" + two},
+			map[string]bool{one: true, two: false},
+		},
+		{
+			"changed there",
+			map[string]bool{one: false, two: true},
+			map[string]bool{one: false, two: true},
+			[]string{"2"},
+			map[string]bool{one: true, two: false},
+			nil,
+			map[string]bool{one: true, two: false},
+		},
+		{
+			// a read state only has two values, so both sides changing it
+			// leaves them agreeing
+			"both changed",
+			map[string]bool{one: false},
+			map[string]bool{one: true},
+			nil,
+			map[string]bool{},
+			nil,
+			map[string]bool{one: true},
+		},
+		{
+			"never synced, read here and unread there: here wins",
+			map[string]bool{},
+			map[string]bool{one: true},
+			[]string{"1"},
+			map[string]bool{},
+			[]string{"a:" + one},
+			map[string]bool{one: true},
+		},
+		{
+			"new here, read there",
+			map[string]bool{},
+			map[string]bool{one: false},
+			nil,
+			map[string]bool{one: true},
+			nil,
+			map[string]bool{one: true},
+		},
+		{
+			"items gone from the feeds are forgotten",
+			map[string]bool{one: false, two: false},
+			map[string]bool{one: false},
+			[]string{"1", "2"},
+			map[string]bool{},
+			nil,
+			map[string]bool{one: false},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &fakeServer{unread: make(map[string]bool)}
+			for _, id := range test.unread {
+				server.unread[id] = true
+			}
+			s := newTestSyncer(t, server, test.agreed)
+			changes, err := s.Sync(context.Background(), test.local)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(changes, test.changes) {
+				t.Errorf("changes %v, want %v", changes, test.changes)
+			}
+			if !reflect.DeepEqual(server.edits, test.edits) {
+				t.Errorf("edits %q, want %q", server.edits, test.edits)
+			}
+			if !reflect.DeepEqual(s.state.Read, test.state) {
+				t.Errorf("state %v, want %v", s.state.Read, test.state)
+			}
+
+			// what was agreed is what the next run starts from
+			saved, err := NewSyncer(s.client, s.path, time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(saved.state.Read, test.state) {
+				t.Errorf("saved state %v, want %v", saved.state.Read, test.state)
+			}
+		})
+	}
+}
+
+func TestFlush(t *testing.T) {
+	one, two, three := longID("1"), longID("2"), longID("3")
+	tests := []struct {
+		name   string
+		agreed map[string]bool
+		read   map[string]bool
+		edits  []string
+		state  map[string]bool
+	}{
+		{
+			"changed here",
+			map[string]bool{one: false, two: true, three: false},
+			map[string]bool{one: true},
+			[]string{"a:" + one, "r:" + two},
+			map[string]bool{one: true, two: false, three: false},
+		},
+		{
+			"nothing changed",
+			map[string]bool{one: false, two: true},
+			map[string]bool{two: true},
+			nil,
+			map[string]bool{one: false, two: true},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &fakeServer{}
+			s := newTestSyncer(t, server, test.agreed)
+			err := s.Flush(context.Background(), func(id string) bool { return test.read[id] })
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(server.edits, test.edits) {
+				t.Errorf("edits %q, want %q", server.edits, test.edits)
+			}
+			if test.edits == nil && server.requests > 0 {
+				t.Errorf("made %d requests with nothing to send", server.requests)
+			}
+			if !reflect.DeepEqual(s.state.Read, test.state) {
+				t.Errorf("state %v, want %v", s.state.Read, test.state)
+			}
+		})
+	}
+}
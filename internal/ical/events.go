@@ -0,0 +1,175 @@
+package ical
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/homielabs/golang-rss-client/internal/store"
+	"github.com/mmcdole/gofeed"
+	ext "github.com/mmcdole/gofeed/extensions"
+)
+
+// The prefixes feeds declare the event namespaces under. Extensions go by
+// the document's own prefix, so the usual spellings of each are tried.
+var (
+	// the RSS 1.0 event module: ev:startdate, ev:enddate, ev:location and
+	// ev:organizer
+	eventPrefixes = []string{"ev", "event"}
+	// xCal, iCalendar in XML: xCal:dtstart, xCal:dtend and xCal:location
+	xcalPrefixes = []string{"xCal", "xcal"}
+	// Google's data API: gd:when's startTime and endTime, gd:where's
+	// valueString
+	gdataPrefixes = []string{"gd"}
+)
+
+// dateLayouts are the forms event dates come in, ISO 8601 or iCalendar's,
+// with or without a time of day and a zone.
+var dateLayouts = []struct {
+	layout string
+	allDay bool
+}{
+	{time.RFC3339, false},
+	{"2006-01-02T15:04Z07:00", false},
+	{"2006-01-02T15:04:05Z0700", false},
+	{"2006-01-02T15:04:05", false},
+	{"2006-01-02T15:04", false},
+	{"2006-01-02 15:04:05", false},
+	{"2006-01-02 15:04", false},
+	{"20060102T150405Z0700", false},
+	{"20060102T150405", false},
+	{"2006-01-02", true},
+	{"20060102", true},
+}
+
+// Events are the feed's items that are events, the ones saying when they
+// start. Dates without a zone are read in loc.
+func Events(feedUrl string, feed *gofeed.Feed, loc *time.Location) []Event {
+	var events []Event
+	for _, item := range feed.Items {
+		if event, ok := itemEvent(feedUrl, item, loc); ok {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+func itemEvent(feedUrl string, item *gofeed.Item, loc *time.Location) (Event, bool) {
+	start := extensionValue(item, eventPrefixes, "startdate")
+	end := extensionValue(item, eventPrefixes, "enddate")
+	location := extensionValue(item, eventPrefixes, "location")
+	organizer := extensionValue(item, eventPrefixes, "organizer")
+	// xCal's all-day events end the day after their last, as iCalendar's do
+	exclusiveEnd := false
+	if start == "" {
+		start = extensionValue(item, xcalPrefixes, "dtstart")
+		end = extensionValue(item, xcalPrefixes, "dtend")
+		location = extensionValue(item, xcalPrefixes, "location")
+		exclusiveEnd = true
+	}
+	if start == "" {
+		start = extensionAttr(item, gdataPrefixes, "when", "startTime")
+		end = extensionAttr(item, gdataPrefixes, "when", "endTime")
+		location = extensionAttr(item, gdataPrefixes, "where", "valueString")
+	}
+	startTime, allDay, ok := parseDate(start, loc)
+	if !ok {
+		return Event{}, false
+	}
+
+	event := Event{
+		UID:      fmt.Sprintf("%x@golang-rss-client", sha1.Sum([]byte(feedUrl+"\x00"+store.ItemKey(item)))),
+		Summary:  strings.Join(strings.Fields(item.Title), " "),
+		Location: strings.Join(strings.Fields(location), " "),
+		URL:      item.Link,
+		Start:    startTime,
+		AllDay:   allDay,
+	}
+	if event.Summary == "" {
+		event.Summary = "Event"
+	}
+	// an end that doesn't go with the start is left off rather than
+	// guessed at
+	if endTime, endAllDay, ok := parseDate(end, loc); ok && endAllDay == allDay {
+		if allDay && exclusiveEnd {
+			endTime = endTime.AddDate(0, 0, -1)
+		}
+		if !endTime.Before(startTime) {
+			event.End = endTime
+		}
+	}
+
+	content := item.Description
+	if content == "" {
+		content = item.Content
+	}
+	description := plainText(content)
+	if organizer = strings.Join(strings.Fields(organizer), " "); organizer != "" {
+		description = strings.TrimSpace("Organized by " + organizer + ".\n\n" + description)
+	}
+	event.Description = description
+	return event, true
+}
+
+// parseDate reads an event date in any of dateLayouts, reporting whether it
+// was only a date.
+func parseDate(value string, loc *time.Location) (time.Time, bool, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false, false
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout.layout, value, loc); err == nil {
+			return t, layout.allDay, true
+		}
+	}
+	return time.Time{}, false, false
+}
+
+// extensionValue is the text of the first of an item's extension elements
+// called name, under any of the prefixes.
+func extensionValue(item *gofeed.Item, prefixes []string, name string) string {
+	if found := extension(item, prefixes, name); found != nil {
+		return found.Value
+	}
+	return ""
+}
+
+// extensionAttr is an attribute of the first of an item's extension elements
+// called name, under any of the prefixes.
+func extensionAttr(item *gofeed.Item, prefixes []string, name string, attr string) string {
+	if found := extension(item, prefixes, name); found != nil {
+		return found.Attrs[attr]
+	}
+	return ""
+}
+
+func extension(item *gofeed.Item, prefixes []string, name string) *ext.Extension {
+	for _, prefix := range prefixes {
+		if elements := item.Extensions[prefix][name]; len(elements) > 0 {
+			return &elements[0]
+		}
+	}
+	return nil
+}
+
+// plainText is an item's HTML as text, a paragraph to a line.
+func plainText(content string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	if err != nil {
+		return ""
+	}
+	doc.Find("br").ReplaceWithHtml("\n")
+	doc.Find("p, div, li, h1, h2, h3, h4, h5, h6, blockquote, pre").Each(func(_ int, s *goquery.Selection) {
+		s.AppendHtml("\n")
+	})
+	var lines []string
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,131 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestEvents(t *testing.T) {
+	const rss = `<?xml version="1.0"?>
+<rss version="2.0"
+	xmlns:ev="http://purl.org/rss/1.0/modules/event/"
+	xmlns:xCal="urn:ietf:params:xml:ns:xcal"
+	xmlns:gd="http://schemas.google.com/g/2005">
+<channel><title>Events</title>
+<item>
+	<title>Go   meetup</title><link>https://x/meetup</link><guid>meetup</guid>
+	<description>&lt;p&gt;Talks&lt;/p&gt;&lt;p&gt;and &lt;b&gt;beer&lt;/b&gt;&lt;/p&gt;</description>
+	<ev:startdate>2026-03-05T19:00:00+01:00</ev:startdate>
+	<ev:enddate>2026-03-05T21:00:00+01:00</ev:enddate>
+	<ev:location> Room
+		1 </ev:location>
+	<ev:organizer>Gophers</ev:organizer>
+</item>
+<item>
+	<title>Conference</title><guid>conf</guid>
+	<xCal:dtstart>20260401</xCal:dtstart>
+	<xCal:dtend>20260404</xCal:dtend>
+</item>
+<item>
+	<title></title><guid>gdata</guid>
+	<gd:when startTime="2026-05-01 10:00" endTime="2026-05-01"/>
+	<gd:where valueString="Online"/>
+</item>
+<item>
+	<title>Backwards</title><guid>backwards</guid>
+	<ev:startdate>2026-06-02</ev:startdate>
+	<ev:enddate>2026-06-01</ev:enddate>
+</item>
+<item><title>Not an event</title><guid>post</guid></item>
+<item>
+	<title>Bad date</title><guid>bad</guid>
+	<ev:startdate>next Tuesday</ev:startdate>
+</item>
+</channel></rss>`
+	feed, err := gofeed.NewParser().ParseString(rss)
+	if err != nil {
+		t.Fatal(err)
+	}
+	berlin := time.FixedZone("CET", 60*60)
+	events := Events("http://x/feed", feed, berlin)
+
+	want := []Event{
+		{
+			Summary:     "Go meetup",
+			Description: "Organized by Gophers.\n\nTalks\nand beer",
+			Location:    "Room 1",
+			URL:         "https://x/meetup",
+			Start:       time.Date(2026, 3, 5, 19, 0, 0, 0, berlin),
+			End:         time.Date(2026, 3, 5, 21, 0, 0, 0, berlin),
+		},
+		{
+			Summary: "Conference",
+			Start:   time.Date(2026, 4, 1, 0, 0, 0, 0, berlin),
+			End:     time.Date(2026, 4, 3, 0, 0, 0, 0, berlin),
+			AllDay:  true,
+		},
+		{
+			Summary:  "Event",
+			Location: "Online",
+			Start:    time.Date(2026, 5, 1, 10, 0, 0, 0, berlin),
+		},
+		{
+			Summary: "Backwards",
+			Start:   time.Date(2026, 6, 2, 0, 0, 0, 0, berlin),
+			AllDay:  true,
+		},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	uids := make(map[string]bool)
+	for i, event := range events {
+		if !strings.HasSuffix(event.UID, "@golang-rss-client") || uids[event.UID] {
+			t.Errorf("event %d has UID %q", i, event.UID)
+		}
+		uids[event.UID] = true
+		if event.Summary != want[i].Summary || event.Description != want[i].Description ||
+			event.Location != want[i].Location || event.URL != want[i].URL || event.AllDay != want[i].AllDay ||
+			!event.Start.Equal(want[i].Start) || !event.End.Equal(want[i].End) {
+			t.Errorf("event %d is %+v, want %+v", i, event, want[i])
+		}
+	}
+
+	again := Events("http://x/feed", feed, berlin)
+	if again[0].UID != events[0].UID {
+		t.Error("the same item got a different UID")
+	}
+	if other := Events("http://y/feed", feed, berlin); other[0].UID == events[0].UID {
+		t.Error("the same item in another feed got the same UID")
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   time.Time
+		allDay bool
+		ok     bool
+	}{
+		{"2026-03-05T19:00:00Z", time.Date(2026, 3, 5, 19, 0, 0, 0, time.UTC), false, true},
+		{"2026-03-05T19:00+02:00", time.Date(2026, 3, 5, 17, 0, 0, 0, time.UTC), false, true},
+		{"2026-03-05T19:00:00", time.Date(2026, 3, 5, 18, 0, 0, 0, time.UTC), false, true},
+		{"20260305T190000Z", time.Date(2026, 3, 5, 19, 0, 0, 0, time.UTC), false, true},
+		{" 2026-03-05 ", time.Date(2026, 3, 4, 23, 0, 0, 0, time.UTC), true, true},
+		{"20260305", time.Date(2026, 3, 4, 23, 0, 0, 0, time.UTC), true, true},
+		{"", time.Time{}, false, false},
+		{"March 5th", time.Time{}, false, false},
+	}
+	berlin := time.FixedZone("CET", 60*60)
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			got, allDay, ok := parseDate(test.value, berlin)
+			if ok != test.ok || allDay != test.allDay || !got.Equal(test.want) {
+				t.Errorf("parseDate(%q) = %v, %v, %v, want %v, %v, %v", test.value, got, allDay, ok, test.want, test.allDay, test.ok)
+			}
+		})
+	}
+}
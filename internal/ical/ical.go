@@ -0,0 +1,116 @@
+// Package ical writes events out as an iCalendar (.ics) file, as calendars
+// import them, and finds the events in feeds that carry them.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLineOctets is how long a content line may be before it has to be
+// folded onto the next.
+const maxLineOctets = 75
+
+// Event is an event in a calendar.
+type Event struct {
+	// UID tells the event apart from every other, so importing the same
+	// event twice updates it instead of adding a copy.
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Start       time.Time
+	// End is the zero time if the feed didn't give one.
+	End time.Time
+	// AllDay events are on Start's date (to End's, inclusive), without a
+	// time of day.
+	AllDay bool
+}
+
+// Write writes a calendar of the events, named name, stamped as of now.
+func Write(w io.Writer, name string, events []Event, now time.Time) error {
+	out := bufio.NewWriter(w)
+	line := func(name string, value string) {
+		writeLine(out, name+":"+value)
+	}
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//golang-rss-client//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if name != "" {
+		line("X-WR-CALNAME", escape(name))
+	}
+	stamp := formatTime(now)
+	for _, event := range events {
+		line("BEGIN", "VEVENT")
+		line("UID", escape(event.UID))
+		line("DTSTAMP", stamp)
+		if event.AllDay {
+			line("DTSTART;VALUE=DATE", formatDate(event.Start))
+			// an all-day event's end is the day after its last
+			if !event.End.IsZero() {
+				line("DTEND;VALUE=DATE", formatDate(event.End.AddDate(0, 0, 1)))
+			}
+		} else {
+			line("DTSTART", formatTime(event.Start))
+			if !event.End.IsZero() {
+				line("DTEND", formatTime(event.End))
+			}
+		}
+		line("SUMMARY", escape(event.Summary))
+		if event.Description != "" {
+			line("DESCRIPTION", escape(event.Description))
+		}
+		if event.Location != "" {
+			line("LOCATION", escape(event.Location))
+		}
+		if event.URL != "" {
+			line("URL", event.URL)
+		}
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return out.Flush()
+}
+
+// writeLine writes a content line, folded to maxLineOctets without breaking
+// up a character, with the CRLF the format wants.
+func writeLine(out *bufio.Writer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		out.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// the space that continues a line counts towards it
+		limit = maxLineOctets - 1
+	}
+	out.WriteString(line + "\r\n")
+}
+
+// escape escapes text the way TEXT values need.
+func escape(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(text)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+func formatDate(t time.Time) string {
+	return t.Format("20060102")
+}
@@ -0,0 +1,129 @@
+package ical
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestWrite(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	berlin := time.FixedZone("CET", 60*60)
+	events := []Event{
+		{
+			UID:         "1@x",
+			Summary:     "Meetup; talks, beer",
+			Description: "Line one\nLine two",
+			Location:    `Room \ 1`,
+			URL:         "https://x/meetup",
+			Start:       time.Date(2026, 3, 5, 19, 0, 0, 0, berlin),
+			End:         time.Date(2026, 3, 5, 21, 0, 0, 0, berlin),
+		},
+		{
+			UID:     "2@x",
+			Summary: "Conference",
+			Start:   time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+			End:     time.Date(2026, 4, 3, 0, 0, 0, 0, time.UTC),
+			AllDay:  true,
+		},
+	}
+	var out bytes.Buffer
+	if err := Write(&out, "Go, events", events, now); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//golang-rss-client//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		`X-WR-CALNAME:Go\, events`,
+		"BEGIN:VEVENT",
+		"UID:1@x",
+		"DTSTAMP:20260301T120000Z",
+		"DTSTART:20260305T180000Z",
+		"DTEND:20260305T200000Z",
+		`SUMMARY:Meetup\; talks\, beer`,
+		`DESCRIPTION:Line one\nLine two`,
+		`LOCATION:Room \\ 1`,
+		"URL:https://x/meetup",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:2@x",
+		"DTSTAMP:20260301T120000Z",
+		"DTSTART;VALUE=DATE:20260401",
+		"DTEND;VALUE=DATE:20260404",
+		"SUMMARY:Conference",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+	if got := out.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriteLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{"short", "SUMMARY:short"},
+		{"exactly the limit", "SUMMARY:" + strings.Repeat("a", maxLineOctets-len("SUMMARY:"))},
+		{"folded", "DESCRIPTION:" + strings.Repeat("a", 200)},
+		{"folded between characters", "DESCRIPTION:" + strings.Repeat("é", 100)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var out bytes.Buffer
+			w := bufio.NewWriter(&out)
+			writeLine(w, test.line)
+			w.Flush()
+
+			written := out.String()
+			if !strings.HasSuffix(written, "\r\n") {
+				t.Fatalf("%q doesn't end in CRLF", written)
+			}
+			lines := strings.Split(strings.TrimSuffix(written, "\r\n"), "\r\n")
+			var unfolded strings.Builder
+			for i, line := range lines {
+				if len(line) > maxLineOctets {
+					t.Errorf("line %d is %d octets", i, len(line))
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line %d splits a character: %q", i, line)
+				}
+				if i > 0 {
+					if !strings.HasPrefix(line, " ") {
+						t.Errorf("continuation %q doesn't start with a space", line)
+					}
+					line = line[1:]
+				}
+				unfolded.WriteString(line)
+			}
+			if unfolded.String() != test.line {
+				t.Errorf("unfolds to %q, want %q", unfolded.String(), test.line)
+			}
+		})
+	}
+}
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"plain", "plain"},
+		{`a\b`, `a\\b`},
+		{"a;b,c", `a\;b\,c`},
+		{"a\r\nb\nc\rd", `a\nb\nc\nd`},
+	}
+	for _, test := range tests {
+		if got := escape(test.in); got != test.want {
+			t.Errorf("escape(%q) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package miniflux
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/homielabs/golang-rss-client/internal/backend"
+	"github.com/mmcdole/gofeed"
+)
+
+// Name is "miniflux".
+func (c *Client) Name() string {
+	return Scheme
+}
+
+// Owns reports whether a feed URL is one of the server's.
+func (c *Client) Owns(feedUrl string) bool {
+	return IsFeedURL(feedUrl)
+}
+
+// StateKeys are ReadKey and StarredKey.
+func (c *Client) StateKeys() (string, string) {
+	return ReadKey, StarredKey
+}
+
+// List is the feeds subscribed to on the server, in their categories. When
+// the server can't be reached, the ones it listed last time come back with
+// the error.
+func (c *Client) List(ctx context.Context) ([]backend.Subscription, error) {
+	feeds, err := c.SavedFeeds(ctx, c.saved)
+	var subscriptions []backend.Subscription
+	for _, feed := range feeds {
+		subscription := backend.Subscription{URL: FeedURL(feed.ID)}
+		if feed.Category.Title != "" {
+			subscription.Groups = []string{feed.Category.Title}
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, err
+}
+
+// entryIDs are the IDs of the entries items were read from.
+func entryIDs(items []*gofeed.Item) []int64 {
+	var ids []int64
+	for _, item := range items {
+		if id, ok := EntryID(item); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// entryStatus is a change of entries' read state.
+type entryStatus struct {
+	EntryIDs []int64 `json:"entry_ids"`
+	Status   string  `json:"status"`
+}
+
+// MarkRead marks entries read, or unread, on the server.
+func (c *Client) MarkRead(ctx context.Context, items []*gofeed.Item, read bool) error {
+	ids := entryIDs(items)
+	if len(ids) == 0 {
+		return nil
+	}
+	status := entryStatus{EntryIDs: ids, Status: "read"}
+	if !read {
+		status.Status = "unread"
+	}
+	return c.do(ctx, http.MethodPut, "/v1/entries", status, nil)
+}
+
+// Star stars or unstars entries on the server. The API only toggles them, so
+// it's only to be asked to for entries that are the other way there.
+func (c *Client) Star(ctx context.Context, items []*gofeed.Item, _ bool) error {
+	for _, id := range entryIDs(items) {
+		if err := c.do(ctx, http.MethodPut, fmt.Sprintf("/v1/entries/%d/bookmark", id), nil, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Subscribe subscribes to a feed on the server, in its first category.
+func (c *Client) Subscribe(ctx context.Context, feedUrl string) (string, error) {
+	var categories []struct {
+		ID int64 `json:"id"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/v1/categories", nil, &categories); err != nil {
+		return "", err
+	}
+	if len(categories) == 0 {
+		return "", fmt.Errorf("miniflux: there's no category to file %s under", feedUrl)
+	}
+	in := struct {
+		FeedURL    string `json:"feed_url"`
+		CategoryID int64  `json:"category_id"`
+	}{feedUrl, categories[0].ID}
+	var out struct {
+		FeedID int64 `json:"feed_id"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/v1/feeds", in, &out); err != nil {
+		return "", err
+	}
+	return FeedURL(out.FeedID), nil
+}
@@ -0,0 +1,134 @@
+package miniflux
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// Scheme is the scheme of the feed URLs that stand for the server's feeds,
+// like miniflux:42: the feed's ID follows it.
+const Scheme = "miniflux"
+
+// entryPrefix starts the GUIDs of entries read from the server, before the
+// entry's ID.
+const entryPrefix = "miniflux:entry/"
+
+// ReadKey and StarredKey are set in the Custom map of an entry's item to
+// "true" or "false": whether it's read, and starred, on the server as far as
+// the reader last knew.
+const (
+	ReadKey    = "miniflux:read"
+	StarredKey = "miniflux:starred"
+)
+
+// FeedURL is the URL a feed is read from the server by.
+func FeedURL(feedID int64) string {
+	return Scheme + ":" + strconv.FormatInt(feedID, 10)
+}
+
+// IsFeedURL reports whether a feed URL stands for one of the server's feeds.
+func IsFeedURL(feedUrl string) bool {
+	return strings.HasPrefix(feedUrl, Scheme+":")
+}
+
+// EntryID is the ID of the entry an item was read from, if it was read from
+// the server.
+func EntryID(item *gofeed.Item) (int64, bool) {
+	if !strings.HasPrefix(item.GUID, entryPrefix) {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(item.GUID, entryPrefix), 10, 64)
+	return id, err == nil
+}
+
+// entry is one of a feed's entries.
+type entry struct {
+	ID          int64     `json:"id"`
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	Author      string    `json:"author"`
+	Content     string    `json:"content"`
+	Status      string    `json:"status"`
+	Starred     bool      `json:"starred"`
+	PublishedAt time.Time `json:"published_at"`
+	Tags        []string  `json:"tags"`
+	Enclosures  []struct {
+		URL      string `json:"url"`
+		MimeType string `json:"mime_type"`
+		Size     int64  `json:"size"`
+	} `json:"enclosures"`
+}
+
+// Fetch reads a feed's latest entries from the server. It's the
+// fetch.Source for miniflux: URLs.
+func (c *Client) Fetch(ctx context.Context, source *url.URL, _ *gofeed.Feed) (*gofeed.Feed, error) {
+	feedID, err := strconv.ParseInt(source.Opaque, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("miniflux: %s isn't a feed's ID", source.Opaque)
+	}
+	var info Feed
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/v1/feeds/%d", feedID), nil, &info); err != nil {
+		return nil, err
+	}
+	query := url.Values{
+		"order":     {"published_at"},
+		"direction": {"desc"},
+		"limit":     {strconv.Itoa(c.config.Entries)},
+		// leaving out the ones removed on the server
+		"status": {"unread", "read"},
+	}
+	var entries struct {
+		Entries []entry `json:"entries"`
+	}
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/v1/feeds/%d/entries?%s", feedID, query.Encode()), nil, &entries); err != nil {
+		return nil, err
+	}
+	feed := &gofeed.Feed{
+		Title:    info.Title,
+		Link:     info.SiteURL,
+		FeedLink: source.String(),
+	}
+	for _, e := range entries.Entries {
+		feed.Items = append(feed.Items, e.feedItem())
+	}
+	return feed, nil
+}
+
+// feedItem is an entry as feeds' items are, with its state on the server in
+// its custom fields.
+func (e entry) feedItem() *gofeed.Item {
+	item := &gofeed.Item{
+		GUID:       entryPrefix + strconv.FormatInt(e.ID, 10),
+		Title:      e.Title,
+		Link:       e.URL,
+		Content:    e.Content,
+		Categories: e.Tags,
+		Custom: map[string]string{
+			ReadKey:    strconv.FormatBool(e.Status == "read"),
+			StarredKey: strconv.FormatBool(e.Starred),
+		},
+	}
+	if e.Author != "" {
+		item.Authors = []*gofeed.Person{{Name: e.Author}}
+	}
+	if !e.PublishedAt.IsZero() {
+		published := e.PublishedAt
+		item.Published = published.Format(time.RFC3339)
+		item.PublishedParsed = &published
+	}
+	for _, enclosure := range e.Enclosures {
+		item.Enclosures = append(item.Enclosures, &gofeed.Enclosure{
+			URL:    enclosure.URL,
+			Type:   enclosure.MimeType,
+			Length: strconv.FormatInt(enclosure.Size, 10),
+		})
+	}
+	return item
+}
@@ -0,0 +1,104 @@
+package miniflux
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		ok     bool
+	}{
+		{"not configured", Config{}, true},
+		{"token", Config{URL: "https://reader.example.net", Token: "abc", Entries: 1}, true},
+		{"not http", Config{URL: "reader.example.net", Token: "abc", Entries: 1}, false},
+		{"no host", Config{URL: "https://", Token: "abc", Entries: 1}, false},
+		{"no token", Config{URL: "https://reader.example.net", Entries: 1}, false},
+		{"no entries", Config{URL: "https://reader.example.net", Token: "abc"}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.config.Validate(); (err == nil) != test.ok {
+				t.Errorf("got %v, want ok %v", err, test.ok)
+			}
+		})
+	}
+}
+
+func TestEntryID(t *testing.T) {
+	tests := []struct {
+		guid string
+		id   int64
+		ok   bool
+	}{
+		{"miniflux:entry/42", 42, true},
+		{"miniflux:entry/", 0, false},
+		{"miniflux:entry/x", 0, false},
+		{"nextcloud:item/42", 0, false},
+		{"https://example.com/42", 0, false},
+	}
+	for _, test := range tests {
+		t.Run(test.guid, func(t *testing.T) {
+			id, ok := EntryID(&gofeed.Item{GUID: test.guid})
+			if id != test.id || ok != test.ok {
+				t.Errorf("got %d, %v, want %d, %v", id, ok, test.id, test.ok)
+			}
+		})
+	}
+}
+
+func TestSavedFeeds(t *testing.T) {
+	up := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !up {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Path != "/v1/feeds" || r.Header.Get("X-Auth-Token") != "abc" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		io.WriteString(w, `[{"id":7,"title":"Example","feed_url":"https://example.com/feed.xml","category":{"id":1,"title":"news"}}]`)
+	}))
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "miniflux.json")
+	c := NewClient(Config{URL: server.URL, Token: "abc", Entries: 1}, path, nil)
+
+	// nothing's been saved yet
+	up = false
+	if feeds, err := c.SavedFeeds(context.Background(), path); err == nil || len(feeds) > 0 {
+		t.Errorf("got %v, %v with the server down and nothing saved, want an error", feeds, err)
+	}
+
+	up = true
+	feeds, err := c.SavedFeeds(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(feeds) != 1 || feeds[0].ID != 7 || feeds[0].Category.Title != "news" {
+		t.Errorf("got %v, want feed 7 in news", feeds)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("the list wasn't saved: %s", err)
+	}
+
+	// starting without the server, the saved list comes back with the error
+	up = false
+	saved, err := c.SavedFeeds(context.Background(), path)
+	if err == nil {
+		t.Error("got no error with the server down")
+	}
+	if !reflect.DeepEqual(saved, feeds) {
+		t.Errorf("got %v, want the saved %v", saved, feeds)
+	}
+}
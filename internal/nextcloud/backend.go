@@ -0,0 +1,96 @@
+package nextcloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/homielabs/golang-rss-client/internal/backend"
+	"github.com/mmcdole/gofeed"
+)
+
+// Name is "nextcloud".
+func (c *Client) Name() string {
+	return Scheme
+}
+
+// Owns reports whether a feed URL is one of the server's.
+func (c *Client) Owns(feedUrl string) bool {
+	return IsFeedURL(feedUrl)
+}
+
+// StateKeys are ReadKey and StarredKey.
+func (c *Client) StateKeys() (string, string) {
+	return ReadKey, StarredKey
+}
+
+// List is the feeds subscribed to on the server, in their folders. When the
+// server can't be reached, the ones it listed last time come back with the
+// error.
+func (c *Client) List(ctx context.Context) ([]backend.Subscription, error) {
+	listed, err := c.SavedSubscriptions(ctx, c.saved)
+	var subscriptions []backend.Subscription
+	for _, feed := range listed.Feeds {
+		subscription := backend.Subscription{URL: FeedURL(feed.ID)}
+		if folder := listed.Folder(feed); folder != "" {
+			subscription.Groups = []string{folder}
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, err
+}
+
+// itemIDs are the items a change is made to.
+type itemIDs struct {
+	ItemIDs []int64 `json:"itemIds"`
+}
+
+// change makes a change to items on the server, through one of the
+// /items/.../multiple endpoints.
+func (c *Client) change(ctx context.Context, change string, items []*gofeed.Item) error {
+	var ids itemIDs
+	for _, item := range items {
+		if id, ok := ItemID(item); ok {
+			ids.ItemIDs = append(ids.ItemIDs, id)
+		}
+	}
+	if len(ids.ItemIDs) == 0 {
+		return nil
+	}
+	return c.do(ctx, http.MethodPost, "/items/"+change+"/multiple", ids, nil)
+}
+
+// MarkRead marks items read, or unread, on the server.
+func (c *Client) MarkRead(ctx context.Context, items []*gofeed.Item, read bool) error {
+	if read {
+		return c.change(ctx, "read", items)
+	}
+	return c.change(ctx, "unread", items)
+}
+
+// Star stars or unstars items on the server.
+func (c *Client) Star(ctx context.Context, items []*gofeed.Item, starred bool) error {
+	if starred {
+		return c.change(ctx, "star", items)
+	}
+	return c.change(ctx, "unstar", items)
+}
+
+// Subscribe subscribes to a feed on the server, outside any folder.
+func (c *Client) Subscribe(ctx context.Context, feedUrl string) (string, error) {
+	in := struct {
+		URL      string `json:"url"`
+		FolderID int64  `json:"folderId"`
+	}{URL: feedUrl}
+	var out struct {
+		Feeds []Feed `json:"feeds"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/feeds", in, &out); err != nil {
+		return "", err
+	}
+	if len(out.Feeds) == 0 {
+		return "", fmt.Errorf("nextcloud: subscribing to %s gave no feed", feedUrl)
+	}
+	c.keepFeeds(out.Feeds)
+	return FeedURL(out.Feeds[0].ID), nil
+}
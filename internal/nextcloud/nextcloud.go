@@ -0,0 +1,226 @@
+// Package nextcloud talks to a Nextcloud server's News app through its API
+// (v1.3): it lists the folders and feeds there, reads their items, and marks
+// items read or starred on the server.
+package nextcloud
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// apiPath is where the API is, under the server's URL.
+const apiPath = "/index.php/apps/news/api/v1-3"
+
+// Config is the `nextcloud` config section.
+type Config struct {
+	// URL is the server's, like https://cloud.example.net. Empty leaves
+	// Nextcloud News out.
+	URL string `mapstructure:"url"`
+	// Username and Password log in; an app password, made under Settings >
+	// Security, is better than the account's own.
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	// PasswordCommand, if set, prints the password instead, like
+	// "pass show nextcloud", so it needn't be kept in the config.
+	PasswordCommand string `mapstructure:"passwordCommand"`
+	// Items is how many of each feed's latest items are read.
+	Items int `mapstructure:"items"`
+}
+
+// Validate checks there's a server, and what to log in to it with.
+func (c Config) Validate() error {
+	if c.URL == "" {
+		return nil
+	}
+	parsed, err := url.Parse(c.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("nextcloud: url %q isn't an http(s) URL", c.URL)
+	}
+	if c.Username == "" || (c.Password == "" && strings.TrimSpace(c.PasswordCommand) == "") {
+		return fmt.Errorf("nextcloud: %s needs a username, and a password or passwordCommand", c.URL)
+	}
+	if c.Items < 1 {
+		return fmt.Errorf("nextcloud: items should be at least 1, not %d", c.Items)
+	}
+	return nil
+}
+
+// ReadPassword runs the password command, if there is one and no password's
+// set, keeping the first line it prints as the password.
+func (c *Config) ReadPassword(ctx context.Context) error {
+	if c.Password != "" || c.PasswordCommand == "" {
+		return nil
+	}
+	fields := strings.Fields(c.PasswordCommand)
+	if len(fields) == 0 {
+		return fmt.Errorf("nextcloud: passwordCommand is blank")
+	}
+	out, err := exec.CommandContext(ctx, fields[0], fields[1:]...).Output()
+	if err != nil {
+		return fmt.Errorf("nextcloud: passwordCommand: %w", err)
+	}
+	password := strings.SplitN(string(out), "\n", 2)[0]
+	if password = strings.TrimSpace(password); password == "" {
+		return fmt.Errorf("nextcloud: passwordCommand printed no password")
+	}
+	c.Password = password
+	return nil
+}
+
+// Client makes requests of the configured server. It's the backend of the
+// server's feeds.
+type Client struct {
+	config Config
+	http   *http.Client
+	// saved is where the list of folders and feeds is kept
+	saved string
+
+	mu sync.Mutex
+	// feeds are the ones last listed, by ID, for their titles and sites
+	feeds map[int64]Feed
+}
+
+// NewClient makes a client for the configured server, whose password has
+// been read, reached over transport (nil for the default) and keeping the
+// list of its folders and feeds at saved.
+func NewClient(config Config, saved string, transport http.RoundTripper) *Client {
+	config.URL = strings.TrimSuffix(config.URL, "/")
+	return &Client{config: config, http: &http.Client{Transport: transport}, saved: saved, feeds: make(map[int64]Feed)}
+}
+
+// Folder is what feeds are filed under on the server.
+type Folder struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// Feed is a feed subscribed to on the server.
+type Feed struct {
+	ID       int64  `json:"id"`
+	Title    string `json:"title"`
+	URL      string `json:"url"`
+	Link     string `json:"link"`
+	FolderID int64  `json:"folderId"`
+}
+
+// Subscriptions are the folders and feeds on the server.
+type Subscriptions struct {
+	Folders []Folder `json:"folders"`
+	Feeds   []Feed   `json:"feeds"`
+}
+
+// Folder is the name of the folder a feed's filed under, if it's in one.
+func (s Subscriptions) Folder(feed Feed) string {
+	for _, folder := range s.Folders {
+		if folder.ID == feed.FolderID {
+			return folder.Name
+		}
+	}
+	return ""
+}
+
+// Subscriptions lists the folders and feeds on the server.
+func (c *Client) Subscriptions(ctx context.Context) (Subscriptions, error) {
+	var subscriptions Subscriptions
+	if err := c.do(ctx, http.MethodGet, "/folders", nil, &subscriptions); err != nil {
+		return subscriptions, err
+	}
+	if err := c.do(ctx, http.MethodGet, "/feeds", nil, &subscriptions); err != nil {
+		return subscriptions, err
+	}
+	c.keepFeeds(subscriptions.Feeds)
+	return subscriptions, nil
+}
+
+// SavedSubscriptions lists the folders and feeds on the server, keeping the
+// list at path. When the server can't be reached, the list kept there comes
+// back with the error, so its feeds can be read from the cache.
+func (c *Client) SavedSubscriptions(ctx context.Context, path string) (Subscriptions, error) {
+	subscriptions, err := c.Subscriptions(ctx)
+	if err != nil {
+		data, readErr := os.ReadFile(path)
+		if readErr != nil {
+			return Subscriptions{}, err
+		}
+		var saved Subscriptions
+		if json.Unmarshal(data, &saved) != nil {
+			return Subscriptions{}, err
+		}
+		c.keepFeeds(saved.Feeds)
+		return saved, err
+	}
+	data, err := json.Marshal(subscriptions)
+	if err != nil {
+		return Subscriptions{}, err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return subscriptions, err
+	}
+	return subscriptions, os.Rename(tmp, path)
+}
+
+// keepFeeds notes the feeds listed, for reading them.
+func (c *Client) keepFeeds(feeds []Feed) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, feed := range feeds {
+		c.feeds[feed.ID] = feed
+	}
+}
+
+// do makes a request, sending in as JSON if it's set and reading the response
+// into out if that is.
+func (c *Client) do(ctx context.Context, method string, path string, in interface{}, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.config.URL+apiPath+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "golang-rss-client")
+	req.SetBasicAuth(c.config.Username, c.config.Password)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("nextcloud: %s: %s: %s", path, resp.Status, apiErr.Message)
+		}
+		return fmt.Errorf("nextcloud: %s: %s", path, resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("nextcloud: %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package nextcloud
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		ok     bool
+	}{
+		{"not configured", Config{}, true},
+		{"password", Config{URL: "https://cloud.example.net", Username: "me", Password: "pw", Items: 1}, true},
+		{"password command", Config{URL: "https://cloud.example.net", Username: "me", PasswordCommand: "pass show nextcloud", Items: 1}, true},
+		{"not http", Config{URL: "ftp://cloud.example.net", Username: "me", Password: "pw", Items: 1}, false},
+		{"no host", Config{URL: "https://", Username: "me", Password: "pw", Items: 1}, false},
+		{"no username", Config{URL: "https://cloud.example.net", Password: "pw", Items: 1}, false},
+		{"no password", Config{URL: "https://cloud.example.net", Username: "me", Items: 1}, false},
+		{"blank password command", Config{URL: "https://cloud.example.net", Username: "me", PasswordCommand: "  \t", Items: 1}, false},
+		{"no items", Config{URL: "https://cloud.example.net", Username: "me", Password: "pw"}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.config.Validate(); (err == nil) != test.ok {
+				t.Errorf("got %v, want ok %v", err, test.ok)
+			}
+		})
+	}
+}
+
+func TestReadPassword(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs printf")
+	}
+	tests := []struct {
+		name   string
+		config Config
+		want   string
+		ok     bool
+	}{
+		{"password set", Config{Password: "pw", PasswordCommand: "false"}, "pw", true},
+		{"first line", Config{PasswordCommand: "printf secret\\nsecond"}, "secret", true},
+		{"blank", Config{PasswordCommand: " "}, "", false},
+		{"prints nothing", Config{PasswordCommand: "true"}, "", false},
+		{"fails", Config{PasswordCommand: "false"}, "", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := test.config
+			err := config.ReadPassword(context.Background())
+			if (err == nil) != test.ok {
+				t.Fatalf("got %v, want ok %v", err, test.ok)
+			}
+			if config.Password != test.want {
+				t.Errorf("got %q, want %q", config.Password, test.want)
+			}
+		})
+	}
+}
+
+func TestItemID(t *testing.T) {
+	tests := []struct {
+		guid string
+		id   int64
+		ok   bool
+	}{
+		{"nextcloud:item/42", 42, true},
+		{"nextcloud:item/", 0, false},
+		{"nextcloud:item/x", 0, false},
+		{"miniflux:entry/42", 0, false},
+		{"https://example.com/42", 0, false},
+	}
+	for _, test := range tests {
+		t.Run(test.guid, func(t *testing.T) {
+			id, ok := ItemID(&gofeed.Item{GUID: test.guid})
+			if id != test.id || ok != test.ok {
+				t.Errorf("got %d, %v, want %d, %v", id, ok, test.id, test.ok)
+			}
+		})
+	}
+}
+
+func TestSavedSubscriptions(t *testing.T) {
+	up := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !up {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		switch r.URL.Path {
+		case apiPath + "/folders":
+			io.WriteString(w, `{"folders":[{"id":1,"name":"news"}]}`)
+		case apiPath + "/feeds":
+			io.WriteString(w, `{"feeds":[{"id":7,"title":"Example","url":"https://example.com/feed.xml","folderId":1}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "nextcloud.json")
+	want := Subscriptions{
+		Folders: []Folder{{ID: 1, Name: "news"}},
+		Feeds:   []Feed{{ID: 7, Title: "Example", URL: "https://example.com/feed.xml", FolderID: 1}},
+	}
+
+	// nothing's been saved yet
+	up = false
+	c := NewClient(Config{URL: server.URL, Username: "me", Password: "pw", Items: 1}, path, nil)
+	if subscriptions, err := c.SavedSubscriptions(context.Background(), path); err == nil || len(subscriptions.Feeds) > 0 {
+		t.Errorf("got %v, %v with the server down and nothing saved, want an error", subscriptions, err)
+	}
+
+	up = true
+	subscriptions, err := c.SavedSubscriptions(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(subscriptions, want) {
+		t.Errorf("got %v, want %v", subscriptions, want)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("the list wasn't saved: %s", err)
+	}
+
+	// starting without the server, the saved list comes back with the error
+	up = false
+	c = NewClient(Config{URL: server.URL, Username: "me", Password: "pw", Items: 1}, path, nil)
+	subscriptions, err = c.SavedSubscriptions(context.Background(), path)
+	if err == nil {
+		t.Error("got no error with the server down")
+	}
+	if !reflect.DeepEqual(subscriptions, want) {
+		t.Errorf("got %v, want the saved %v", subscriptions, want)
+	}
+	if feed := c.feeds[7]; feed.Title != "Example" {
+		t.Errorf("the saved feeds weren't kept for reading them: %v", c.feeds)
+	}
+}
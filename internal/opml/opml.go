@@ -0,0 +1,88 @@
+// Package opml reads and writes OPML subscription lists, the format feed
+// readers import and export feeds in.
+package opml
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// Document is an OPML file; only the parts that matter for subscriptions
+// are kept.
+type Document struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Title   string   `xml:"head>title"`
+	// DateCreated is an RFC 822 date, as OPML 2.0 wants
+	DateCreated string    `xml:"head>dateCreated,omitempty"`
+	Body        []Outline `xml:"body>outline"`
+}
+
+// Outline is an entry in the outline: a feed if it has an XMLURL, otherwise
+// usually a folder of them.
+type Outline struct {
+	Text     string    `xml:"text,attr"`
+	Title    string    `xml:"title,attr,omitempty"`
+	Type     string    `xml:"type,attr,omitempty"`
+	XMLURL   string    `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string    `xml:"htmlUrl,attr,omitempty"`
+	Outlines []Outline `xml:"outline"`
+}
+
+// Feed is a subscription found in an outline.
+type Feed struct {
+	Url   string
+	Title string
+	// Group is the folder the feed was filed under, if any. Nested folders
+	// go by the innermost one.
+	Group string
+}
+
+// Parse reads an OPML document.
+func Parse(r io.Reader) (*Document, error) {
+	var doc Document
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// Feeds flattens the outline into its feeds, in document order.
+func (d *Document) Feeds() []Feed {
+	return collectFeeds(d.Body, "")
+}
+
+func collectFeeds(outlines []Outline, group string) []Feed {
+	var feeds []Feed
+	for _, outline := range outlines {
+		title := strings.TrimSpace(outline.Title)
+		if title == "" {
+			title = strings.TrimSpace(outline.Text)
+		}
+		if feedUrl := strings.TrimSpace(outline.XMLURL); feedUrl != "" {
+			feeds = append(feeds, Feed{Url: feedUrl, Title: title, Group: group})
+		}
+		if len(outline.Outlines) > 0 {
+			feeds = append(feeds, collectFeeds(outline.Outlines, title)...)
+		}
+	}
+	return feeds
+}
+
+// Write writes an OPML document, declaration and all.
+func Write(w io.Writer, doc Document) error {
+	if doc.Version == "" {
+		doc.Version = "2.0"
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,108 @@
+package opml
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFeeds(t *testing.T) {
+	tests := []struct {
+		name string
+		opml string
+		want []Feed
+	}{
+		{
+			"flat",
+			`<opml version="1.0"><body>
+				<outline text="A" xmlUrl="http://a/feed"/>
+				<outline text="B" title="Bee" xmlUrl=" http://b/feed "/>
+			</body></opml>`,
+			[]Feed{{Url: "http://a/feed", Title: "A"}, {Url: "http://b/feed", Title: "Bee"}},
+		},
+		{
+			"folders",
+			`<opml version="2.0"><body>
+				<outline text="Tech">
+					<outline text="A" xmlUrl="http://a/feed"/>
+					<outline text="Go">
+						<outline text="B" xmlUrl="http://b/feed"/>
+					</outline>
+				</outline>
+				<outline text="C" xmlUrl="http://c/feed"/>
+			</body></opml>`,
+			[]Feed{
+				{Url: "http://a/feed", Title: "A", Group: "Tech"},
+				{Url: "http://b/feed", Title: "B", Group: "Go"},
+				{Url: "http://c/feed", Title: "C"},
+			},
+		},
+		{
+			"outlines that aren't feeds",
+			`<opml version="2.0"><body>
+				<outline text="Just a note"/>
+				<outline text="Empty" xmlUrl="  "/>
+			</body></opml>`,
+			nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doc, err := Parse(strings.NewReader(test.opml))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := doc.Feeds(); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Feeds() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseError(t *testing.T) {
+	if _, err := Parse(strings.NewReader("<opml><body>")); err == nil {
+		t.Error("a truncated document parsed")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	doc := Document{
+		Title: "Subscriptions",
+		Body: []Outline{
+			{Text: "Tech", Outlines: []Outline{
+				{Text: "A & B", Title: "A & B", Type: "rss", XMLURL: "http://a/feed?x=1&y=2", HTMLURL: "http://a/"},
+			}},
+			{Text: "C", Type: "rss", XMLURL: "http://c/feed"},
+		},
+	}
+	var out bytes.Buffer
+	if err := Write(&out, doc); err != nil {
+		t.Fatal(err)
+	}
+	written := out.String()
+	if !strings.HasPrefix(written, `<?xml version="1.0" encoding="UTF-8"?>`) {
+		t.Errorf("no XML declaration: %s", written)
+	}
+	if !strings.Contains(written, `<opml version="2.0">`) {
+		t.Errorf("no version: %s", written)
+	}
+	if strings.Contains(written, "dateCreated") {
+		t.Errorf("empty dateCreated written: %s", written)
+	}
+
+	read, err := Parse(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Feed{
+		{Url: "http://a/feed?x=1&y=2", Title: "A & B", Group: "Tech"},
+		{Url: "http://c/feed", Title: "C"},
+	}
+	if got := read.Feeds(); !reflect.DeepEqual(got, want) {
+		t.Errorf("read back %+v, want %+v", got, want)
+	}
+	if read.Title != doc.Title {
+		t.Errorf("read back title %q, want %q", read.Title, doc.Title)
+	}
+}
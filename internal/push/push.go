@@ -0,0 +1,154 @@
+// Package push sends new-item alerts to push notification services, for
+// phones to pick up when the reader isn't running on the desktop.
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Services are the push services a Target can be.
+var Services = []string{"ntfy", "gotify"}
+
+// Target is an entry in the `push` config section: where alerts go.
+type Target struct {
+	// Service is ntfy or gotify.
+	Service string `mapstructure:"service"`
+	// URL is the ntfy topic (like https://ntfy.sh/my-feeds) or the Gotify
+	// server.
+	URL string `mapstructure:"url"`
+	// Token is an ntfy access token, or the Gotify application token.
+	Token string `mapstructure:"token"`
+	// Priority is passed on as is: 1 to 5 for ntfy, 0 to 10 for Gotify. 0
+	// leaves it to the service.
+	Priority int `mapstructure:"priority"`
+}
+
+// Message is an alert.
+type Message struct {
+	Title string
+	Body  string
+	// Link, if set, is opened when the notification is tapped.
+	Link string
+}
+
+// timeout is how long a push may take. They're sent from the background, but
+// a service that's down shouldn't keep them piling up.
+const timeout = 10 * time.Second
+
+// Validate checks a target has what its service needs.
+func (t Target) Validate() error {
+	if t.Service != "ntfy" && t.Service != "gotify" {
+		return fmt.Errorf("push: service %q isn't one of %s", t.Service, strings.Join(Services, ", "))
+	}
+	parsed, err := url.Parse(t.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("push: %s url %q isn't an http(s) URL", t.Service, t.URL)
+	}
+	if t.Service == "gotify" && t.Token == "" {
+		return fmt.Errorf("push: gotify %s needs an application token", t.URL)
+	}
+	return nil
+}
+
+// Send pushes a message to a target.
+func Send(ctx context.Context, t Target, msg Message) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var req *http.Request
+	var err error
+	switch t.Service {
+	case "ntfy":
+		req, err = ntfyRequest(ctx, t, msg)
+	case "gotify":
+		req, err = gotifyRequest(ctx, t, msg)
+	default:
+		return fmt.Errorf("push: unknown service %q", t.Service)
+	}
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "golang-rss-client")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("push: %s %s: %s", t.Service, t.URL, resp.Status)
+	}
+	return nil
+}
+
+// ntfyRequest publishes to an ntfy topic: the body is the message, and the
+// rest goes in headers.
+func ntfyRequest(ctx context.Context, t Target, msg Message) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.URL, strings.NewReader(msg.Body))
+	if err != nil {
+		return nil, err
+	}
+	// headers have to be ASCII, which ntfy gets around with RFC 2047
+	req.Header.Set("Title", encodeHeader(msg.Title))
+	if msg.Link != "" {
+		req.Header.Set("Click", msg.Link)
+	}
+	if t.Priority > 0 {
+		req.Header.Set("Priority", fmt.Sprint(t.Priority))
+	}
+	if t.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+t.Token)
+	}
+	return req, nil
+}
+
+// gotifyRequest posts a message to a Gotify server's message endpoint.
+func gotifyRequest(ctx context.Context, t Target, msg Message) (*http.Request, error) {
+	body := map[string]interface{}{
+		"title":   msg.Title,
+		"message": msg.Body,
+	}
+	if t.Priority > 0 {
+		body["priority"] = t.Priority
+	}
+	if msg.Link != "" {
+		body["extras"] = map[string]interface{}{
+			"client::notification": map[string]interface{}{
+				"click": map[string]string{"url": msg.Link},
+			},
+		}
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := strings.TrimSuffix(t.URL, "/") + "/message"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", t.Token)
+	return req, nil
+}
+
+// encodeHeader makes a header value safe to send, encoding it if it isn't
+// plain ASCII.
+func encodeHeader(value string) string {
+	for _, r := range value {
+		if r >= 0x80 || r < 0x20 {
+			return mime.QEncoding.Encode("utf-8", value)
+		}
+	}
+	return value
+}
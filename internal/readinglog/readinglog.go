@@ -0,0 +1,290 @@
+// Package readinglog keeps a markdown log of what was read and starred each
+// day in a git repository, committing it as it grows, for people who keep
+// track of their reading in public.
+package readinglog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultFile is where each day's log goes unless the config says otherwise.
+const defaultFile = "{{year}}/{{date}}.md"
+
+// gitTimeout bounds each git command, pushing included.
+const gitTimeout = 30 * time.Second
+
+// Config is the `readingLog` config section.
+type Config struct {
+	// Repo is the git working tree the log is kept in; empty keeps no log.
+	Repo string `mapstructure:"repo"`
+	// File is the path of each day's log in the repository: {{year}},
+	// {{month}} and {{date}} are filled in.
+	File string `mapstructure:"file"`
+	// CommitEvery is how many minutes entries wait to be committed, so
+	// they're committed a few at a time; 0 commits them on quitting only.
+	CommitEvery int `mapstructure:"commitEvery"`
+	// Push pushes every commit to the repository's upstream.
+	Push bool `mapstructure:"push"`
+}
+
+// Validate checks the log's file stays inside the repository.
+func (c Config) Validate() error {
+	if c.File == "" {
+		return nil
+	}
+	if filepath.IsAbs(c.File) || strings.HasPrefix(filepath.Clean(c.File), "..") {
+		return fmt.Errorf("readingLog: file %q should be a path inside the repository", c.File)
+	}
+	return nil
+}
+
+// Entry is something done with an article.
+type Entry struct {
+	// Action is what was done, like "Read" or "Starred".
+	Action string
+	Title  string
+	Link   string
+	Feed   string
+	At     time.Time
+}
+
+// Log collects entries until they're committed. A nil *Log logs nothing.
+type Log struct {
+	config Config
+	repo   string
+
+	mu      sync.Mutex
+	pending []Entry
+	// committing makes sure commits happen one at a time
+	committing sync.Mutex
+	// uncommitted are the files written to that git didn't commit, and the
+	// days in them, kept under committing for the next commit to take in
+	uncommitted     map[string]bool
+	uncommittedDays map[string]bool
+}
+
+// New starts a log kept as configured, checking the repository is one.
+func New(config Config) (*Log, error) {
+	repo := expandHome(config.Repo)
+	ctx, cancel := context.WithTimeout(context.Background(), gitTimeout)
+	defer cancel()
+	if _, err := git(ctx, repo, "rev-parse", "--is-inside-work-tree"); err != nil {
+		return nil, fmt.Errorf("readingLog: %s isn't a git repository: %w", config.Repo, err)
+	}
+	return &Log{
+		config:          config,
+		repo:            repo,
+		uncommitted:     make(map[string]bool),
+		uncommittedDays: make(map[string]bool),
+	}, nil
+}
+
+// Add records an entry, to be committed with the next lot.
+func (l *Log) Add(entry Entry) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pending = append(l.pending, entry)
+}
+
+// Due reports whether the oldest entry not yet committed has waited long
+// enough.
+func (l *Log) Due(now time.Time) bool {
+	if l == nil || l.config.CommitEvery <= 0 {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.pending) > 0 && now.Sub(l.pending[0].At) >= time.Duration(l.config.CommitEvery)*time.Minute
+}
+
+// Commit adds the entries waiting to the days' logs and commits them, then
+// pushes if that's configured. Entries that couldn't be written wait for the
+// next commit, and so do the files written that git couldn't commit; what
+// was written is committed either way.
+func (l *Log) Commit() error {
+	if l == nil {
+		return nil
+	}
+	l.committing.Lock()
+	defer l.committing.Unlock()
+	l.mu.Lock()
+	entries := l.pending
+	l.pending = nil
+	l.mu.Unlock()
+	if len(entries) == 0 && len(l.uncommitted) == 0 {
+		return nil
+	}
+
+	byFile := make(map[string][]Entry)
+	var order []string
+	for _, entry := range entries {
+		file := l.file(entry.At)
+		if _, ok := byFile[file]; !ok {
+			order = append(order, file)
+		}
+		byFile[file] = append(byFile[file], entry)
+	}
+	var writeErr error
+	written := make(map[string]bool)
+	for _, file := range order {
+		if writeErr = appendEntries(filepath.Join(l.repo, file), byFile[file]); writeErr != nil {
+			break
+		}
+		written[file] = true
+		l.uncommitted[file] = true
+		for _, entry := range byFile[file] {
+			l.uncommittedDays[entry.At.Format("2006-01-02")] = true
+		}
+	}
+	if writeErr != nil {
+		// the file that failed and the ones not reached yet, in the order
+		// their entries came in
+		var unwritten []Entry
+		for _, entry := range entries {
+			if !written[l.file(entry.At)] {
+				unwritten = append(unwritten, entry)
+			}
+		}
+		l.requeue(unwritten)
+	}
+	if len(l.uncommitted) == 0 {
+		return writeErr
+	}
+	files := sortedKeys(l.uncommitted)
+	days := sortedKeys(l.uncommittedDays)
+
+	ctx, cancel := context.WithTimeout(context.Background(), gitTimeout)
+	defer cancel()
+	if _, err := git(ctx, l.repo, append([]string{"add", "--"}, files...)...); err != nil {
+		return err
+	}
+	// files committed some other way since leave nothing to commit
+	if _, err := git(ctx, l.repo, append([]string{"diff", "--cached", "--quiet", "--"}, files...)...); err != nil {
+		message := "Reading log for " + strings.Join(days, ", ")
+		// only the log's files, whatever else is staged
+		if _, err := git(ctx, l.repo, append([]string{"commit", "--quiet", "-m", message, "--"}, files...)...); err != nil {
+			return err
+		}
+	}
+	l.uncommitted = make(map[string]bool)
+	l.uncommittedDays = make(map[string]bool)
+	if writeErr != nil {
+		return writeErr
+	}
+	if l.config.Push {
+		if _, err := git(ctx, l.repo, "push", "--quiet"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// requeue puts entries back in front of the ones that came in since.
+func (l *Log) requeue(entries []Entry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pending = append(entries, l.pending...)
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// file is the path of a day's log within the repository.
+func (l *Log) file(day time.Time) string {
+	template := l.config.File
+	if template == "" {
+		template = defaultFile
+	}
+	return filepath.FromSlash(strings.NewReplacer(
+		"{{year}}", day.Format("2006"),
+		"{{month}}", day.Format("01"),
+		"{{date}}", day.Format("2006-01-02"),
+	).Replace(template))
+}
+
+// appendEntries adds entries to a day's log, starting it with a heading if
+// it's new.
+func appendEntries(path string, entries []Entry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	var b bytes.Buffer
+	if info, err := file.Stat(); err == nil && info.Size() == 0 {
+		b.WriteString("# Reading log for " + entries[0].At.Format("Monday, 2 January 2006") + "\n\n")
+	}
+	for _, entry := range entries {
+		b.WriteString(entryLine(entry) + "\n")
+	}
+	_, err = file.Write(b.Bytes())
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// entryLine is an entry as a markdown list item.
+func entryLine(entry Entry) string {
+	title := strings.Join(strings.Fields(entry.Title), " ")
+	if title == "" {
+		title = "Untitled"
+	}
+	title = strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`).Replace(title)
+	if entry.Link != "" {
+		title = "[" + title + "](" + strings.NewReplacer("(", "%28", ")", "%29", " ", "%20").Replace(entry.Link) + ")"
+	}
+	line := fmt.Sprintf("- %s %s %s", entry.At.Format("15:04"), entry.Action, title)
+	if feed := strings.Join(strings.Fields(entry.Feed), " "); feed != "" {
+		line += " in *" + strings.NewReplacer("*", `\*`).Replace(feed) + "*"
+	}
+	return line
+}
+
+// git runs a git command in the repository, returning its output. Failures
+// come with what git said about them.
+func git(ctx context.Context, repo string, args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", repo}, args...)...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if reason := strings.TrimSpace(stderr.String()); reason != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], reason)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return string(out), nil
+}
+
+// expandHome replaces a leading ~ with the home directory, as shells do.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
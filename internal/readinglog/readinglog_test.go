@@ -0,0 +1,155 @@
+package readinglog
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newRepo makes a git repository to keep a log in.
+func newRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+	repo := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"config", "user.name", "Reader"},
+		{"config", "user.email", "reader@example.com"},
+		{"config", "commit.gpgsign", "false"},
+	} {
+		if _, err := git(context.Background(), repo, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return repo
+}
+
+// commits are the messages of the repository's commits, oldest first.
+func commits(t *testing.T, repo string) []string {
+	t.Helper()
+	out, err := git(context.Background(), repo, "log", "--reverse", "--format=%s")
+	if err != nil {
+		return nil
+	}
+	return strings.Split(strings.TrimSpace(out), "\n")
+}
+
+func day(d int) time.Time {
+	return time.Date(2026, 3, d, 9, 30, 0, 0, time.UTC)
+}
+
+func TestCommit(t *testing.T) {
+	repo := newRepo(t)
+	l, err := New(Config{Repo: repo})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Add(Entry{Action: "Read", Title: "One [draft]", Link: "https://x/a b", Feed: "*Go*", At: day(1)})
+	l.Add(Entry{Action: "Starred", Title: "Two", At: day(2)})
+	if err := l.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if got := commits(t, repo); len(got) != 1 || got[0] != "Reading log for 2026-03-01, 2026-03-02" {
+		t.Errorf("commits %q", got)
+	}
+	data, err := os.ReadFile(filepath.Join(repo, "2026", "2026-03-01.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Reading log for Sunday, 1 March 2026\n\n- 09:30 Read [One \\[draft\\]](https://x/a%20b) in *\\*Go\\**\n"
+	if string(data) != want {
+		t.Errorf("log is %q, want %q", data, want)
+	}
+	if err := l.Commit(); err != nil || len(commits(t, repo)) != 1 {
+		t.Errorf("a commit with nothing waiting made one (%v)", err)
+	}
+}
+
+func TestCommitWriteFails(t *testing.T) {
+	repo := newRepo(t)
+	l, err := New(Config{Repo: repo})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a directory where the second day's log goes can't be appended to
+	blocked := filepath.Join(repo, "2026", "2026-03-02.md")
+	if err := os.MkdirAll(blocked, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for d := 1; d <= 3; d++ {
+		l.Add(Entry{Action: "Read", Title: "Day", At: day(d)})
+	}
+	if err := l.Commit(); err == nil {
+		t.Fatal("Commit didn't report the log it couldn't write")
+	}
+	if got := commits(t, repo); len(got) != 1 || got[0] != "Reading log for 2026-03-01" {
+		t.Errorf("what was written wasn't committed: %q", got)
+	}
+	if len(l.pending) != 2 || !l.pending[0].At.Equal(day(2)) || !l.pending[1].At.Equal(day(3)) {
+		t.Errorf("pending %v, want the second and third days' entries", l.pending)
+	}
+
+	os.Remove(blocked)
+	if err := l.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if got := commits(t, repo); len(got) != 2 || got[1] != "Reading log for 2026-03-02, 2026-03-03" {
+		t.Errorf("commits %q", got)
+	}
+}
+
+func TestCommitGitFails(t *testing.T) {
+	repo := newRepo(t)
+	l, err := New(Config{Repo: repo})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hook := filepath.Join(repo, ".git", "hooks", "pre-commit")
+	if err := os.WriteFile(hook, []byte("#!/bin/sh\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	l.Add(Entry{Action: "Read", Title: "Day", At: day(1)})
+	if err := l.Commit(); err == nil {
+		t.Fatal("Commit didn't report git failing")
+	}
+	if len(l.pending) != 0 {
+		t.Errorf("entries written were queued again: %v", l.pending)
+	}
+
+	os.Remove(hook)
+	if err := l.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if got := commits(t, repo); len(got) != 1 || got[0] != "Reading log for 2026-03-01" {
+		t.Errorf("the file written wasn't committed next time: %q", got)
+	}
+	data, _ := os.ReadFile(filepath.Join(repo, "2026", "2026-03-01.md"))
+	if strings.Count(string(data), "Read Day") != 1 {
+		t.Errorf("the entry was written %d times", strings.Count(string(data), "Read Day"))
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		file string
+		ok   bool
+	}{
+		{"", true},
+		{"{{year}}/{{month}}/{{date}}.md", true},
+		{"log.md", true},
+		{"/tmp/log.md", false},
+		{"../outside.md", false},
+		{"a/../../outside.md", false},
+	}
+	for _, test := range tests {
+		if err := (Config{File: test.file}).Validate(); (err == nil) != test.ok {
+			t.Errorf("Validate(%q) = %v, want ok %v", test.file, err, test.ok)
+		}
+	}
+}
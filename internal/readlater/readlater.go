@@ -0,0 +1,248 @@
+// Package readlater saves articles to a read-later service: Pocket,
+// Instapaper or Wallabag.
+package readlater
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Services are the services Config.Service can be.
+var Services = []string{"pocket", "instapaper", "wallabag"}
+
+// pocketAdd and instapaperAdd are where Pocket and Instapaper take articles.
+const (
+	pocketAdd     = "https://getpocket.com/v3/add"
+	instapaperAdd = "https://www.instapaper.com/api/add"
+)
+
+// timeout is how long saving may take before it's given up on.
+const timeout = 15 * time.Second
+
+// Config is the `readLater` config section: the service articles are saved to
+// with w.
+type Config struct {
+	// Service is pocket, instapaper or wallabag; empty saves nowhere.
+	Service string `mapstructure:"service"`
+	// ConsumerKey is a Pocket app's, and AccessToken the one Pocket gave it
+	// for the account.
+	ConsumerKey string `mapstructure:"consumerKey"`
+	AccessToken string `mapstructure:"accessToken"`
+	// Username and Password log in to Instapaper, or to Wallabag along with
+	// the ClientID and ClientSecret of a client made under API clients
+	// management there.
+	Username     string `mapstructure:"username"`
+	Password     string `mapstructure:"password"`
+	URL          string `mapstructure:"url"`
+	ClientID     string `mapstructure:"clientId"`
+	ClientSecret string `mapstructure:"clientSecret"`
+}
+
+// Article is what's saved.
+type Article struct {
+	Title string
+	Link  string
+}
+
+// Validate checks the service has what it needs.
+func (c Config) Validate() error {
+	switch c.Service {
+	case "":
+		return nil
+	case "pocket":
+		if c.ConsumerKey == "" || c.AccessToken == "" {
+			return fmt.Errorf("readLater: pocket needs a consumerKey and an accessToken")
+		}
+	case "instapaper":
+		if c.Username == "" {
+			return fmt.Errorf("readLater: instapaper needs a username")
+		}
+	case "wallabag":
+		parsed, err := url.Parse(c.URL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("readLater: wallabag url %q isn't an http(s) URL", c.URL)
+		}
+		if c.ClientID == "" || c.ClientSecret == "" || c.Username == "" || c.Password == "" {
+			return fmt.Errorf("readLater: wallabag needs a clientId, clientSecret, username and password")
+		}
+	default:
+		return fmt.Errorf("readLater: service %q isn't one of %s", c.Service, strings.Join(Services, ", "))
+	}
+	return nil
+}
+
+// Name is the service's, as it calls itself.
+func (c Config) Name() string {
+	switch c.Service {
+	case "pocket":
+		return "Pocket"
+	case "instapaper":
+		return "Instapaper"
+	case "wallabag":
+		return "Wallabag"
+	}
+	return c.Service
+}
+
+// Save saves an article to the service.
+func (c Config) Save(ctx context.Context, article Article) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	switch c.Service {
+	case "pocket":
+		return c.savePocket(ctx, article)
+	case "instapaper":
+		return c.saveInstapaper(ctx, article)
+	case "wallabag":
+		return c.saveWallabag(ctx, article)
+	}
+	return fmt.Errorf("readLater: no service is configured")
+}
+
+// savePocket adds the article to Pocket, which explains failures in the
+// X-Error header rather than the body.
+func (c Config) savePocket(ctx context.Context, article Article) error {
+	req, err := jsonRequest(ctx, pocketAdd, map[string]string{
+		"url":          article.Link,
+		"title":        article.Title,
+		"consumer_key": c.ConsumerKey,
+		"access_token": c.AccessToken,
+	})
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "golang-rss-client")
+	req.Header.Set("X-Accept", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("pocket: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		if reason := resp.Header.Get("X-Error"); reason != "" {
+			return fmt.Errorf("pocket: %s", reason)
+		}
+		return fmt.Errorf("pocket: %s", resp.Status)
+	}
+	return nil
+}
+
+// saveInstapaper adds the article through Instapaper's simple API, which
+// answers with nothing but a status.
+func (c Config) saveInstapaper(ctx context.Context, article Article) error {
+	form := url.Values{"url": {article.Link}, "title": {article.Title}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, instapaperAdd, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "golang-rss-client")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(c.Username, c.Password)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("instapaper: %w", err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusCreated, http.StatusOK:
+		return nil
+	case http.StatusForbidden:
+		return fmt.Errorf("instapaper: the username or password is wrong")
+	case http.StatusBadRequest:
+		return fmt.Errorf("instapaper: the link was turned down")
+	}
+	return fmt.Errorf("instapaper: %s", resp.Status)
+}
+
+// saveWallabag logs in to Wallabag (OAuth, with the password grant) and adds
+// the article as an entry.
+func (c Config) saveWallabag(ctx context.Context, article Article) error {
+	server := strings.TrimSuffix(c.URL, "/")
+	form := url.Values{
+		"grant_type":    {"password"},
+		"client_id":     {c.ClientID},
+		"client_secret": {c.ClientSecret},
+		"username":      {c.Username},
+		"password":      {c.Password},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server+"/oauth/v2/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := do(req, "wallabag", &token); err != nil {
+		return err
+	}
+	req, err = jsonRequest(ctx, server+"/api/entries.json", map[string]string{
+		"url":   article.Link,
+		"title": article.Title,
+	})
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	return do(req, "wallabag", nil)
+}
+
+// jsonRequest is a POST of body as JSON.
+func jsonRequest(ctx context.Context, endpoint string, body interface{}) (*http.Request, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// do makes a request of a JSON API, decoding the response into out if it's
+// given. Failures are explained with the error in the body, if there is one.
+func do(req *http.Request, service string, out interface{}) error {
+	req.Header.Set("User-Agent", "golang-rss-client")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", service, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var failure struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+			Message     string `json:"message"`
+		}
+		reason := resp.Status
+		if json.Unmarshal(data, &failure) == nil {
+			switch {
+			case failure.Description != "":
+				reason = failure.Description
+			case failure.Error != "":
+				reason = failure.Error
+			case failure.Message != "":
+				reason = failure.Message
+			}
+		}
+		return fmt.Errorf("%s: %s", service, reason)
+	}
+	if out != nil {
+		return json.Unmarshal(data, out)
+	}
+	return nil
+}
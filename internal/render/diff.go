@@ -0,0 +1,161 @@
+package render
+
+import (
+	"html"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// diffBlocks are the elements whose text makes up the paragraphs articles are
+// compared by.
+const diffBlocks = "p, li, h1, h2, h3, h4, h5, h6, blockquote, pre, td, th, dt, dd, figcaption"
+
+// maxDiffCells bounds the work of lining up two runs of paragraphs or words;
+// longer ones are shown as the first taken out and the second put in.
+const maxDiffCells = 1 << 22
+
+// edit is one step of turning one run of strings into another: keeping a
+// string ('='), taking one out ('-') or putting one in ('+').
+type edit struct {
+	op   byte
+	text string
+}
+
+// Diff is HTML showing what changed between two versions of an article,
+// paragraph by paragraph and word by word within changed paragraphs. Words
+// taken out are struck through and words put in are bold; formatting and
+// links are left out.
+func Diff(before, after string) string {
+	was, now := paragraphs(before), paragraphs(after)
+	var out strings.Builder
+	var removed, added []string
+	flush := func() {
+		for i := 0; i < len(removed) || i < len(added); i++ {
+			switch {
+			case i >= len(added):
+				writeParagraph(&out, []edit{{'-', removed[i]}})
+			case i >= len(removed):
+				writeParagraph(&out, []edit{{'+', added[i]}})
+			default:
+				writeParagraph(&out, diffRuns(strings.Fields(removed[i]), strings.Fields(added[i])))
+			}
+		}
+		removed, added = nil, nil
+	}
+	for _, e := range diffRuns(was, now) {
+		switch e.op {
+		case '-':
+			removed = append(removed, e.text)
+		case '+':
+			added = append(added, e.text)
+		default:
+			flush()
+			writeParagraph(&out, []edit{e})
+		}
+	}
+	flush()
+	return out.String()
+}
+
+// paragraphs are the text of an article's innermost blocks, with runs of
+// white space made single spaces. An article without any is split into lines.
+func paragraphs(content string) []string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	if err != nil {
+		return nil
+	}
+	var texts []string
+	add := func(text string) {
+		if text = strings.Join(strings.Fields(text), " "); text != "" {
+			texts = append(texts, text)
+		}
+	}
+	blocks := doc.Find(diffBlocks).FilterFunction(func(_ int, s *goquery.Selection) bool {
+		return s.Find(diffBlocks).Length() == 0
+	})
+	if blocks.Length() == 0 {
+		for _, line := range strings.Split(doc.Text(), "\n") {
+			add(line)
+		}
+		return texts
+	}
+	blocks.Each(func(_ int, s *goquery.Selection) {
+		add(s.Text())
+	})
+	return texts
+}
+
+// diffRuns lines up two runs of strings, keeping as many as it can in order
+// (a longest common subsequence), as the edits that turn the first into the
+// second.
+func diffRuns(a, b []string) []edit {
+	var edits []edit
+	if (len(a)+1)*(len(b)+1) > maxDiffCells {
+		for _, text := range a {
+			edits = append(edits, edit{'-', text})
+		}
+		for _, text := range b {
+			edits = append(edits, edit{'+', text})
+		}
+		return edits
+	}
+	// kept[i*width+j] is how many of a[i:] and b[j:] can be kept
+	width := len(b) + 1
+	kept := make([]int32, (len(a)+1)*width)
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				kept[i*width+j] = kept[(i+1)*width+j+1] + 1
+			case kept[(i+1)*width+j] >= kept[i*width+j+1]:
+				kept[i*width+j] = kept[(i+1)*width+j]
+			default:
+				kept[i*width+j] = kept[i*width+j+1]
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			edits = append(edits, edit{'=', a[i]})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && kept[(i+1)*width+j] >= kept[i*width+j+1]):
+			edits = append(edits, edit{'-', a[i]})
+			i++
+		default:
+			edits = append(edits, edit{'+', b[j]})
+			j++
+		}
+	}
+	return edits
+}
+
+// writeParagraph writes out a paragraph of edits, striking through each run
+// of words taken out and making each run put in bold.
+func writeParagraph(out *strings.Builder, edits []edit) {
+	out.WriteString("<p>")
+	for i := 0; i < len(edits); {
+		var words []string
+		j := i
+		for ; j < len(edits) && edits[j].op == edits[i].op; j++ {
+			words = append(words, edits[j].text)
+		}
+		if i > 0 {
+			out.WriteString(" ")
+		}
+		text := html.EscapeString(strings.Join(words, " "))
+		switch edits[i].op {
+		case '-':
+			out.WriteString("~~" + text + "~~")
+		case '+':
+			out.WriteString("<strong>" + text + "</strong>")
+		default:
+			out.WriteString(text)
+		}
+		i = j
+	}
+	out.WriteString("</p>")
+}
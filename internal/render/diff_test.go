@@ -0,0 +1,81 @@
+package render
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after string
+		want          string
+	}{
+		{
+			"unchanged",
+			"<p>One</p><p>Two</p>",
+			"<p>One</p><p>Two</p>",
+			"<p>One</p><p>Two</p>",
+		},
+		{
+			"words put in and taken out",
+			"<p>Hello old world</p><p>Same</p>",
+			"<p>Hello new world</p><p>Same</p><p>Added</p>",
+			"<p>Hello ~~old~~ <strong>new</strong> world</p><p>Same</p><p><strong>Added</strong></p>",
+		},
+		{
+			"paragraph taken out",
+			"<p>Keep</p><p>Drop this</p><p>Keep too</p>",
+			"<p>Keep</p><p>Keep too</p>",
+			"<p>Keep</p><p>~~Drop this~~</p><p>Keep too</p>",
+		},
+		{
+			"formatting left out and text escaped",
+			"<p>a <b>&lt;b&gt;</b></p>",
+			"<p>a <a href='x'>&lt;i&gt;</a></p>",
+			"<p>a ~~&lt;b&gt;~~ <strong>&lt;i&gt;</strong></p>",
+		},
+		{
+			"plain text by line",
+			"first\nsecond",
+			"first\nthird",
+			"<p>first</p><p>~~second~~ <strong>third</strong></p>",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Diff(test.before, test.after); got != test.want {
+				t.Errorf("got  %s\nwant %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParagraphs(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"blocks", "<p>One  two</p>\n<p>\n three </p>", []string{"One two", "three"}},
+		{"innermost blocks", "<blockquote><p>Quoted</p></blockquote><ul><li>Item</li></ul>", []string{"Quoted", "Item"}},
+		{"no blocks", "line one\n\n  line   two ", []string{"line one", "line two"}},
+		{"empty", "", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := paragraphs(test.content); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("paragraphs() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDiffRunsTooLong(t *testing.T) {
+	long := strings.Fields(strings.Repeat("word ", 3000))
+	edits := diffRuns(long, long)
+	if len(edits) != 2*len(long) || edits[0].op != '-' || edits[len(long)].op != '+' {
+		t.Errorf("runs past maxDiffCells weren't shown as taken out and put in")
+	}
+}
@@ -0,0 +1,47 @@
+package render
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// escapeSequence matches CSI (colors, cursor movement, ...) and OSC (window
+// titles, clipboard, hyperlinks, ...) sequences, so they can be dropped whole
+// rather than leaving their parameters behind as garbage.
+var escapeSequence = regexp.MustCompile(
+	"\x1b\\[[0-?]*[ -/]*[@-~]|\x1b\\][^\x07\x1b]*(\x07|\x1b\\\\)?",
+)
+
+// StripControl removes terminal escape sequences and any other control
+// characters apart from newlines and tabs, so text from a feed can't restyle
+// or otherwise take over the terminal once it's printed.
+func StripControl(s string) string {
+	s = escapeSequence.ReplaceAllString(s, "")
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' || !unicode.IsControl(r) {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// SanitizeFeed strips control characters from every field of a feed that
+// ends up on screen.
+func SanitizeFeed(feed *gofeed.Feed) {
+	feed.Title = StripControl(feed.Title)
+	feed.Description = StripControl(feed.Description)
+	for _, item := range feed.Items {
+		item.Title = StripControl(item.Title)
+		item.Description = StripControl(item.Description)
+		item.Content = StripControl(item.Content)
+		for _, author := range item.Authors {
+			author.Name = StripControl(author.Name)
+		}
+		if item.Author != nil {
+			item.Author.Name = StripControl(item.Author.Name)
+		}
+	}
+}
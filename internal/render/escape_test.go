@@ -0,0 +1,29 @@
+package render
+
+import "testing"
+
+func TestStripControl(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain text", "hello, world", "hello, world"},
+		{"newlines and tabs kept", "a\n\tb", "a\n\tb"},
+		{"color", "\x1b[31mred\x1b[0m", "red"},
+		{"cursor movement", "a\x1b[2Jb\x1b[10;20Hc", "abc"},
+		{"title terminated by BEL", "\x1b]0;pwned\x07text", "text"},
+		{"hyperlink terminated by ST", "\x1b]8;;http://x\x1b\\link\x1b]8;;\x1b\\", "link"},
+		{"unterminated OSC", "text\x1b]52;c;aGk=", "text"},
+		{"other control characters", "a\x00b\x08c\rd\x7f", "abcd"},
+		{"C1 controls", "a\u009bb", "ab"},
+		{"unicode kept", "café ☕", "café ☕"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := StripControl(test.in); got != test.want {
+				t.Errorf("StripControl(%q) = %q, want %q", test.in, got, test.want)
+			}
+		})
+	}
+}
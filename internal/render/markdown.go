@@ -0,0 +1,116 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+	"github.com/JohannesKaufmann/html-to-markdown/plugin"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Rules tune the HTML -> markdown conversion.
+type Rules struct {
+	// Plugins are html-to-markdown plugins, by their keys in the Plugins map.
+	Plugins []string `mapstructure:"plugins"`
+	// Keep lists tags that are passed through as raw HTML.
+	Keep []string `mapstructure:"keep"`
+	// Remove lists tags that are dropped along with their content.
+	Remove []string `mapstructure:"remove"`
+}
+
+// Display overrides how a single feed's items are rendered, for feeds whose
+// HTML doesn't survive the usual conversion.
+type Display struct {
+	// DescriptionOnly leaves the item content out, showing the description.
+	DescriptionOnly bool `mapstructure:"descriptionOnly"`
+	// NoMarkdown shows the converted markdown as is, without styling it.
+	NoMarkdown bool `mapstructure:"noMarkdown"`
+	// PlainText skips the markdown conversion too, dropping every tag.
+	PlainText bool `mapstructure:"plainText"`
+	// Width is the column lines are wrapped at, 0 for the width of the screen.
+	Width int `mapstructure:"width"`
+}
+
+// MarkdownConfig is the `markdown` config section. The top-level rules apply to
+// every feed; each entry in Feeds adds to them for a single feed, and can
+// change how it's displayed.
+type MarkdownConfig struct {
+	Rules `mapstructure:",squash"`
+	Feeds []struct {
+		Url     string `mapstructure:"url"`
+		Rules   `mapstructure:",squash"`
+		Display `mapstructure:",squash"`
+	} `mapstructure:"feeds"`
+}
+
+// Plugins are the html-to-markdown plugins that can be enabled by name.
+var Plugins = map[string]func() md.Plugin{
+	"strikethrough": func() md.Plugin { return plugin.Strikethrough("") },
+	"table":         plugin.Table,
+	"tableCompat":   plugin.TableCompat,
+	"taskList":      plugin.TaskListItems,
+	"gfm":           plugin.GitHubFlavored,
+}
+
+// NewConverter builds the HTML -> markdown converter. In low-bandwidth
+// mode images are reduced to their alt text so nothing invites loading them.
+func NewConverter(lowBandwidth bool, rules ...Rules) (*md.Converter, error) {
+	converter := md.NewConverter("", true, nil)
+	if lowBandwidth {
+		converter.AddRules(md.Rule{
+			Filter: []string{"img"},
+			Replacement: func(_ string, selec *goquery.Selection, _ *md.Options) *string {
+				alt := strings.TrimSpace(selec.AttrOr("alt", ""))
+				if alt == "" {
+					return md.String("")
+				}
+				return md.String("[image: " + alt + "]")
+			},
+		})
+	}
+
+	for _, r := range rules {
+		for _, name := range r.Plugins {
+			newPlugin, ok := Plugins[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown markdown plugin %q", name)
+			}
+			converter.Use(newPlugin())
+		}
+		converter.Keep(r.Keep...)
+		converter.Remove(r.Remove...)
+	}
+	return converter, nil
+}
+
+// Converters builds the default converter plus one for every feed that has
+// rules of its own, keyed by feed URL.
+func (c MarkdownConfig) Converters(lowBandwidth bool) (*md.Converter, map[string]*md.Converter, error) {
+	defaultConverter, err := NewConverter(lowBandwidth, c.Rules)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	feedConverters := make(map[string]*md.Converter)
+	for _, feed := range c.Feeds {
+		converter, err := NewConverter(lowBandwidth, c.Rules, feed.Rules)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", feed.Url, err)
+		}
+		feedConverters[feed.Url] = converter
+	}
+	return defaultConverter, feedConverters, nil
+}
+
+// Displays are the display overrides of every feed that has any, keyed by
+// feed URL.
+func (c MarkdownConfig) Displays() map[string]Display {
+	displays := make(map[string]Display)
+	for _, feed := range c.Feeds {
+		if feed.Display != (Display{}) {
+			displays[feed.Url] = feed.Display
+		}
+	}
+	return displays
+}
@@ -0,0 +1,221 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// The class and id hints readability-style extractors go by: unlikely
+// elements are dropped before scoring unless they also look likely, and both
+// weigh on the score of the elements that stay.
+var (
+	unlikelyHint = regexp.MustCompile(`(?i)banner|breadcrumb|combx|comment|community|cookie|disqus|extra|foot|header|legends|menu|modal|nav|newsletter|pager|popup|promo|related|remark|replies|rss|share|shoutbox|sidebar|skyscraper|social|sponsor|subscribe|tweet|widget`)
+	likelyHint   = regexp.MustCompile(`(?i)and|article|body|column|content|entry|hentry|main|page|post|shadow|story|text`)
+	negativeHint = regexp.MustCompile(`(?i)hidden|banner|combx|comment|contact|foot|footer|footnote|masthead|media|meta|outbrain|promo|related|scroll|share|shopping|sidebar|skyscraper|sponsor|tags|tool|widget`)
+	positiveHint = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|pagination|post|text|blog|story`)
+)
+
+// minParagraphRunes is how long a paragraph has to be to count towards the
+// score of the element it's in.
+const minParagraphRunes = 25
+
+// minArticleRunes is how much text the best candidate needs to be taken for
+// the article, rather than the page just not having one.
+const minArticleRunes = 140
+
+// Readable picks the main article out of a web page, the way readability
+// does: paragraphs score the elements they're in by how long they are and
+// how many commas they have, class and id names nudge the scores up or down,
+// and the best scoring element is taken along with any siblings that look
+// like part of it. Links and images are resolved against pageUrl, so the
+// HTML can be rendered like any item's.
+func Readable(page io.Reader, pageUrl string) (title string, content string, err error) {
+	doc, err := goquery.NewDocumentFromReader(page)
+	if err != nil {
+		return "", "", err
+	}
+	title = StripControl(strings.Join(strings.Fields(doc.Find("title").First().Text()), " "))
+
+	doc.Find("script, style, noscript, iframe, form, nav, aside, svg, button, input, select, textarea, link, meta").Remove()
+	doc.Find("*").Each(func(_ int, s *goquery.Selection) {
+		if s.Is("html, body, article, main") {
+			return
+		}
+		hints := s.AttrOr("class", "") + " " + s.AttrOr("id", "")
+		if unlikelyHint.MatchString(hints) && !likelyHint.MatchString(hints) {
+			s.Remove()
+		}
+	})
+
+	scores := make(map[*html.Node]float64)
+	var candidates []*html.Node
+	addScore := func(s *goquery.Selection, score float64) {
+		if s.Length() == 0 || s.Is("html") {
+			return
+		}
+		node := s.Get(0)
+		if _, ok := scores[node]; !ok {
+			scores[node] = initialScore(s)
+			candidates = append(candidates, node)
+		}
+		scores[node] += score
+	}
+	doc.Find("p, pre, td, blockquote, li").Each(func(_ int, s *goquery.Selection) {
+		text := strings.TrimSpace(s.Text())
+		runes := len([]rune(text))
+		if runes < minParagraphRunes {
+			return
+		}
+		score := 1 + float64(strings.Count(text, ",")) + float64(minInt(runes/100, 3))
+		addScore(s.Parent(), score)
+		addScore(s.Parent().Parent(), score/2)
+	})
+
+	var best *goquery.Selection
+	bestScore := 0.0
+	for _, node := range candidates {
+		s := doc.FindNodes(node)
+		score := scores[node] * (1 - linkDensity(s))
+		if best == nil || score > bestScore {
+			best, bestScore = s, score
+		}
+	}
+	if best == nil {
+		best = doc.Find("article, main").First()
+	}
+	if best.Length() == 0 || len([]rune(strings.TrimSpace(best.Text()))) < minArticleRunes {
+		return title, "", fmt.Errorf("no article found on %s", pageUrl)
+	}
+
+	// siblings scoring well enough are more of the same article, split up by
+	// the page's layout
+	threshold := bestScore * 0.2
+	if threshold < 10 {
+		threshold = 10
+	}
+	var parts []string
+	best.Parent().Children().Each(func(_ int, s *goquery.Selection) {
+		if s.Get(0) != best.Get(0) && !relatedSibling(s, scores, threshold) {
+			return
+		}
+		if part, err := goquery.OuterHtml(s); err == nil {
+			parts = append(parts, part)
+		}
+	})
+
+	article, err := goquery.NewDocumentFromReader(strings.NewReader(strings.Join(parts, "\n")))
+	if err != nil {
+		return title, "", err
+	}
+	resolveLinks(article, pageUrl)
+	content, err = article.Find("body").Html()
+	return title, content, err
+}
+
+// initialScore is what an element starts with before its paragraphs are
+// counted: a bit for the tags articles tend to be in, and its class and id.
+func initialScore(s *goquery.Selection) float64 {
+	score := 0.0
+	switch goquery.NodeName(s) {
+	case "article":
+		score += 10
+	case "div", "main", "section":
+		score += 5
+	case "pre", "td", "blockquote":
+		score += 3
+	case "ol", "ul", "dl", "form", "th":
+		score -= 3
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		score -= 5
+	}
+	for _, attr := range []string{"class", "id"} {
+		value := s.AttrOr(attr, "")
+		if value == "" {
+			continue
+		}
+		if negativeHint.MatchString(value) {
+			score -= 25
+		}
+		if positiveHint.MatchString(value) {
+			score += 25
+		}
+	}
+	return score
+}
+
+// linkDensity is how much of an element's text is in links, from 0 to 1.
+// Menus and lists of related articles are nearly all links.
+func linkDensity(s *goquery.Selection) float64 {
+	text := len(strings.TrimSpace(s.Text()))
+	if text == 0 {
+		return 0
+	}
+	links := 0
+	s.Find("a").Each(func(_ int, a *goquery.Selection) {
+		links += len(strings.TrimSpace(a.Text()))
+	})
+	return float64(links) / float64(text)
+}
+
+// relatedSibling reports whether a sibling of the best candidate belongs with
+// it: it scored well itself, or it's a paragraph of prose rather than links.
+func relatedSibling(s *goquery.Selection, scores map[*html.Node]float64, threshold float64) bool {
+	if scores[s.Get(0)] >= threshold {
+		return true
+	}
+	if !s.Is("p") {
+		return false
+	}
+	text := strings.TrimSpace(s.Text())
+	density := linkDensity(s)
+	runes := len([]rune(text))
+	if runes > 80 && density < 0.25 {
+		return true
+	}
+	return runes > 0 && density == 0 && strings.ContainsAny(text, ".!?")
+}
+
+// ResolveLinks makes the hrefs and image sources in a piece of HTML absolute,
+// for taking it somewhere the page it came from isn't known.
+func ResolveLinks(content string, pageUrl string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	if err != nil {
+		return content
+	}
+	resolveLinks(doc, pageUrl)
+	resolved, err := doc.Find("body").Html()
+	if err != nil {
+		return content
+	}
+	return resolved
+}
+
+// resolveLinks makes an article's hrefs and image sources absolute.
+func resolveLinks(doc *goquery.Document, pageUrl string) {
+	base, err := url.Parse(pageUrl)
+	if err != nil {
+		return
+	}
+	for _, attr := range []string{"href", "src"} {
+		doc.Find("[" + attr + "]").Each(func(_ int, s *goquery.Selection) {
+			ref, err := url.Parse(strings.TrimSpace(s.AttrOr(attr, "")))
+			if err != nil {
+				return
+			}
+			s.SetAttr(attr, base.ResolveReference(ref).String())
+		})
+	}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
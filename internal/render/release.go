@@ -0,0 +1,81 @@
+package render
+
+import (
+	"html"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// releaseLink matches the links of the items of GitHub's release and tag
+// feeds (releases.atom and tags.atom), capturing the tag.
+var releaseLink = regexp.MustCompile(`^https://github\.com/[^/]+/[^/]+/releases/tag/([^/?#]+)`)
+
+// checksum matches a SHA-256 or SHA-512 sum in release notes, and the file
+// it's for when it's written the way sha256sum prints it.
+var checksum = regexp.MustCompile(`(?i)\b([0-9a-f]{128}|[0-9a-f]{64})\b(?:[ \t]+\*?([\w.+-]+))?`)
+
+// ReleaseVersion is the version (tag) of a GitHub release or tag, from its
+// link, if it's one.
+func ReleaseVersion(link string) (string, bool) {
+	match := releaseLink.FindStringSubmatch(link)
+	if match == nil {
+		return "", false
+	}
+	version, err := url.PathUnescape(match[1])
+	if err != nil {
+		return "", false
+	}
+	return version, true
+}
+
+// Release is the HTML of a release's notes laid out for reading: headed by
+// its version, and with the files to download and their checksums listed
+// after the notes.
+func Release(version string, notes string) string {
+	var out strings.Builder
+	out.WriteString("<h1>" + html.EscapeString(version) + "</h1>")
+	out.WriteString(notes)
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(notes))
+	if err != nil {
+		return out.String()
+	}
+
+	var assets []string
+	seen := make(map[string]bool)
+	doc.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		href := a.AttrOr("href", "")
+		if !strings.Contains(href, "/releases/download/") || seen[href] {
+			return
+		}
+		seen[href] = true
+		name := path.Base(href)
+		if unescaped, err := url.PathUnescape(name); err == nil {
+			name = unescaped
+		}
+		assets = append(assets, `<li><a href="`+html.EscapeString(href)+`"><code>`+html.EscapeString(name)+`</code></a></li>`)
+	})
+	if len(assets) > 0 {
+		out.WriteString("<h2>Downloads</h2><ul>" + strings.Join(assets, "") + "</ul>")
+	}
+
+	var sums []string
+	for _, match := range checksum.FindAllStringSubmatch(doc.Text(), -1) {
+		sum := strings.ToLower(match[1])
+		if seen[sum] {
+			continue
+		}
+		seen[sum] = true
+		if match[2] != "" {
+			sum += "  " + match[2]
+		}
+		sums = append(sums, html.EscapeString(sum))
+	}
+	if len(sums) > 0 {
+		out.WriteString("<h2>Checksums</h2><pre><code>" + strings.Join(sums, "\n") + "</code></pre>")
+	}
+	return out.String()
+}
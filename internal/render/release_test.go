@@ -0,0 +1,75 @@
+package render
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReleaseVersion(t *testing.T) {
+	tests := []struct {
+		link string
+		want string
+		ok   bool
+	}{
+		{"https://github.com/golang/go/releases/tag/go1.18", "go1.18", true},
+		{"https://github.com/x/y/releases/tag/v1.2.3?after=1", "v1.2.3", true},
+		{"https://github.com/x/y/releases/tag/release%2F2.0", "release/2.0", true},
+		{"https://github.com/x/y/releases", "", false},
+		{"https://gitlab.com/x/y/releases/tag/v1", "", false},
+		{"https://github.com/x/y/releases/tag/%zz", "", false},
+	}
+	for _, test := range tests {
+		t.Run(test.link, func(t *testing.T) {
+			got, ok := ReleaseVersion(test.link)
+			if got != test.want || ok != test.ok {
+				t.Errorf("ReleaseVersion() = %q, %v, want %q, %v", got, ok, test.want, test.ok)
+			}
+		})
+	}
+}
+
+func TestRelease(t *testing.T) {
+	sum := strings.Repeat("ab", 32)
+	tests := []struct {
+		name    string
+		notes   string
+		want    []string
+		notWant []string
+	}{
+		{
+			"notes only",
+			"<p>Bug fixes.</p>",
+			[]string{"<h1>v1 &amp; more</h1><p>Bug fixes.</p>"},
+			[]string{"Downloads", "Checksums"},
+		},
+		{
+			"downloads listed once",
+			`<a href="https://github.com/x/y/releases/download/v1/app%20linux.tar.gz">linux</a>` +
+				`<a href="https://github.com/x/y/releases/download/v1/app%20linux.tar.gz">again</a>` +
+				`<a href="https://example.com">site</a>`,
+			[]string{`<h2>Downloads</h2><ul><li><a href="https://github.com/x/y/releases/download/v1/app%20linux.tar.gz"><code>app linux.tar.gz</code></a></li></ul>`},
+			[]string{"example.com\"><code>"},
+		},
+		{
+			"checksums with their files",
+			"<pre>" + strings.ToUpper(sum) + "  app.tar.gz\n" + sum + " *app.tar.gz</pre>",
+			[]string{"<h2>Checksums</h2><pre><code>" + sum + "  app.tar.gz</code></pre>"},
+			nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := Release("v1 & more", test.notes)
+			for _, want := range test.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("%s\ndoesn't contain %s", got, want)
+				}
+			}
+			for _, notWant := range test.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("%s\ncontains %s", got, notWant)
+				}
+			}
+		})
+	}
+}
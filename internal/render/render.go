@@ -0,0 +1,109 @@
+// Package render turns the HTML in feed items into text for the terminal,
+// making sure nothing in a feed can take the terminal over along the way.
+package render
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+	"github.com/charmbracelet/glamour"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/muesli/reflow/wordwrap"
+)
+
+// sanitizer strips scripts, iframes, styles, event handlers and the like from
+// feed HTML, keeping the markup you'd expect in user-generated content.
+var sanitizer = bluemonday.UGCPolicy()
+
+// stripper drops every tag, for plain text.
+var stripper = bluemonday.StrictPolicy()
+
+// lineBreak matches the tags that end a line, which would otherwise run into
+// the next once the tags are stripped.
+var lineBreak = regexp.MustCompile(`(?i)<(br|hr|/p|/div|/li|/h[1-6]|/tr|/blockquote|/pre)\b[^>]*>`)
+
+// blankLines matches runs of more than one blank line.
+var blankLines = regexp.MustCompile(`\n\s*\n(\s*\n)+`)
+
+// Prepare converts an item's HTML to the markdown that Style styles, wrapped
+// at width unless display says otherwise. For feeds displayed as plain text or
+// unstyled markdown that's the finished article, so styled is false.
+func Prepare(content string, markdownConverter *md.Converter, display Display, width int) (text string, styled bool, err error) {
+	width = display.WrapWidth(width)
+	// unescape HTML entities
+	content = html.UnescapeString(content)
+	if display.PlainText {
+		return plainText(content, width), false, nil
+	}
+	// feeds are untrusted, so sanitize after unescaping in case the entities
+	// were hiding markup (or escape characters)
+	content = StripControl(sanitizer.Sanitize(content))
+	// pass to HTML -> markdown converter (oops)
+	content, err = markdownConverter.ConvertString(content)
+	if err != nil {
+		return "", false, err
+	}
+	if display.NoMarkdown {
+		return wordwrap.String(content, width), false, nil
+	}
+	return content, true, nil
+}
+
+// NewRenderer sets up glamour to style markdown in the named style, wrapped
+// at width. Renderers can be reused for as long as the width stays the same.
+func NewRenderer(style string, width int) (*glamour.TermRenderer, error) {
+	return glamour.NewTermRenderer(
+		glamour.WithStylePath(style),
+		glamour.WithWordWrap(width),
+	)
+}
+
+// Style styles markdown from Prepare.
+func Style(markdown string, renderer *glamour.TermRenderer) (string, error) {
+	return renderer.Render(markdown)
+}
+
+// Chunks splits markdown into pieces of at least size lines that can be
+// styled one at a time, breaking only at blank lines outside code blocks.
+func Chunks(markdown string, size int) []string {
+	var chunks []string
+	var chunk []string
+	fenced := false
+	for _, line := range strings.Split(markdown, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			fenced = !fenced
+		}
+		if strings.TrimSpace(line) == "" && !fenced && len(chunk) >= size {
+			chunks = append(chunks, strings.Join(chunk, "\n"))
+			chunk = nil
+			continue
+		}
+		chunk = append(chunk, line)
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, strings.Join(chunk, "\n"))
+	}
+	return chunks
+}
+
+// WrapWidth is the width a feed's lines are wrapped at, given the width of
+// the screen.
+func (d Display) WrapWidth(width int) int {
+	if d.Width > 0 {
+		return d.Width
+	}
+	return width
+}
+
+// plainText is the text of some HTML, line breaks and all, wrapped at width.
+func plainText(content string, width int) string {
+	content = lineBreak.ReplaceAllString(content, "$0\n")
+	// the strict policy escapes what's left, so unescape once more; the
+	// control characters go last, in case entities like &#27; were hiding
+	// escape sequences
+	content = StripControl(html.UnescapeString(stripper.Sanitize(content)))
+	content = blankLines.ReplaceAllString(strings.TrimSpace(content), "\n\n")
+	return wordwrap.String(content, width) + "\n"
+}
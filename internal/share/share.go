@@ -0,0 +1,275 @@
+// Package share posts articles to chat rooms, through the services' bot APIs
+// or incoming webhooks, for passing something on without leaving the reader.
+package share
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Services are the chat services a Target can be.
+var Services = []string{"matrix", "telegram", "slack", "discord"}
+
+// Target is an entry in the `share` config section: a room articles can be
+// posted to.
+type Target struct {
+	// Name is how the target is listed in the reader. It defaults to the
+	// room or chat, or the service for webhooks.
+	Name string `mapstructure:"name"`
+	// Service is matrix, telegram, slack or discord.
+	Service string `mapstructure:"service"`
+	// URL is the Matrix homeserver, a Telegram Bot API server other than
+	// the official one, or the Slack or Discord incoming webhook.
+	URL string `mapstructure:"url"`
+	// Token is the Matrix access token, or the Telegram bot token.
+	Token string `mapstructure:"token"`
+	// Room is the Matrix room, by ID (!abc:matrix.org) or alias
+	// (#feeds:matrix.org).
+	Room string `mapstructure:"room"`
+	// Chat is the Telegram chat ID, or @username for a public channel.
+	Chat string `mapstructure:"chat"`
+}
+
+// Article is what's shared.
+type Article struct {
+	Title string
+	Link  string
+	// Summary is a few lines of the article in plain text, for the webhooks
+	// to post under the link.
+	Summary string
+}
+
+// telegramAPI is where Telegram bots are run from, unless a target has a
+// server of its own.
+const telegramAPI = "https://api.telegram.org"
+
+// timeout is how long sharing may take before it's given up on.
+const timeout = 10 * time.Second
+
+// Label is how the target is listed: its name, or where it posts to.
+func (t Target) Label() string {
+	if t.Name != "" {
+		return t.Name
+	}
+	switch t.Service {
+	case "matrix":
+		return t.Room
+	case "telegram":
+		return t.Chat
+	}
+	return t.Service
+}
+
+// describe names the target in errors: its service, and its label too if
+// it has one of its own.
+func (t Target) describe() string {
+	if label := t.Label(); label != t.Service && label != "" {
+		return t.Service + " " + label
+	}
+	return t.Service
+}
+
+// webhook reports whether a target is an incoming webhook, which needs
+// nothing but its URL.
+func (t Target) webhook() bool {
+	return t.Service == "slack" || t.Service == "discord"
+}
+
+// Validate checks a target has what its service needs.
+func (t Target) Validate() error {
+	if t.Service != "matrix" && t.Service != "telegram" && !t.webhook() {
+		return fmt.Errorf("share: service %q isn't one of %s", t.Service, strings.Join(Services, ", "))
+	}
+	if t.URL != "" || t.Service != "telegram" {
+		parsed, err := url.Parse(t.URL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("share: %s url %q isn't an http(s) URL", t.Service, t.URL)
+		}
+	}
+	if t.Token == "" && !t.webhook() {
+		return fmt.Errorf("share: %s needs a token", t.describe())
+	}
+	if t.Service == "matrix" && !strings.HasPrefix(t.Room, "!") && !strings.HasPrefix(t.Room, "#") {
+		return fmt.Errorf("share: matrix room %q isn't a room ID (!...) or alias (#...)", t.Room)
+	}
+	if t.Service == "telegram" && t.Chat == "" {
+		return fmt.Errorf("share: %s needs a chat", t.describe())
+	}
+	return nil
+}
+
+// Send posts an article's title and link to a target, and its summary to
+// the webhooks.
+func Send(ctx context.Context, t Target, article Article) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	switch t.Service {
+	case "matrix":
+		return sendMatrix(ctx, t, article)
+	case "telegram":
+		return sendTelegram(ctx, t, article)
+	case "slack":
+		return sendSlack(ctx, t, article)
+	case "discord":
+		return sendDiscord(ctx, t, article)
+	}
+	return fmt.Errorf("share: unknown service %q", t.Service)
+}
+
+// sendMatrix sends the article to a Matrix room as a message, with the title
+// linked for the clients that show HTML.
+func sendMatrix(ctx context.Context, t Target, article Article) error {
+	room := t.Room
+	if strings.HasPrefix(room, "#") {
+		var resolved struct {
+			RoomID string `json:"room_id"`
+		}
+		endpoint := matrixEndpoint(t, "directory/room/"+url.PathEscape(room))
+		if err := call(ctx, t, http.MethodGet, endpoint, nil, &resolved); err != nil {
+			return err
+		}
+		room = resolved.RoomID
+	}
+
+	body := map[string]string{
+		"msgtype": "m.text",
+		"body":    strings.TrimSpace(article.Title + "\n" + article.Link),
+	}
+	if article.Link != "" {
+		body["format"] = "org.matrix.custom.html"
+		body["formatted_body"] = fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(article.Link), html.EscapeString(article.Title))
+	}
+	// the transaction ID only has to be unique for the access token
+	txn := fmt.Sprint(time.Now().UnixNano())
+	endpoint := matrixEndpoint(t, "rooms/"+url.PathEscape(room)+"/send/m.room.message/"+txn)
+	return call(ctx, t, http.MethodPut, endpoint, body, nil)
+}
+
+func matrixEndpoint(t Target, path string) string {
+	return strings.TrimSuffix(t.URL, "/") + "/_matrix/client/v3/" + path
+}
+
+// sendTelegram has the bot post the article to a chat. Telegram shows a
+// preview of the link under it.
+func sendTelegram(ctx context.Context, t Target, article Article) error {
+	api := telegramAPI
+	if t.URL != "" {
+		api = strings.TrimSuffix(t.URL, "/")
+	}
+	body := map[string]string{
+		"chat_id": t.Chat,
+		"text":    strings.TrimSpace(article.Title + "\n" + article.Link),
+	}
+	return call(ctx, t, http.MethodPost, api+"/bot"+t.Token+"/sendMessage", body, nil)
+}
+
+// slackEscaper escapes the characters Slack's message formatting gives a
+// meaning to.
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// sendSlack posts the article to a Slack incoming webhook: the title linked,
+// with the summary quoted under it.
+func sendSlack(ctx context.Context, t Target, article Article) error {
+	text := slackEscaper.Replace(article.Title)
+	if article.Link != "" {
+		text = "<" + article.Link + "|" + text + ">"
+	}
+	if article.Summary != "" {
+		text += "\n>" + strings.ReplaceAll(slackEscaper.Replace(article.Summary), "\n", "\n>")
+	}
+	return call(ctx, t, http.MethodPost, t.URL, map[string]interface{}{
+		"text": text,
+		// the link is already there, a preview of it would only repeat the
+		// summary
+		"unfurl_links": false,
+	}, nil)
+}
+
+// sendDiscord posts the article to a Discord webhook as an embed, which
+// shows the title as a link over the summary.
+func sendDiscord(ctx context.Context, t Target, article Article) error {
+	embed := map[string]string{
+		"title":       article.Title,
+		"description": article.Summary,
+	}
+	if article.Link != "" {
+		embed["url"] = article.Link
+	}
+	return call(ctx, t, http.MethodPost, t.URL, map[string]interface{}{
+		"embeds": []map[string]string{embed},
+	}, nil)
+}
+
+// call makes a JSON API request, decoding the response into out if it's
+// given. The services explain failures in the body, which makes for a better
+// error than the status alone.
+func call(ctx context.Context, t Target, method string, endpoint string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "golang-rss-client")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if t.Service == "matrix" {
+		req.Header.Set("Authorization", "Bearer "+t.Token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		// the Telegram token is part of the URL, as webhooks' secrets are,
+		// so it mustn't end up in the log with it
+		reason := err.Error()
+		if t.Token != "" {
+			reason = strings.ReplaceAll(reason, t.Token, "<token>")
+		}
+		if t.webhook() {
+			reason = strings.ReplaceAll(reason, t.URL, "<webhook>")
+		}
+		return fmt.Errorf("%s: %s", t.describe(), reason)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var failure struct {
+			Error       string `json:"error"`
+			Description string `json:"description"`
+			Message     string `json:"message"`
+		}
+		reason := resp.Status
+		if json.Unmarshal(data, &failure) == nil {
+			if explained := failure.Error + failure.Description + failure.Message; explained != "" {
+				reason = explained
+			}
+		} else if text := strings.TrimSpace(string(data)); text != "" && len(text) < 200 {
+			// Slack answers in plain text, like invalid_payload
+			reason = text
+		}
+		return fmt.Errorf("%s: %s", t.describe(), reason)
+	}
+	if out != nil {
+		return json.Unmarshal(data, out)
+	}
+	return nil
+}
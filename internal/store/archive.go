@@ -0,0 +1,172 @@
+//go:build cgo
+// +build cgo
+
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	// the archive is a SQLite database, through go-sqlite3, which needs cgo;
+	// archive_nocgo.go stands in for it in builds without
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/mmcdole/gofeed"
+)
+
+// archiveSchema is created on opening the archive if it isn't there yet.
+// Items are kept whole, as JSON, alongside the columns worth querying the
+// database by directly.
+const archiveSchema = `
+CREATE TABLE IF NOT EXISTS feeds (
+	feed_url     TEXT PRIMARY KEY,
+	title        TEXT NOT NULL,
+	link         TEXT NOT NULL,
+	last_fetched INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS items (
+	feed_url      TEXT NOT NULL,
+	key           TEXT NOT NULL,
+	title         TEXT NOT NULL,
+	link          TEXT NOT NULL,
+	author        TEXT NOT NULL,
+	content       TEXT NOT NULL,
+	published     INTEGER,
+	first_fetched INTEGER NOT NULL,
+	last_fetched  INTEGER NOT NULL,
+	item          TEXT NOT NULL,
+	PRIMARY KEY (feed_url, key)
+);
+CREATE INDEX IF NOT EXISTS items_by_date ON items (feed_url, published);
+`
+
+// Archive keeps every item ever fetched, in a SQLite database, so feeds that
+// only carry their latest few items still have their history, and can be
+// read while offline.
+type Archive struct {
+	db *sql.DB
+}
+
+// OpenArchive opens the archive at path, creating it if need be.
+func OpenArchive(path string) (*Archive, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+	// feeds are fetched concurrently, and SQLite only takes one writer
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(archiveSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Archive{db: db}, nil
+}
+
+// Save adds a fetch of a feed to the archive. Items already in it are
+// updated to their latest copy, keeping when they were first fetched.
+func (a *Archive) Save(feedUrl string, feed *gofeed.Feed, fetched time.Time) error {
+	tx, err := a.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		INSERT INTO feeds (feed_url, title, link, last_fetched) VALUES (?, ?, ?, ?)
+		ON CONFLICT (feed_url) DO UPDATE SET
+			title = excluded.title, link = excluded.link, last_fetched = excluded.last_fetched`,
+		feedUrl, feed.Title, feed.Link, fetched.Unix(),
+	); err != nil {
+		return err
+	}
+	insert, err := tx.Prepare(`
+		INSERT INTO items (feed_url, key, title, link, author, content, published,
+			first_fetched, last_fetched, item)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (feed_url, key) DO UPDATE SET
+			title = excluded.title, link = excluded.link, author = excluded.author,
+			content = excluded.content, published = excluded.published,
+			last_fetched = excluded.last_fetched, item = excluded.item`)
+	if err != nil {
+		return err
+	}
+	defer insert.Close()
+	for _, item := range feed.Items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		var published interface{}
+		if item.PublishedParsed != nil {
+			published = item.PublishedParsed.Unix()
+		}
+		author := ""
+		if item.Author != nil {
+			author = item.Author.Name
+		}
+		content := item.Content
+		if content == "" {
+			content = item.Description
+		}
+		if _, err := insert.Exec(
+			feedUrl, ItemKey(item), item.Title, item.Link, author, content, published,
+			fetched.Unix(), fetched.Unix(), string(data),
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Items are the archived items of a feed, newest first by when they were
+// published (or else first fetched). limit caps how many, 0 for all of them.
+func (a *Archive) Items(feedUrl string, limit int) ([]*gofeed.Item, error) {
+	if limit <= 0 {
+		limit = -1
+	}
+	rows, err := a.db.Query(`
+		SELECT item FROM items WHERE feed_url = ?
+		ORDER BY COALESCE(published, first_fetched) DESC LIMIT ?`,
+		feedUrl, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*gofeed.Item
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var item gofeed.Item
+		if err := json.Unmarshal([]byte(data), &item); err != nil {
+			return nil, err
+		}
+		items = append(items, &item)
+	}
+	return items, rows.Err()
+}
+
+// Feed is a feed as the archive has it, for when it can't be fetched: its
+// title and link from the last fetch, and its archived items.
+func (a *Archive) Feed(feedUrl string, limit int) (*gofeed.Feed, error) {
+	feed := &gofeed.Feed{FeedLink: feedUrl}
+	err := a.db.QueryRow(
+		`SELECT title, link FROM feeds WHERE feed_url = ?`, feedUrl,
+	).Scan(&feed.Title, &feed.Link)
+	if err != nil {
+		return nil, err
+	}
+	feed.Items, err = a.Items(feedUrl, limit)
+	if err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+// Close closes the database.
+func (a *Archive) Close() error {
+	return a.db.Close()
+}
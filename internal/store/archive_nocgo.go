@@ -0,0 +1,40 @@
+//go:build !cgo
+// +build !cgo
+
+package store
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// errNoArchive is what every use of the archive fails with in a build
+// without cgo, which the SQLite driver needs.
+var errNoArchive = errors.New("archive: this build has no SQLite support, build it with cgo (CGO_ENABLED=1) to keep an archive")
+
+// Archive would keep every item ever fetched, but this build can't open
+// SQLite databases.
+type Archive struct{}
+
+// OpenArchive fails: there's no SQLite driver without cgo.
+func OpenArchive(path string) (*Archive, error) {
+	return nil, errNoArchive
+}
+
+func (a *Archive) Save(feedUrl string, feed *gofeed.Feed, fetched time.Time) error {
+	return errNoArchive
+}
+
+func (a *Archive) Items(feedUrl string, limit int) ([]*gofeed.Item, error) {
+	return nil, errNoArchive
+}
+
+func (a *Archive) Feed(feedUrl string, limit int) (*gofeed.Feed, error) {
+	return nil, errNoArchive
+}
+
+func (a *Archive) Close() error {
+	return nil
+}
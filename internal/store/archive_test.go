@@ -0,0 +1,68 @@
+//go:build cgo
+// +build cgo
+
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestArchive(t *testing.T) {
+	archive, err := OpenArchive(filepath.Join(t.TempDir(), "archive.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer archive.Close()
+
+	day := func(d int) *time.Time {
+		date := time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+	first := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	if err := archive.Save("http://x/feed", &gofeed.Feed{Title: "Old title", Link: "http://x/", Items: []*gofeed.Item{
+		{GUID: "1", Title: "One", PublishedParsed: day(1)},
+		{GUID: "2", Title: "Two", PublishedParsed: day(2)},
+	}}, first); err != nil {
+		t.Fatal(err)
+	}
+	// a later fetch that has dropped the oldest item and edited another
+	if err := archive.Save("http://x/feed", &gofeed.Feed{Title: "New title", Link: "http://x/", Items: []*gofeed.Item{
+		{GUID: "2", Title: "Two, edited", PublishedParsed: day(2)},
+		{GUID: "3", Title: "Three", PublishedParsed: day(3)},
+	}}, first.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	feed, err := archive.Feed("http://x/feed", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if feed.Title != "New title" {
+		t.Errorf("title %q, want the latest", feed.Title)
+	}
+	var titles []string
+	for _, item := range feed.Items {
+		titles = append(titles, item.Title)
+	}
+	want := []string{"Three", "Two, edited", "One"}
+	if len(titles) != len(want) {
+		t.Fatalf("items %q, want %q", titles, want)
+	}
+	for i := range want {
+		if titles[i] != want[i] {
+			t.Errorf("items %q, want %q", titles, want)
+			break
+		}
+	}
+
+	if items, err := archive.Items("http://x/feed", 2); err != nil || len(items) != 2 {
+		t.Errorf("Items with a limit of 2 gave %d items (%v)", len(items), err)
+	}
+	if _, err := archive.Feed("http://other/feed", 0); err == nil {
+		t.Error("a feed that was never archived was found")
+	}
+}
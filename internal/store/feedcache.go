@@ -0,0 +1,83 @@
+package store
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// FeedCache keeps the last successful fetch of each feed, so it can be read
+// back without going to the network.
+type FeedCache struct {
+	dir string
+}
+
+// NewFeedCache keeps cached feeds in a "feeds" directory under dir.
+func NewFeedCache(dir string) (*FeedCache, error) {
+	dir = filepath.Join(dir, "feeds")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &FeedCache{dir: dir}, nil
+}
+
+func (c *FeedCache) path(feedUrl string) string {
+	return filepath.Join(c.dir, fmt.Sprintf("%x.json", sha1.Sum([]byte(feedUrl))))
+}
+
+// Save stores a parsed feed.
+func (c *FeedCache) Save(feedUrl string, feed *gofeed.Feed) error {
+	data, err := json.Marshal(feed)
+	if err != nil {
+		return err
+	}
+	return writeFile(c.path(feedUrl), data)
+}
+
+// Validators are the headers a feed was last served with that let the next
+// fetch ask for it only if it has changed since.
+type Validators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+func (c *FeedCache) validatorsPath(feedUrl string) string {
+	return filepath.Join(c.dir, fmt.Sprintf("%x.http.json", sha1.Sum([]byte(feedUrl))))
+}
+
+// SaveValidators stores the validators of the copy of a feed last saved.
+func (c *FeedCache) SaveValidators(feedUrl string, validators Validators) error {
+	data, err := json.Marshal(validators)
+	if err != nil {
+		return err
+	}
+	return writeFile(c.validatorsPath(feedUrl), data)
+}
+
+// Validators reads back the validators of a cached feed. They're empty if
+// there aren't any, or they can't be read.
+func (c *FeedCache) Validators(feedUrl string) Validators {
+	var validators Validators
+	data, err := os.ReadFile(c.validatorsPath(feedUrl))
+	if err == nil {
+		json.Unmarshal(data, &validators)
+	}
+	return validators
+}
+
+// Load reads back the last successful fetch of a feed.
+func (c *FeedCache) Load(feedUrl string) (*gofeed.Feed, error) {
+	data, err := os.ReadFile(c.path(feedUrl))
+	if err != nil {
+		return nil, err
+	}
+	var feed gofeed.Feed
+	if err := json.Unmarshal(data, &feed); err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
@@ -0,0 +1,80 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// Progress remembers how far through long items the reader got, by ItemKey,
+// so they can be picked up again later. A nil *Progress remembers nothing.
+type Progress struct {
+	path    string
+	percent map[string]float64
+	dirty   bool
+}
+
+// LoadProgress reads the progress kept at path. A missing file just means
+// nothing has been left half read.
+func LoadProgress(path string) (*Progress, error) {
+	progress := &Progress{
+		path:    path,
+		percent: make(map[string]float64),
+	}
+
+	data, err := os.ReadFile(progress.path)
+	if os.IsNotExist(err) {
+		return progress, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &progress.percent); err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+// Get returns how far through an item the reader got, from 0 to 1.
+func (p *Progress) Get(item *gofeed.Item) float64 {
+	if p == nil {
+		return 0
+	}
+	return p.percent[ItemKey(item)]
+}
+
+// Set records how far through an item the reader is. Items that are
+// finished, or haven't been started, are forgotten.
+func (p *Progress) Set(item *gofeed.Item, percent float64) {
+	if p == nil {
+		return
+	}
+	key := ItemKey(item)
+	if percent <= 0 || percent >= 1 {
+		if _, ok := p.percent[key]; ok {
+			delete(p.percent, key)
+			p.dirty = true
+		}
+		return
+	}
+	if p.percent[key] != percent {
+		p.percent[key] = percent
+		p.dirty = true
+	}
+}
+
+// Save writes the progress out, if it has changed since it was last saved.
+func (p *Progress) Save() error {
+	if p == nil || !p.dirty {
+		return nil
+	}
+	data, err := json.Marshal(p.percent)
+	if err != nil {
+		return err
+	}
+	if err := writeFile(p.path, data); err != nil {
+		return err
+	}
+	p.dirty = false
+	return nil
+}
@@ -0,0 +1,93 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// ReadState remembers which items have been read, by ItemKey, across
+// sessions.
+type ReadState struct {
+	path string
+	read map[string]bool
+}
+
+// LoadReadState reads the read state kept at path. A missing file just means
+// nothing has been read yet.
+func LoadReadState(path string) (*ReadState, error) {
+	state := &ReadState{
+		path: path,
+		read: make(map[string]bool),
+	}
+
+	data, err := os.ReadFile(state.path)
+	if os.IsNotExist(err) {
+		return state, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var keys []string
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		state.read[key] = true
+	}
+	return state, nil
+}
+
+func (s *ReadState) IsRead(item *gofeed.Item) bool {
+	return s.read[ItemKey(item)]
+}
+
+// IsKeyRead is IsRead for an item known only by its ItemKey.
+func (s *ReadState) IsKeyRead(key string) bool {
+	return s.read[key]
+}
+
+// MarkRead records an item as read, reporting whether that changed anything.
+func (s *ReadState) MarkRead(item *gofeed.Item) bool {
+	key := ItemKey(item)
+	if s.read[key] {
+		return false
+	}
+	s.read[key] = true
+	return true
+}
+
+// MarkUnread forgets an item was read, reporting whether it had been.
+func (s *ReadState) MarkUnread(item *gofeed.Item) bool {
+	key := ItemKey(item)
+	if !s.read[key] {
+		return false
+	}
+	delete(s.read, key)
+	return true
+}
+
+func (s *ReadState) Save() error {
+	keys := make([]string, 0, len(s.read))
+	for key := range s.read {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	data, err := json.Marshal(keys)
+	if err != nil {
+		return err
+	}
+	return writeFile(s.path, data)
+}
+
+// UnreadCount counts the items in a feed that haven't been read.
+func (s *ReadState) UnreadCount(feed gofeed.Feed) int {
+	unread := 0
+	for _, item := range feed.Items {
+		if !s.IsRead(item) {
+			unread++
+		}
+	}
+	return unread
+}
@@ -0,0 +1,81 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// StarredItem is a starred item, kept whole so it outlives its feed.
+type StarredItem struct {
+	FeedUrl string       `json:"feedUrl"`
+	Starred time.Time    `json:"starred"`
+	Item    *gofeed.Item `json:"item"`
+}
+
+// Stars remembers starred items across sessions.
+type Stars struct {
+	path    string
+	starred []StarredItem
+}
+
+// LoadStars reads the stars kept at path. A missing file just means nothing
+// has been starred yet.
+func LoadStars(path string) (*Stars, error) {
+	stars := &Stars{path: path}
+
+	data, err := os.ReadFile(stars.path)
+	if os.IsNotExist(err) {
+		return stars, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &stars.starred); err != nil {
+		return nil, err
+	}
+	return stars, nil
+}
+
+func (s *Stars) find(item *gofeed.Item) int {
+	key := ItemKey(item)
+	for i, starred := range s.starred {
+		if ItemKey(starred.Item) == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Stars) IsStarred(item *gofeed.Item) bool {
+	return s.find(item) >= 0
+}
+
+// Toggle stars an item from a feed, or unstars it if it already was,
+// reporting whether it's now starred.
+func (s *Stars) Toggle(feedUrl string, item *gofeed.Item) bool {
+	if i := s.find(item); i >= 0 {
+		s.starred = append(s.starred[:i], s.starred[i+1:]...)
+		return false
+	}
+	s.starred = append(s.starred, StarredItem{FeedUrl: feedUrl, Starred: time.Now(), Item: item})
+	return true
+}
+
+// Items lists the starred items, most recently starred first.
+func (s *Stars) Items() []StarredItem {
+	items := make([]StarredItem, 0, len(s.starred))
+	for i := len(s.starred) - 1; i >= 0; i-- {
+		items = append(items, s.starred[i])
+	}
+	return items
+}
+
+func (s *Stars) Save() error {
+	data, err := json.Marshal(s.starred)
+	if err != nil {
+		return err
+	}
+	return writeFile(s.path, data)
+}
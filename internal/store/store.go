@@ -0,0 +1,30 @@
+// Package store keeps what the reader remembers between runs: which items
+// have been read or starred, and the last copy of every feed.
+package store
+
+import (
+	"os"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// ItemKey identifies an item across fetches. Not every feed sets a GUID, so
+// fall back to the link and then the title.
+func ItemKey(item *gofeed.Item) string {
+	if item.GUID != "" {
+		return item.GUID
+	}
+	if item.Link != "" {
+		return item.Link
+	}
+	return item.Title
+}
+
+// writeFile writes then renames, so a reader never sees half a file.
+func writeFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,64 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestItemKey(t *testing.T) {
+	tests := []struct {
+		name string
+		item gofeed.Item
+		want string
+	}{
+		{"guid", gofeed.Item{GUID: "g", Link: "l", Title: "t"}, "g"},
+		{"link", gofeed.Item{Link: "l", Title: "t"}, "l"},
+		{"title", gofeed.Item{Title: "t"}, "t"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := ItemKey(&test.item); got != test.want {
+				t.Errorf("ItemKey() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestReadState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "read.json")
+	state, err := LoadReadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	read := &gofeed.Item{GUID: "read"}
+	unread := &gofeed.Item{GUID: "unread"}
+	if !state.MarkRead(read) {
+		t.Error("MarkRead of a new item reported no change")
+	}
+	if state.MarkRead(read) {
+		t.Error("MarkRead of a read item reported a change")
+	}
+	if state.MarkUnread(unread) {
+		t.Error("MarkUnread of an unread item reported a change")
+	}
+	if err := state.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err = LoadReadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !state.IsRead(read) || state.IsRead(unread) {
+		t.Errorf("read state didn't survive saving: read=%v unread=%v", state.IsRead(read), state.IsRead(unread))
+	}
+	if got := state.UnreadCount(gofeed.Feed{Items: []*gofeed.Item{read, unread}}); got != 1 {
+		t.Errorf("UnreadCount() = %d, want 1", got)
+	}
+	if !state.MarkUnread(read) || state.IsRead(read) {
+		t.Error("MarkUnread didn't forget a read item")
+	}
+}
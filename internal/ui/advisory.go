@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/homielabs/golang-rss-client/internal/fetch"
+	"github.com/mmcdole/gofeed"
+)
+
+// severityColors are the ansi colors the titles of security advisories are
+// drawn in, by severity.
+var severityColors = map[string]string{
+	"critical": "9",
+	"high":     "1",
+	"medium":   "3",
+	"low":      "4",
+}
+
+// severe reports whether an item is a security advisory rated high or
+// critical.
+func severe(item *gofeed.Item) bool {
+	return fetch.SeverityRank(item.Custom[fetch.SeverityKey]) >= fetch.SeverityRank("high")
+}
+
+// severityExtra is how an advisory's severity is put among an item's
+// extras: with its CVSS score, unless it's brief.
+func (m model) severityExtra(item *gofeed.Item, brief bool) string {
+	severity := item.Custom[fetch.SeverityKey]
+	if severity == "" {
+		return ""
+	}
+	extra := m.symbol("⚠ ", "severity: ") + severity
+	if score := item.Custom[fetch.CVSSKey]; score != "" && !brief {
+		extra += " (CVSS " + score + ")"
+	}
+	return extra
+}
+
+// toggleSevereOnly switches between showing every article and only the
+// advisories rated high or critical.
+func (m *model) toggleSevereOnly() tea.Cmd {
+	m.severeOnly = !m.severeOnly
+	if m.severeOnly {
+		return m.setStatus("Showing high and critical advisories only")
+	}
+	return m.setStatus("Showing all articles")
+}
+
+// severeArticles leaves all but the high and critical advisories out of an
+// article list's items.
+func (m model) severeArticles(items []list.Item) []list.Item {
+	var kept []list.Item
+	for _, item := range items {
+		if article, ok := item.(articleItem); ok && severe(m.feedSlice[article.feed].Items[article.index]) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
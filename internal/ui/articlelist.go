@@ -0,0 +1,277 @@
+package ui
+
+import (
+	"io"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/homielabs/golang-rss-client/internal/fetch"
+	"github.com/homielabs/golang-rss-client/internal/store"
+)
+
+// articleListKind says which articles the article list is showing.
+type articleListKind int
+
+const (
+	// the items of the current feed
+	articleListFeed articleListKind = iota
+	// the best unread items across every feed
+	articleListTop
+	// the items across every feed matching the last search
+	articleListSearch
+	// the items across every feed by one author
+	articleListAuthor
+	// the items across every feed in one language
+	articleListLanguage
+)
+
+// itemAge buckets items by how old they are, so stale ones stand out.
+type itemAge int
+
+const (
+	ageUnknown itemAge = iota
+	ageToday
+	ageThisWeek
+	ageOlder
+)
+
+// ageColors are the ansi colors the date lines of unread items are drawn in.
+var ageColors = map[itemAge]string{
+	ageToday:    "2",
+	ageThisWeek: "3",
+	ageOlder:    "8",
+}
+
+var ageLabels = map[itemAge]string{
+	ageToday:    "today",
+	ageThisWeek: "this week",
+	ageOlder:    "older",
+}
+
+// ageOf buckets a date by the calendar in the display time zone.
+func (m model) ageOf(date *time.Time, now time.Time) itemAge {
+	if date == nil {
+		return ageUnknown
+	}
+	t := date.In(m.timezone)
+	now = now.In(m.timezone)
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, m.timezone)
+	switch {
+	case !t.Before(midnight):
+		return ageToday
+	case !t.Before(midnight.AddDate(0, 0, -6)):
+		return ageThisWeek
+	default:
+		return ageOlder
+	}
+}
+
+// articleItem is an item as it's listed in the article list.
+type articleItem struct {
+	feed   int
+	index  int
+	title  string
+	detail string
+	read   bool
+	age    itemAge
+	// severity is the advisory's, if the item is one
+	severity string
+}
+
+func (i articleItem) Title() string       { return i.title }
+func (i articleItem) Description() string { return i.detail }
+func (i articleItem) FilterValue() string { return i.title }
+
+// articleDelegate draws read items dimmed, unless they're selected, and
+// colors the date lines of unread ones by age and the titles of unread
+// advisories by severity.
+type articleDelegate struct {
+	list.DefaultDelegate
+	colored bool
+}
+
+func (d articleDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	article, ok := item.(articleItem)
+	if !ok {
+		return
+	}
+	styled := d.DefaultDelegate
+	if article.read {
+		styled.Styles.NormalTitle = styled.Styles.DimmedTitle
+		styled.Styles.NormalDesc = styled.Styles.DimmedDesc
+	} else if d.colored {
+		if color, ok := ageColors[article.age]; ok {
+			styled.Styles.NormalDesc = styled.Styles.NormalDesc.Copy().Foreground(lipgloss.Color(color))
+			styled.Styles.SelectedDesc = styled.Styles.SelectedDesc.Copy().Foreground(lipgloss.Color(color))
+		}
+		if color, ok := severityColors[article.severity]; ok {
+			styled.Styles.NormalTitle = styled.Styles.NormalTitle.Copy().Foreground(lipgloss.Color(color))
+		}
+	}
+	styled.Render(w, m, index, item)
+}
+
+// newArticleItem lists an item with its read (or new) marker, publish date
+// and age, after whatever context comes first in its detail line.
+func (m model) newArticleItem(feedIndex int, itemIndex int, context string) articleItem {
+	item := m.feedSlice[feedIndex].Items[itemIndex]
+	read := m.readState.IsRead(item)
+	marker := m.symbol("● ", "* ")
+	state := "unread"
+	if read {
+		marker = "  "
+		state = "read"
+	} else if m.isFresh(feedIndex, store.ItemKey(item)) {
+		marker = m.symbol("✦ ", "+ ")
+		state = "new"
+	}
+	separator := " · "
+	if m.asciiOnly || m.accessible {
+		separator = ", "
+	}
+
+	age := m.ageOf(item.PublishedParsed, time.Now())
+	detail := state
+	if published := item.PublishedParsed; published != nil {
+		detail = published.In(m.timezone).Format("2006-01-02 15:04") + separator +
+			ageLabels[age] + separator + state
+	}
+	if context != "" {
+		detail = context + separator + detail
+	}
+	for _, extra := range m.itemExtras(item, false) {
+		detail += separator + extra
+	}
+	return articleItem{
+		feed:     feedIndex,
+		index:    itemIndex,
+		title:    marker + item.Title,
+		detail:   "  " + detail,
+		read:     read,
+		age:      age,
+		severity: item.Custom[fetch.SeverityKey],
+	}
+}
+
+// openArticleList shows a kind of article list, starting on the item being
+// read if it's in there.
+func (m *model) openArticleList(kind articleListKind) {
+	var title string
+	var items []list.Item
+	switch kind {
+	case articleListFeed:
+		title = m.feedSlice[m.feedSliceIndex].Title
+		for i := range m.feedSlice[m.feedSliceIndex].Items {
+			items = append(items, m.newArticleItem(m.feedSliceIndex, i, ""))
+		}
+	case articleListTop:
+		title = "Top stories"
+		items = m.topStories()
+	case articleListSearch:
+		title = "Search: " + m.search.query
+		// it was checked before the search was made
+		matches, _ := searchMatcher(m.search.query)
+		items = m.searchResults(matches)
+	case articleListAuthor:
+		title = "By " + m.articleListAuthor
+		items = m.authorResults(m.articleListAuthor)
+	case articleListLanguage:
+		title = "In " + fetch.LanguageName(m.articleListLanguage)
+		items = m.languageResults(m.articleListLanguage)
+	}
+	if m.unreadOnly {
+		items = unreadArticles(items)
+		title += " (unread)"
+	}
+	if m.severeOnly {
+		items = m.severeArticles(items)
+		title += " (high and critical)"
+	}
+
+	delegate := list.NewDefaultDelegate()
+	if m.accessible {
+		// mark the selection with text rather than color alone
+		marker := lipgloss.NewStyle().
+			Border(lipgloss.Border{Left: ">"}, false, false, false, true).
+			PaddingLeft(1)
+		plain := lipgloss.NewStyle().PaddingLeft(2)
+		delegate.Styles = list.DefaultItemStyles{
+			NormalTitle:   plain,
+			NormalDesc:    plain,
+			SelectedTitle: marker,
+			SelectedDesc:  marker,
+			DimmedTitle:   plain,
+			DimmedDesc:    plain,
+		}
+	} else {
+		delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.
+			Foreground(lipgloss.Color(m.feedAccent())).
+			BorderForeground(lipgloss.Color(m.feedAccent()))
+		delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.
+			BorderForeground(lipgloss.Color(m.feedAccent()))
+	}
+
+	m.articleList = list.NewModel(items, articleDelegate{DefaultDelegate: delegate, colored: !m.accessible}, m.viewport.Width, m.viewport.Height)
+	m.articleList.Title = title
+	if m.accessible {
+		m.articleList.Styles.Title = lipgloss.NewStyle()
+	} else {
+		m.articleList.Styles.Title = m.articleList.Styles.Title.
+			Background(lipgloss.Color(m.feedAccent())).
+			Foreground(lipgloss.Color(m.textColor))
+	}
+	// quitting (and esc) are handled here rather than by the list
+	m.articleList.DisableQuitKeybindings()
+	for i, item := range items {
+		if article := item.(articleItem); article.feed == m.feedSliceIndex && article.index == m.feedIndex {
+			m.articleList.Select(i)
+			break
+		}
+	}
+	m.articleListKind = kind
+	m.articleListMode = true
+}
+
+func (m model) updateArticleList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// while a filter is being typed every key belongs to it
+	if m.articleList.FilterState() != list.Filtering {
+		switch {
+		case key.Matches(msg, defaultKeyMap.Open):
+			if item, ok := m.articleList.SelectedItem().(articleItem); ok {
+				m.feedSliceIndex = item.feed
+				m.feedIndex = item.index
+				m.articleListMode = false
+				m.fromArticleList = true
+				return m.Update(rerenderMsg{})
+			}
+			return m, nil
+		case key.Matches(msg, defaultKeyMap.Back) && m.articleList.FilterState() != list.FilterApplied,
+			key.Matches(msg, defaultKeyMap.ArticleList),
+			key.Matches(msg, defaultKeyMap.Top):
+			m.articleListMode = false
+			m.fromArticleList = false
+			return m, nil
+		case key.Matches(msg, defaultKeyMap.Follow) && m.articleListKind == articleListAuthor:
+			cmd := m.toggleFollow(m.articleListAuthor)
+			return m, cmd
+		case key.Matches(msg, defaultKeyMap.UnreadOnly):
+			cmd := m.toggleUnreadOnly()
+			m.openArticleList(m.articleListKind)
+			return m, cmd
+		case key.Matches(msg, defaultKeyMap.SevereOnly):
+			cmd := m.toggleSevereOnly()
+			m.openArticleList(m.articleListKind)
+			return m, cmd
+		case key.Matches(msg, defaultKeyMap.Quit) && !key.Matches(msg, defaultKeyMap.Back):
+			// esc with a filter applied clears it, in the list below
+			return m, m.quit()
+		}
+	}
+
+	var cmd tea.Cmd
+	m.articleList, cmd = m.articleList.Update(msg)
+	return m, cmd
+}
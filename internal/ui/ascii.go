@@ -0,0 +1,33 @@
+package ui
+
+import "github.com/charmbracelet/lipgloss"
+
+// asciiBorder draws boxes for terminals (and fonts) without box-drawing
+// characters.
+var asciiBorder = lipgloss.Border{
+	Top:         "-",
+	Bottom:      "-",
+	Left:        "|",
+	Right:       "|",
+	TopLeft:     "+",
+	TopRight:    "+",
+	BottomLeft:  "+",
+	BottomRight: "+",
+}
+
+// border returns the border used for boxes, or the ASCII one if asked to
+// avoid unicode.
+func (m model) border(preferred lipgloss.Border) lipgloss.Border {
+	if m.asciiOnly {
+		return asciiBorder
+	}
+	return preferred
+}
+
+// symbol returns the unicode decoration, or its ASCII stand-in.
+func (m model) symbol(unicode string, ascii string) string {
+	if m.asciiOnly {
+		return ascii
+	}
+	return unicode
+}
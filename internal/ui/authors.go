@@ -0,0 +1,207 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/homielabs/golang-rss-client/internal/config"
+	"github.com/homielabs/golang-rss-client/internal/store"
+	"github.com/mmcdole/gofeed"
+	"github.com/spf13/viper"
+)
+
+// authorFeedPrefix starts the URL standing in for a followed author's feed,
+// made up of their articles in every other feed rather than fetched. The
+// author's name follows it.
+const authorFeedPrefix = "author:"
+
+// isAuthorFeed reports whether a feed is a followed author's.
+func (m model) isAuthorFeed(index int) bool {
+	return strings.HasPrefix(m.feedUrls[index], authorFeedPrefix)
+}
+
+// isVirtualFeed reports whether a feed is made up of other feeds' items, like
+// the starred one and followed authors', so there's nothing to fetch.
+func (m model) isVirtualFeed(index int) bool {
+	return m.isStarredFeed(index) || m.isAuthorFeed(index)
+}
+
+// sameAuthor reports whether two author names are the same person's,
+// ignoring case and spacing.
+func sameAuthor(a, b string) bool {
+	return strings.EqualFold(strings.Join(strings.Fields(a), " "), strings.Join(strings.Fields(b), " "))
+}
+
+// itemAuthors are the names of an item's authors.
+func itemAuthors(item *gofeed.Item) []string {
+	var names []string
+	for _, author := range item.Authors {
+		if name := strings.Join(strings.Fields(author.Name), " "); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// byAuthor reports whether someone's among an item's authors.
+func byAuthor(item *gofeed.Item, name string) bool {
+	for _, author := range itemAuthors(item) {
+		if sameAuthor(author, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// articleRef is an item by the feed it's in and where.
+type articleRef struct {
+	feed  int
+	index int
+}
+
+// authorArticles are every loaded item by an author, across the feeds that
+// are fetched, newest first. An item in two feeds is there once.
+func (m model) authorArticles(name string) []articleRef {
+	var refs []articleRef
+	seen := make(map[string]bool)
+	for i, feed := range m.feedSlice {
+		if m.isVirtualFeed(i) {
+			continue
+		}
+		for j, item := range feed.Items {
+			if key := store.ItemKey(item); byAuthor(item, name) && !seen[key] {
+				seen[key] = true
+				refs = append(refs, articleRef{i, j})
+			}
+		}
+	}
+	sort.SliceStable(refs, func(a, b int) bool {
+		first := m.feedSlice[refs[a].feed].Items[refs[a].index].PublishedParsed
+		second := m.feedSlice[refs[b].feed].Items[refs[b].index].PublishedParsed
+		return first != nil && (second == nil || first.After(*second))
+	})
+	return refs
+}
+
+// authorResults lists an author's articles, each with the feed it's from.
+func (m model) authorResults(name string) []list.Item {
+	var items []list.Item
+	for _, ref := range m.authorArticles(name) {
+		feedTitle := m.feedSlice[ref.feed].Title
+		if feedTitle == "" {
+			feedTitle = m.feedUrls[ref.feed]
+		}
+		items = append(items, m.newArticleItem(ref.feed, ref.index, feedTitle))
+	}
+	return items
+}
+
+// authorFeed builds a followed author's feed of their articles.
+func (m model) authorFeed(name string) gofeed.Feed {
+	feed := gofeed.Feed{Title: name, Description: "Articles by " + name}
+	for _, ref := range m.authorArticles(name) {
+		feed.Items = append(feed.Items, m.feedSlice[ref.feed].Items[ref.index])
+	}
+	return feed
+}
+
+// updateAuthorFeeds gathers followed authors' articles again, once a feed
+// has changed, staying on the article being read if it's in one.
+func (m *model) updateAuthorFeeds() {
+	for i, feedUrl := range m.feedUrls {
+		if !m.isAuthorFeed(i) {
+			continue
+		}
+		previous := m.feedSlice[i]
+		m.feedSlice[i] = m.authorFeed(strings.TrimPrefix(feedUrl, authorFeedPrefix))
+		if i != m.feedSliceIndex || m.feedIndex >= previous.Len() {
+			continue
+		}
+		reading := store.ItemKey(previous.Items[m.feedIndex])
+		m.feedIndex = 0
+		for j, item := range m.feedSlice[i].Items {
+			if store.ItemKey(item) == reading {
+				m.feedIndex = j
+				break
+			}
+		}
+	}
+}
+
+// currentAuthors are the authors of the article being read.
+func (m model) currentAuthors() []string {
+	feed := m.feedSlice[m.feedSliceIndex]
+	if m.feedIndex >= feed.Len() {
+		return nil
+	}
+	return itemAuthors(feed.Items[m.feedIndex])
+}
+
+// chooseAuthor does something with the author of the article being read,
+// asking which one first if there are a few.
+func (m *model) chooseAuthor(kind pickerKind, verb string) tea.Cmd {
+	authors := m.currentAuthors()
+	switch {
+	case len(authors) == 0:
+		return m.setStatus("This article doesn't say who wrote it")
+	case len(authors) == 1:
+		return m.authorChosen(kind, authors[0])
+	}
+	m.picker = newPicker(kind, verb+" which author?", authors)
+	return nil
+}
+
+// authorChosen lists the author's articles, or follows them, depending on
+// what they were chosen for.
+func (m *model) authorChosen(kind pickerKind, name string) tea.Cmd {
+	if kind == pickerFollow {
+		return m.toggleFollow(name)
+	}
+	m.articleListAuthor = name
+	m.openArticleList(articleListAuthor)
+	return nil
+}
+
+// followedIndex is the index of an author's feed, or -1 if they're not
+// followed.
+func (m model) followedIndex(name string) int {
+	for i, feedUrl := range m.feedUrls {
+		if m.isAuthorFeed(i) && sameAuthor(strings.TrimPrefix(feedUrl, authorFeedPrefix), name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// toggleFollow follows an author, adding a feed of their articles, or stops
+// following them. Who's followed is saved to the config.
+func (m *model) toggleFollow(name string) tea.Cmd {
+	status := "Following " + name
+	var cmds []tea.Cmd
+	if index := m.followedIndex(name); index >= 0 {
+		if index == m.feedSliceIndex {
+			cmds = append(cmds, func() tea.Msg { return rerenderMsg{} })
+		}
+		m.dropFeed(index)
+		status = "Stopped following " + name
+	} else {
+		m.appendFeed(authorFeedPrefix+name, m.authorFeed(name))
+	}
+
+	var followed []string
+	for i, feedUrl := range m.feedUrls {
+		if m.isAuthorFeed(i) {
+			followed = append(followed, strings.TrimPrefix(feedUrl, authorFeedPrefix))
+		}
+	}
+	viper.Set("followedAuthors", followed)
+	if err := config.Save(); err != nil {
+		log.Println(err)
+		status = fmt.Sprintf("%s, but couldn't save the config: %s", status, err)
+	}
+	return tea.Batch(append(cmds, m.setStatus(status))...)
+}
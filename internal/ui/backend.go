@@ -0,0 +1,208 @@
+package ui
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/homielabs/golang-rss-client/internal/backend"
+	"github.com/homielabs/golang-rss-client/internal/store"
+	"github.com/mmcdole/gofeed"
+)
+
+// backendRetryAfter is how long to wait before sending changes to a backend
+// again after it couldn't take them.
+const backendRetryAfter = time.Minute
+
+// tracked is a backend that's sent the items read, unread, starred or
+// unstarred here, like Miniflux.
+type tracked struct {
+	backend.Tracker
+	// updating is set while changes are being sent, and retry is when to
+	// try again after they couldn't be
+	updating bool
+	retry    time.Time
+}
+
+// trackedBackends are the backends that are trackers.
+func trackedBackends(backends backend.Backends) []*tracked {
+	var trackers []*tracked
+	for _, b := range backends {
+		if tracker, ok := b.(backend.Tracker); ok {
+			trackers = append(trackers, &tracked{Tracker: tracker})
+		}
+	}
+	return trackers
+}
+
+// tracker is the tracked backend a feed's from, if it's one of them.
+func (m model) tracker(feedUrl string) *tracked {
+	for _, t := range m.tracked {
+		if t.Owns(feedUrl) {
+			return t
+		}
+	}
+	return nil
+}
+
+type backendUpdatedMsg struct {
+	backend *tracked
+	// read and starred are the items' states the backend now has, by
+	// ItemKey
+	read    map[string]bool
+	starred map[string]bool
+	err     error
+}
+
+// applyTrackedState marks the items of a feed just read from a tracked
+// backend read or starred here the way they are there. Items changed here
+// since the last read, which haven't been sent yet, stay as they are here.
+func (m model) applyTrackedState(t *tracked, feedUrl string, previous gofeed.Feed, fresh *gofeed.Feed) {
+	readKey, starredKey := t.StateKeys()
+	known := make(map[string]*gofeed.Item, len(previous.Items))
+	for _, item := range previous.Items {
+		known[store.ItemKey(item)] = item
+	}
+	readChanged, starsChanged := false, false
+	for _, item := range fresh.Items {
+		if _, ok := item.Custom[readKey]; !ok {
+			continue
+		}
+		before := known[store.ItemKey(item)]
+
+		readHere := m.readState.IsRead(item)
+		if before != nil && readHere != (before.Custom[readKey] == "true") {
+			// still to be sent
+			item.Custom[readKey] = before.Custom[readKey]
+		} else if readThere := item.Custom[readKey] == "true"; readThere != readHere {
+			if readThere {
+				m.readState.MarkRead(item)
+			} else {
+				m.readState.MarkUnread(item)
+			}
+			readChanged = true
+		}
+
+		starredHere := m.stars.IsStarred(item)
+		if before != nil && starredHere != (before.Custom[starredKey] == "true") {
+			item.Custom[starredKey] = before.Custom[starredKey]
+		} else if starredThere := item.Custom[starredKey] == "true"; starredThere != starredHere {
+			m.stars.Toggle(feedUrl, item)
+			starsChanged = true
+		}
+	}
+	if readChanged {
+		if err := m.readState.Save(); err != nil {
+			log.Println(err)
+		}
+	}
+	if starsChanged {
+		if err := m.stars.Save(); err != nil {
+			log.Println(err)
+		}
+		for i := range m.feedUrls {
+			if m.isStarredFeed(i) {
+				m.feedSlice[i] = m.starredFeed()
+			}
+		}
+	}
+}
+
+// updateBackends sends each tracked backend the items read, unread, starred
+// or unstarred here since they were last read from it, in the background.
+func (m *model) updateBackends() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, t := range m.tracked {
+		cmds = append(cmds, m.updateBackend(t))
+	}
+	return tea.Batch(cmds...)
+}
+
+// updateBackend sends a tracked backend the items changed here.
+func (m *model) updateBackend(t *tracked) tea.Cmd {
+	if t.updating || time.Now().Before(t.retry) {
+		return nil
+	}
+	readKey, starredKey := t.StateKeys()
+	// by whether they're now read or starred
+	readChanges := make(map[bool][]*gofeed.Item)
+	starChanges := make(map[bool][]*gofeed.Item)
+	readNow := make(map[string]bool)
+	starredNow := make(map[string]bool)
+	for i, feed := range m.feedSlice {
+		if !t.Owns(m.feedUrls[i]) {
+			continue
+		}
+		for _, item := range feed.Items {
+			if _, ok := item.Custom[readKey]; !ok {
+				continue
+			}
+			if readHere := m.readState.IsRead(item); readHere != (item.Custom[readKey] == "true") {
+				readChanges[readHere] = append(readChanges[readHere], item)
+				readNow[store.ItemKey(item)] = readHere
+			}
+			if starredHere := m.stars.IsStarred(item); starredHere != (item.Custom[starredKey] == "true") {
+				starChanges[starredHere] = append(starChanges[starredHere], item)
+				starredNow[store.ItemKey(item)] = starredHere
+			}
+		}
+	}
+	if len(readNow) == 0 && len(starredNow) == 0 {
+		return nil
+	}
+	t.updating = true
+	ctx := m.ctx
+	return func() tea.Msg {
+		err := sendChanges(ctx, t, readChanges, starChanges)
+		return backendUpdatedMsg{backend: t, read: readNow, starred: starredNow, err: err}
+	}
+}
+
+// sendChanges marks items read and unread, and stars and unstars them, with
+// a backend.
+func sendChanges(ctx context.Context, b backend.Backend, readChanges, starChanges map[bool][]*gofeed.Item) error {
+	for _, read := range []bool{true, false} {
+		if items := readChanges[read]; len(items) > 0 {
+			if err := b.MarkRead(ctx, items, read); err != nil {
+				return err
+			}
+		}
+	}
+	for _, starred := range []bool{true, false} {
+		if items := starChanges[starred]; len(items) > 0 {
+			if err := b.Star(ctx, items, starred); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// backendUpdated notes what a tracked backend now has, once it's been sent.
+func (m *model) backendUpdated(msg backendUpdatedMsg) tea.Cmd {
+	t := msg.backend
+	t.updating = false
+	if msg.err != nil {
+		log.Println(msg.err)
+		t.retry = time.Now().Add(backendRetryAfter)
+		return m.setStatus("Couldn't update " + t.Name() + ": " + msg.err.Error())
+	}
+	readKey, starredKey := t.StateKeys()
+	for i, feed := range m.feedSlice {
+		if !t.Owns(m.feedUrls[i]) {
+			continue
+		}
+		for _, item := range feed.Items {
+			key := store.ItemKey(item)
+			if read, ok := msg.read[key]; ok {
+				item.Custom[readKey] = strconv.FormatBool(read)
+			}
+			if starred, ok := msg.starred[key]; ok {
+				item.Custom[starredKey] = strconv.FormatBool(starred)
+			}
+		}
+	}
+	return nil
+}
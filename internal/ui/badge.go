@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"hash/fnv"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// badgeColors are ANSI colors that stay readable behind black text.
+var badgeColors = []string{
+	"39", "43", "76", "109", "141", "168", "173", "178", "203", "214",
+}
+
+// feedInitials are up to two letters standing in for a feed: the first
+// letters of its first two words, or the first two letters of a single word.
+func feedInitials(title string) string {
+	words := strings.FieldsFunc(title, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var initials []rune
+	switch {
+	case len(words) == 0:
+		return "?"
+	case len(words) == 1:
+		initials = []rune(words[0])
+		if len(initials) > 2 {
+			initials = initials[:2]
+		}
+	default:
+		initials = []rune{[]rune(words[0])[0], []rune(words[1])[0]}
+	}
+	return strings.ToUpper(string(initials))
+}
+
+// hashedBadgeColor picks a color for a feed from its URL, so it stays the
+// same from one run to the next.
+func hashedBadgeColor(feedUrl string) string {
+	h := fnv.New32a()
+	h.Write([]byte(feedUrl))
+	return badgeColors[h.Sum32()%uint32(len(badgeColors))]
+}
+
+// badgeColor is the feed's own accent color if it has one, or else one
+// picked from its URL.
+func (m model) badgeColor(index int) string {
+	if color, ok := m.feedColors[m.feedUrls[index]]; ok {
+		return color
+	}
+	return hashedBadgeColor(m.feedUrls[index])
+}
+
+// feedAccent is the accent color for the feed being read.
+func (m model) feedAccent() string {
+	if color, ok := m.feedColors[m.feedUrls[m.feedSliceIndex]]; ok {
+		return color
+	}
+	return m.accent
+}
+
+// feedBadge renders a feed's initials on its color. In accessible mode it is
+// left out altogether, since the title is right next to it anyway.
+func (m model) feedBadge(index int) string {
+	if m.accessible {
+		return ""
+	}
+	title := m.feedSlice[index].Title
+	if title == "" {
+		title = m.feedUrls[index]
+	}
+	return lipgloss.NewStyle().
+		Bold(true).
+		Background(lipgloss.Color(m.badgeColor(index))).
+		Foreground(lipgloss.Color("0")).
+		Width(4).
+		Align(lipgloss.Center).
+		Render(feedInitials(title)) + " "
+}
@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/homielabs/golang-rss-client/internal/capture"
+	"github.com/homielabs/golang-rss-client/internal/render"
+)
+
+type capturedMsg struct {
+	// where is what the article was captured to
+	where string
+	err   error
+}
+
+// captureCmd captures an article in the background.
+func captureCmd(ctx context.Context, c capture.Config, target string, article capture.Article) tea.Cmd {
+	return func() tea.Msg {
+		where, err := capture.Save(ctx, c, target, article)
+		return capturedMsg{where: where, err: err}
+	}
+}
+
+// captureArticle captures the article being read, straight away if there's
+// only the one target and otherwise once one's been picked.
+func (m *model) captureArticle() tea.Cmd {
+	if m.feedIndex >= m.feedSlice[m.feedSliceIndex].Len() {
+		return m.setStatus("Nothing to capture")
+	}
+	targets := m.capture.Targets()
+	switch len(targets) {
+	case 0:
+		return m.setStatus("No capture targets are configured")
+	case 1:
+		return m.captureTo(targets[0])
+	}
+	m.picker = newPicker(pickerCapture, "capture to where?", targets)
+	return nil
+}
+
+// captureTo captures the article being read to the chosen target.
+func (m *model) captureTo(target string) tea.Cmd {
+	feed := m.feedSlice[m.feedSliceIndex]
+	item := feed.Items[m.feedIndex]
+	article := capture.Article{
+		Title:     item.Title,
+		Link:      item.Link,
+		Feed:      feed.Title,
+		Published: item.PublishedParsed,
+	}
+	var authors []string
+	for _, author := range item.Authors {
+		authors = append(authors, author.Name)
+	}
+	article.Author = strings.Join(authors, ", ")
+	html, _ := m.articleHTML(m.feedSliceIndex, m.feedIndex)
+	if item.Link != "" {
+		html = render.ResolveLinks(html, item.Link)
+	}
+	markdown, err := m.converterFor(m.feedSliceIndex).ConvertString(html)
+	if err != nil {
+		log.Println(err)
+	}
+	article.Markdown = markdown
+	return captureCmd(m.ctx, m.capture, target, article)
+}
+
+// captured reports how capturing went.
+func (m *model) captured(msg capturedMsg) tea.Cmd {
+	if msg.err != nil {
+		log.Println(msg.err)
+		return m.setStatus("Couldn't capture: " + msg.err.Error())
+	}
+	return m.setStatus("Captured to " + msg.where)
+}
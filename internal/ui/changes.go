@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"html"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/homielabs/golang-rss-client/internal/fetch"
+	"github.com/homielabs/golang-rss-client/internal/render"
+	"github.com/homielabs/golang-rss-client/internal/store"
+	"github.com/mmcdole/gofeed"
+)
+
+// itemHTML is the HTML of a version of an item, as it's rendered.
+func itemHTML(title, description, content string, display render.Display) string {
+	if display.DescriptionOnly {
+		content = ""
+	}
+	return "<h1>" + html.EscapeString(title) + "</h1>" + description + "<hr>" + content
+}
+
+// changesHTML is the HTML of what changed in an item since its previous
+// version, if the feed re-published it with changes.
+func (m model) changesHTML(item *gofeed.Item, display render.Display) (string, bool) {
+	previous, ok := fetch.PreviousRevision(item)
+	if !ok {
+		return "", false
+	}
+	legend := "<p><em>Changes since the version of " +
+		html.EscapeString(previous.Updated.In(m.timezone).Format("2006-01-02 15:04")) +
+		": struck out was taken out, bold was put in.</em></p>"
+	return legend + render.Diff(
+		itemHTML(previous.Title, previous.Description, previous.Content, display),
+		itemHTML(item.Title, item.Description, item.Content, display),
+	), true
+}
+
+// toggleChanges swaps the item being read for what's changed in it since
+// the feed re-published it, or back again.
+func (m *model) toggleChanges() tea.Cmd {
+	feed := m.feedSlice[m.feedSliceIndex]
+	if m.feedIndex >= feed.Len() {
+		return m.setStatus("Nothing to compare")
+	}
+	item := feed.Items[m.feedIndex]
+	key := store.ItemKey(item)
+	status := "Showing what changed"
+	if m.showingChanges[key] {
+		delete(m.showingChanges, key)
+		status = "Back to the article"
+	} else if _, ok := fetch.PreviousRevision(item); !ok {
+		return m.setStatus("This article hasn't been edited since it was first fetched")
+	} else {
+		m.showingChanges[key] = true
+	}
+	m.forgetPrerendered(key)
+	return tea.Batch(
+		func() tea.Msg { return rerenderMsg{scroll: true} },
+		m.setStatus(status),
+	)
+}
@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"log"
+	"time"
+)
+
+// expireOld marks the items of a feed read once they're older than the
+// feed's expiry, for feeds that post more than could ever be caught up on.
+func (m model) expireOld(index int) {
+	expiry, ok := m.expireAfter[m.feedUrls[index]]
+	if !ok || expiry <= 0 {
+		return
+	}
+	cutoff := time.Now().Add(-expiry)
+	expired := 0
+	for _, item := range m.feedSlice[index].Items {
+		date := item.PublishedParsed
+		if item.UpdatedParsed != nil {
+			date = item.UpdatedParsed
+		}
+		if date != nil && date.Before(cutoff) && m.readState.MarkRead(item) {
+			expired++
+		}
+	}
+	if expired == 0 {
+		return
+	}
+	log.Printf("expired %d items of %s", expired, m.feedUrls[index])
+	if err := m.readState.Save(); err != nil {
+		log.Println(err)
+	}
+}
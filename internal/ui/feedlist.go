@@ -0,0 +1,347 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxSidebarWidth caps the feed list, which otherwise takes a third of the
+// screen.
+const maxSidebarWidth = 40
+
+// feedListKeys are the bindings only the feed list has, for its groups.
+var feedListKeys = struct {
+	Collapse key.Binding
+}{
+	Collapse: key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("space", "fold group"),
+	),
+}
+
+// feedListRow is a line of the feed list: a group's header, or a feed listed
+// under the group it's in (if any).
+type feedListRow struct {
+	group string
+	// feed is the feed's index, -1 for the header
+	feed int
+}
+
+// feedListRows lists the groups, each header followed by its feeds unless
+// it's folded, then the feeds that aren't in any group. A feed in more than
+// one group is listed under each.
+func (m model) feedListRows() []feedListRow {
+	var rows []feedListRow
+	grouped := make(map[int]bool)
+	for _, name := range m.groupNames() {
+		rows = append(rows, feedListRow{group: name, feed: -1})
+		for _, i := range m.groupFeeds(name) {
+			grouped[i] = true
+			if !m.collapsedGroups[name] {
+				rows = append(rows, feedListRow{group: name, feed: i})
+			}
+		}
+	}
+	for i := range m.feedSlice {
+		if !grouped[i] {
+			rows = append(rows, feedListRow{feed: i})
+		}
+	}
+	return rows
+}
+
+// feedListRowOf is the row a feed is on, or its group's header if that's
+// folded.
+func (m model) feedListRowOf(index int) int {
+	header := -1
+	for row, entry := range m.feedListRows() {
+		if entry.feed == index {
+			return row
+		}
+		if entry.feed < 0 && m.collapsedGroups[entry.group] && header < 0 {
+			for _, i := range m.groupFeeds(entry.group) {
+				if i == index {
+					header = row
+				}
+			}
+		}
+	}
+	if header < 0 {
+		return 0
+	}
+	return header
+}
+
+// sidebarWidth is how much of the screen the feed list takes up, if it's
+// showing.
+func (m model) sidebarWidth() int {
+	if !m.feedListMode {
+		return 0
+	}
+	width := m.width / 3
+	if width > maxSidebarWidth {
+		width = maxSidebarWidth
+	}
+	return width
+}
+
+// articleWidth is what's left of the screen for the article.
+func (m model) articleWidth() int {
+	return m.width - m.sidebarWidth()
+}
+
+// toggleFeedList shows or hides the feed list, starting on the feed being
+// read. The article needs rerendering to fit afterwards.
+func (m *model) toggleFeedList() {
+	m.feedListMode = !m.feedListMode
+	m.feedListIndex = m.feedListRowOf(m.feedSliceIndex)
+	m.viewport.Width = m.articleWidth()
+}
+
+func (m model) updateFeedList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	rows := m.feedListRows()
+	if m.feedListIndex >= len(rows) {
+		m.feedListIndex = len(rows) - 1
+	}
+	selected := rows[m.feedListIndex]
+	switch {
+	case key.Matches(msg, defaultKeyMap.Up):
+		if m.feedListIndex > 0 {
+			m.feedListIndex--
+		}
+	case key.Matches(msg, defaultKeyMap.Down):
+		if m.feedListIndex < len(rows)-1 {
+			m.feedListIndex++
+		}
+	case selected.feed < 0 && (key.Matches(msg, defaultKeyMap.Open) || key.Matches(msg, feedListKeys.Collapse)):
+		m.toggleGroup(selected.group)
+	case key.Matches(msg, feedListKeys.Collapse) && selected.group != "":
+		m.toggleGroup(selected.group)
+		m.feedListIndex = m.feedListRowOf(selected.feed)
+	case key.Matches(msg, defaultKeyMap.MarkAllRead):
+		// the group on its header, otherwise just the feed
+		if selected.feed < 0 {
+			cmd := m.markFeedsRead(selected.group, m.groupFeeds(selected.group))
+			return m, cmd
+		}
+		title := m.feedSlice[selected.feed].Title
+		if title == "" {
+			title = m.feedUrls[selected.feed]
+		}
+		cmd := m.markFeedsRead(title, []int{selected.feed})
+		return m, cmd
+	case key.Matches(msg, defaultKeyMap.Open):
+		m.toggleFeedList()
+		if selected.feed != m.feedSliceIndex {
+			m.feedSliceIndex = selected.feed
+			m.feedIndex = 0
+		}
+		return m.Update(rerenderMsg{})
+	case key.Matches(msg, defaultKeyMap.Pause):
+		if selected.feed < 0 {
+			cmd := m.setStatus("Groups can't be paused, only their feeds")
+			return m, cmd
+		}
+		cmd := m.togglePaused(selected.feed)
+		return m, cmd
+	case key.Matches(msg, defaultKeyMap.Refresh):
+		indices := []int{selected.feed}
+		if selected.feed < 0 {
+			indices = m.groupFeeds(selected.group)
+		}
+		cmd := m.refreshFeeds(indices)
+		return m, cmd
+	case key.Matches(msg, defaultKeyMap.FeedList), key.Matches(msg, defaultKeyMap.Back):
+		m.toggleFeedList()
+		return m.Update(rerenderMsg{})
+	case key.Matches(msg, defaultKeyMap.Help):
+		m.help.ShowAll = !m.help.ShowAll
+	case key.Matches(msg, defaultKeyMap.Quit):
+		return m, m.quit()
+	}
+	return m, nil
+}
+
+// feedListEntry is a feed's two lines in the feed list: its title with the
+// number of unread items, and when it was last fetched or why that failed.
+func (m model) feedListEntry(index int, width int) []string {
+	feed := m.feedSlice[index]
+	title := feed.Title
+	if title == "" {
+		title = m.feedUrls[index]
+	}
+	unread := fmt.Sprint(m.readState.UnreadCount(feed))
+	fetchErr, failing := m.feedErrors[index]
+	if failing {
+		marker := "! "
+		if !m.accessible {
+			marker = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("9")).Render(m.symbol("⚠ ", "! "))
+		}
+		unread = marker + unread
+	}
+
+	badge := m.feedBadge(index)
+	titleWidth := width - lipgloss.Width(badge) - lipgloss.Width(unread) - 1
+	if titleWidth < 1 {
+		titleWidth = 1
+	}
+	title = lipgloss.NewStyle().MaxWidth(titleWidth).Render(title)
+	gap := width - lipgloss.Width(badge) - lipgloss.Width(title) - lipgloss.Width(unread)
+	if gap < 1 {
+		gap = 1
+	}
+	first := badge + title + strings.Repeat(" ", gap) + unread
+
+	var second string
+	switch fetched := m.fetcher.Stats.Get(m.feedUrls[index]).LastFetched; {
+	case m.isStarredFeed(index):
+		second = fmt.Sprintf("%d starred", feed.Len())
+	case m.isAuthorFeed(index):
+		second = fmt.Sprintf("following, %d article(s)", feed.Len())
+	case m.isPaused(index):
+		second = "paused"
+	case failing:
+		// as much of the error as fits, the rest being in the feed itself
+		second = lipgloss.NewStyle().MaxWidth(width - 2).Render("failed: " + fetchErr.Error())
+	case fetched.IsZero():
+		second = "not fetched yet"
+	default:
+		second = "fetched " + fetched.In(m.timezone).Format("15:04")
+		if fresh := len(m.freshItems[index]); fresh > 0 {
+			second += fmt.Sprintf(", %d new", fresh)
+		}
+		if m.isStatusPage(index) {
+			second = lipgloss.NewStyle().MaxWidth(width - 2).Render(m.statusPageState(index) + ", " + second)
+		}
+	}
+	return []string{first, "  " + second}
+}
+
+// groupEntry is a group's two lines in the feed list: its name, folded or
+// not, with the number of unread items in its feeds, and how many feeds it
+// has.
+func (m model) groupEntry(name string, width int) []string {
+	indices := m.groupFeeds(name)
+	unread, failing := 0, 0
+	for _, i := range indices {
+		unread += m.readState.UnreadCount(m.feedSlice[i])
+		if _, ok := m.feedErrors[i]; ok {
+			failing++
+		}
+	}
+	fold := m.symbol("▾ ", "v ")
+	if m.collapsedGroups[name] {
+		fold = m.symbol("▸ ", "> ")
+	}
+	if m.accessible {
+		fold = ""
+		if m.collapsedGroups[name] {
+			name += " (folded)"
+		}
+	}
+	count := fmt.Sprint(unread)
+	title := lipgloss.NewStyle().Bold(!m.accessible).MaxWidth(width - lipgloss.Width(count) - 1).Render(fold + name)
+	gap := width - lipgloss.Width(title) - lipgloss.Width(count)
+	if gap < 1 {
+		gap = 1
+	}
+
+	second := fmt.Sprintf("%d feed(s)", len(indices))
+	if failing > 0 {
+		second += fmt.Sprintf(", %d failing", failing)
+	}
+	return []string{title + strings.Repeat(" ", gap) + count, "  " + second}
+}
+
+// assembleFeedList renders the feed list, scrolled to keep the selected
+// row in view.
+func assembleFeedList(m model) string {
+	// leave room for the selection marker and the border
+	width := m.sidebarWidth() - 3
+	if width < 1 {
+		width = 1
+	}
+	rows := m.feedListRows()
+	height := m.viewport.Height
+	// the group keys aren't listed anywhere else
+	var hint string
+	if len(m.groups) > 0 {
+		hint = lipgloss.NewStyle().MaxWidth(width + 1).Render(" " + strings.Join([]string{
+			feedListKeys.Collapse.Help().Key + " fold",
+			defaultKeyMap.MarkAllRead.Help().Key + " mark read",
+		}, m.symbol(" · ", ", ")))
+		height -= 2
+	}
+	// every row takes two lines, plus a blank one between them
+	visible := (height + 1) / 3
+	if visible < 1 {
+		visible = 1
+	}
+	selectedRow := m.feedListIndex
+	if selectedRow >= len(rows) {
+		selectedRow = len(rows) - 1
+	}
+	start := 0
+	if selectedRow >= visible {
+		start = selectedRow - visible + 1
+	}
+	end := start + visible
+	if end > len(rows) {
+		end = len(rows)
+	}
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color(m.feedAccent()))
+	dimStyle := lipgloss.NewStyle().Faint(true)
+
+	var entries []string
+	for i := start; i < end; i++ {
+		var lines []string
+		switch row := rows[i]; {
+		case row.feed < 0:
+			lines = m.groupEntry(row.group, width)
+		case row.group != "":
+			// a group's feeds are indented under it
+			lines = m.feedListEntry(row.feed, width-2)
+			lines[0], lines[1] = "  "+lines[0], "  "+lines[1]
+		default:
+			lines = m.feedListEntry(row.feed, width)
+		}
+		if m.accessible {
+			marker := "  "
+			if i == selectedRow {
+				marker = "> "
+			}
+			lines[0] = marker + lines[0]
+		} else {
+			if i == selectedRow {
+				lines[0] = selectedStyle.Render(m.symbol("▌", ">")) + lines[0]
+			} else {
+				lines[0] = " " + lines[0]
+			}
+			lines[1] = dimStyle.Render(lines[1])
+		}
+		entries = append(entries, strings.Join(lines, "\n"))
+	}
+
+	style := lipgloss.NewStyle().
+		Width(m.sidebarWidth()).
+		Height(m.viewport.Height).
+		MaxHeight(m.viewport.Height)
+	if !m.accessible {
+		style = style.
+			BorderStyle(m.border(lipgloss.NormalBorder())).
+			BorderRight(true).
+			Width(m.sidebarWidth() - 1)
+	}
+	list := strings.Join(entries, "\n\n")
+	if hint != "" {
+		list = lipgloss.NewStyle().Height(height).Render(list) + "\n\n" + hint
+	}
+	return style.Render(list)
+}
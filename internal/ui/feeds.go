@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/homielabs/golang-rss-client/internal/fetch"
+	"github.com/mmcdole/gofeed"
+)
+
+type feedFetchedMsg struct {
+	index int
+	// feedUrl is what was fetched, in case index has since been given to
+	// another feed
+	feedUrl string
+	feed    *gofeed.Feed
+	// movedTo is set if the feed has moved permanently
+	movedTo string
+	err     error
+}
+
+// fetchFeedCmd fetches the feed at feedSlice[index] in the background.
+func fetchFeedCmd(ctx context.Context, index int, feedUrl string, fetcher fetch.Fetcher) tea.Cmd {
+	return func() tea.Msg {
+		feed, movedTo, err := fetcher.FetchMoved(ctx, feedUrl)
+		return feedFetchedMsg{index: index, feedUrl: feedUrl, feed: feed, movedTo: movedTo, err: err}
+	}
+}
+
+// isPaused reports whether fetching has been paused for a feed.
+func (m model) isPaused(index int) bool {
+	return m.pausedFeeds[m.feedUrls[index]]
+}
+
+type refreshTickMsg struct {
+	index   int
+	feedUrl string
+}
+
+// refreshTickCmd waits for a feed's polling interval to elapse.
+func refreshTickCmd(index int, feedUrl string, interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return refreshTickMsg{index: index, feedUrl: feedUrl}
+	})
+}
+
+// indexOf finds where a feed a command was started for is now, since feeds
+// can be removed (shifting the rest along) or moved to a new URL while it runs.
+// ok is false if the feed is gone.
+func (m model) indexOf(index int, feedUrl string) (int, bool) {
+	if index < len(m.feedUrls) && m.feedUrls[index] == feedUrl {
+		return index, true
+	}
+	for i, subscribed := range m.feedUrls {
+		if subscribed == feedUrl {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// refreshInterval is how often a feed is polled; priority feeds and status
+// pages get their own, shorter interval. Zero disables polling. During quiet
+// hours polling slows down to the quiet interval, if that's longer.
+func (m model) refreshInterval(index int) time.Duration {
+	interval := m.pollInterval
+	if m.priorityFeeds[m.feedUrls[index]] || m.isStatusPage(index) {
+		interval = m.priorityPollInterval
+	}
+	if interval > 0 && m.isQuiet() && m.quietPollInterval > interval {
+		interval = m.quietPollInterval
+	}
+	return interval
+}
+
+// isQuiet reports whether it's currently quiet hours.
+func (m model) isQuiet() bool {
+	now := time.Now()
+	for _, window := range m.quietHours {
+		if window.Contains(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// scheduleRefresh starts the polling loop for a feed, if it polls at all.
+func (m model) scheduleRefresh(index int) tea.Cmd {
+	interval := m.refreshInterval(index)
+	if interval <= 0 || m.isVirtualFeed(index) {
+		return nil
+	}
+	return refreshTickCmd(index, m.feedUrls[index], interval)
+}
+
+// converterFor picks the markdown converter for a feed.
+func (m model) converterFor(index int) *md.Converter {
+	if converter, ok := m.feedConverters[m.feedUrls[index]]; ok {
+		return converter
+	}
+	return m.markdownConverter
+}
+
+// refreshFeeds fetches feeds on request, turning the spinner in the header
+// until they're back. Paused feeds and the starred one are skipped, as are
+// feeds already on their way.
+func (m *model) refreshFeeds(indices []int) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, i := range indices {
+		if m.isPaused(i) || m.isVirtualFeed(i) || m.refreshing[i] || m.loading[i] {
+			continue
+		}
+		m.refreshing[i] = true
+		cmds = append(cmds, fetchFeedCmd(m.ctx, i, m.feedUrls[i], m.fetcher))
+	}
+	if len(cmds) == 0 {
+		return m.setStatus("Nothing to refresh")
+	}
+	// restarts the spinner if it had stopped; its tags retire the old tick
+	// loop otherwise
+	return tea.Batch(append(cmds, spinner.Tick)...)
+}
+
+// loadingBanner counts the feeds still on their way in, with the spinner.
+func (m model) loadingBanner() string {
+	text := fmt.Sprintf("Loading feeds %d/%d", len(m.feedSlice)-len(m.loading), len(m.feedSlice))
+	if m.accessible {
+		return text
+	}
+	return m.spinner.View() + " " + text
+}
@@ -0,0 +1,43 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// findTarget is somewhere the finder can jump to: a feed, or one of its
+// items when item isn't -1.
+type findTarget struct {
+	feed int
+	item int
+}
+
+// openFinder lists every feed and every loaded article to pick from.
+func (m *model) openFinder() {
+	m.findTargets = nil
+	var labels []string
+	separator := m.symbol(" ▸ ", " > ")
+	for i, feed := range m.feedSlice {
+		title := feed.Title
+		if title == "" {
+			title = m.feedUrls[i]
+		}
+		m.findTargets = append(m.findTargets, findTarget{feed: i, item: -1})
+		labels = append(labels, title)
+		for j, item := range feed.Items {
+			m.findTargets = append(m.findTargets, findTarget{feed: i, item: j})
+			labels = append(labels, title+separator+item.Title)
+		}
+	}
+	m.picker = newPicker(pickerFinder, "find a feed or article", labels)
+}
+
+// jumpTo goes to the feed or article picked in the finder.
+func (m model) jumpTo(chosen int) (tea.Model, tea.Cmd) {
+	target := m.findTargets[chosen]
+	m.feedSliceIndex = target.feed
+	m.feedIndex = 0
+	if target.item >= 0 {
+		m.feedIndex = target.item
+	}
+	return m.Update(rerenderMsg{})
+}
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/homielabs/golang-rss-client/internal/store"
+	"github.com/mmcdole/gofeed"
+)
+
+// addFresh remembers the items a background refresh brought in, so they can
+// be announced and jumped to rather than just appearing.
+func (m model) addFresh(index int, items []*gofeed.Item) {
+	for _, item := range items {
+		m.freshItems[index] = append(m.freshItems[index], store.ItemKey(item))
+	}
+}
+
+// seen drops an item from the fresh ones once it has been shown.
+func (m model) seen(index int, key string) {
+	keys := m.freshItems[index]
+	for i, fresh := range keys {
+		if fresh == key {
+			keys = append(keys[:i], keys[i+1:]...)
+			break
+		}
+	}
+	if len(keys) == 0 {
+		delete(m.freshItems, index)
+	} else {
+		m.freshItems[index] = keys
+	}
+}
+
+// isFresh reports whether a background refresh brought an item in that
+// hasn't been shown yet.
+func (m model) isFresh(index int, key string) bool {
+	for _, fresh := range m.freshItems[index] {
+		if fresh == key {
+			return true
+		}
+	}
+	return false
+}
+
+// lastRefreshed is when the current feed was last fetched successfully, as
+// shown in the footer, or empty if it hasn't been this session.
+func (m model) lastRefreshed() string {
+	fetched := m.fetcher.Stats.Get(m.feedUrls[m.feedSliceIndex]).LastFetched
+	if fetched.IsZero() || m.isVirtualFeed(m.feedSliceIndex) {
+		return ""
+	}
+	return "refreshed " + fetched.In(m.timezone).Format("15:04")
+}
+
+func (m model) freshCount() int {
+	count := 0
+	for _, keys := range m.freshItems {
+		count += len(keys)
+	}
+	return count
+}
+
+// freshBanner announces fresh items, with the key that jumps to them if it's
+// bound, or is empty if there aren't any.
+func (m model) freshBanner() string {
+	count := m.freshCount()
+	var hint string
+	if keys := defaultKeyMap.Fresh.Keys(); len(keys) > 0 {
+		hint = m.symbol(" — ", " - ") + "press " + keys[0] + " to jump"
+	}
+	switch count {
+	case 0:
+		return ""
+	case 1:
+		return "1 new item" + hint
+	}
+	return fmt.Sprintf("%d new items%s", count, hint)
+}
+
+// jumpToFresh moves to the first fresh item in the first feed that has any,
+// reporting whether there was one to go to.
+func (m *model) jumpToFresh() bool {
+	for index := range m.feedSlice {
+		for len(m.freshItems[index]) > 0 {
+			key := m.freshItems[index][0]
+			for i, item := range m.feedSlice[index].Items {
+				if store.ItemKey(item) == key {
+					m.feedSliceIndex = index
+					m.feedIndex = i
+					return true
+				}
+			}
+			// the item went away with a later refresh, but the ones after it
+			// may not have
+			m.seen(index, key)
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestJumpToFresh(t *testing.T) {
+	feeds := []gofeed.Feed{
+		{Items: []*gofeed.Item{{GUID: "a1"}, {GUID: "a2"}}},
+		{Items: []*gofeed.Item{{GUID: "b1"}, {GUID: "b2"}, {GUID: "b3"}}},
+	}
+	tests := []struct {
+		name       string
+		fresh      map[int][]string
+		ok         bool
+		feed, item int
+		left       map[int][]string
+	}{
+		{"none", map[int][]string{}, false, 0, 0, map[int][]string{}},
+		{"first feed first", map[int][]string{0: {"a2"}, 1: {"b1"}}, true, 0, 1, map[int][]string{0: {"a2"}, 1: {"b1"}}},
+		{"oldest fresh item", map[int][]string{1: {"b3", "b2"}}, true, 1, 2, map[int][]string{1: {"b3", "b2"}}},
+		{"gone item skipped", map[int][]string{1: {"gone", "b2", "b3"}}, true, 1, 1, map[int][]string{1: {"b2", "b3"}}},
+		{"feed with only gone items skipped", map[int][]string{0: {"gone", "also gone"}, 1: {"b1"}}, true, 1, 0, map[int][]string{1: {"b1"}}},
+		{"all gone", map[int][]string{0: {"gone"}, 1: {"also gone"}}, false, 0, 0, map[int][]string{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := &model{feedSlice: feeds, freshItems: test.fresh}
+			if ok := m.jumpToFresh(); ok != test.ok {
+				t.Fatalf("jumpToFresh() = %v, want %v", ok, test.ok)
+			}
+			if test.ok && (m.feedSliceIndex != test.feed || m.feedIndex != test.item) {
+				t.Errorf("jumped to feed %d item %d, want feed %d item %d", m.feedSliceIndex, m.feedIndex, test.feed, test.item)
+			}
+			if !reflect.DeepEqual(m.freshItems, test.left) {
+				t.Errorf("fresh items left %v, want %v", m.freshItems, test.left)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/homielabs/golang-rss-client/internal/render"
+	"github.com/homielabs/golang-rss-client/internal/store"
+)
+
+// maxArticleBytes caps how much of an article's page is read looking for the
+// article. Pages bigger than this are mostly scripts and ads anyway.
+const maxArticleBytes = 5 * 1024 * 1024
+
+type fullTextMsg struct {
+	// key is the ItemKey of the item the article is for
+	key     string
+	content string
+	err     error
+}
+
+// fetchFullTextCmd fetches the page an item links to and extracts the article
+// from it.
+func fetchFullTextCmd(ctx context.Context, key string, link string, timeout time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+		if err != nil {
+			return fullTextMsg{key: key, err: err}
+		}
+		req.Header.Set("User-Agent", "golang-rss-client")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return fullTextMsg{key: key, err: err}
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fullTextMsg{key: key, err: fmt.Errorf("%s", resp.Status)}
+		}
+
+		start := time.Now()
+		_, content, err := render.Readable(io.LimitReader(resp.Body, maxArticleBytes), resp.Request.URL.String())
+		if err != nil {
+			return fullTextMsg{key: key, err: err}
+		}
+		log.Printf("timing: full article for %s extracted in %s", link, time.Since(start))
+		return fullTextMsg{key: key, content: content}
+	}
+}
+
+// toggleFullText swaps the item being read for the whole article from the
+// page it links to, fetching that the first time, or back again.
+func (m *model) toggleFullText() tea.Cmd {
+	feed := m.feedSlice[m.feedSliceIndex]
+	if m.feedIndex >= feed.Len() {
+		return m.setStatus("Nothing to fetch")
+	}
+	item := feed.Items[m.feedIndex]
+	key := store.ItemKey(item)
+	if _, ok := m.fullText[key]; ok {
+		delete(m.fullText, key)
+		m.forgetPrerendered(key)
+		return tea.Batch(
+			func() tea.Msg { return rerenderMsg{scroll: true} },
+			m.setStatus("Back to the feed's copy"),
+		)
+	}
+	if m.lowBandwidth {
+		return m.setStatus("Full articles aren't fetched in low-bandwidth mode")
+	}
+	if item.Link == "" {
+		return m.setStatus("This article has no link")
+	}
+	if m.fetchingFullText[key] {
+		return nil
+	}
+	m.fetchingFullText[key] = true
+	return tea.Batch(
+		fetchFullTextCmd(m.ctx, key, item.Link, m.fetcher.Timeout),
+		m.setStatus("Fetching the full article"+m.symbol("…", "...")),
+	)
+}
+
+// fullTextFetched shows a fetched article, if the item it's for is still the
+// one being read; otherwise it's kept for when it is.
+func (m *model) fullTextFetched(msg fullTextMsg) tea.Cmd {
+	delete(m.fetchingFullText, msg.key)
+	if msg.err != nil {
+		log.Println(msg.err)
+		return m.setStatus("Couldn't get the full article: " + msg.err.Error())
+	}
+	m.fullText[msg.key] = msg.content
+	m.forgetPrerendered(msg.key)
+	if msg.key != m.shown {
+		return nil
+	}
+	return tea.Batch(
+		func() tea.Msg { return rerenderMsg{scroll: true} },
+		m.setStatus("Showing the full article"),
+	)
+}
+
+// forgetPrerendered drops an item rendered ahead of time, at every width.
+func (m *model) forgetPrerendered(key string) {
+	for prerendered := range m.prerendered {
+		if prerendered.item == key {
+			delete(m.prerendered, prerendered)
+		}
+	}
+}
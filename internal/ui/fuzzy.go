@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// fuzzyScore reports whether every character of pattern appears in text, in
+// order, and scores the match: consecutive characters, matches at the start
+// of a word and matches early on all count for more. Case is ignored.
+func fuzzyScore(pattern string, text string) (int, bool) {
+	pattern = strings.ToLower(pattern)
+	runes := []rune(strings.ToLower(text))
+
+	score := 0
+	last := -1
+	i := 0
+	for _, p := range pattern {
+		if unicode.IsSpace(p) {
+			continue
+		}
+		for i < len(runes) && runes[i] != p {
+			i++
+		}
+		if i == len(runes) {
+			return 0, false
+		}
+		score++
+		if i == last+1 {
+			score += 3
+		}
+		if i == 0 || !unicode.IsLetter(runes[i-1]) && !unicode.IsDigit(runes[i-1]) {
+			score += 2
+		}
+		last = i
+		i++
+	}
+	// prefer matches that end early in shorter text
+	return score*100 - last, true
+}
+
+// fuzzyFilter returns the indexes of the candidates matching pattern, best
+// match first. An empty pattern matches everything, in order.
+func fuzzyFilter(pattern string, candidates []string) []int {
+	type match struct {
+		index int
+		score int
+	}
+	var matches []match
+	for i, candidate := range candidates {
+		if score, ok := fuzzyScore(pattern, candidate); ok {
+			matches = append(matches, match{index: i, score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	indexes := make([]int, len(matches))
+	for i, m := range matches {
+		indexes[i] = m.index
+	}
+	return indexes
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		pattern string
+		text    string
+		ok      bool
+	}{
+		{"", "anything", true},
+		{"rfa", "Refresh all", true},
+		{"REF", "refresh", true},
+		{"mark all", "Mark all read", true},
+		{"ar", "Star", true},
+		{"ra", "Star", false},
+		{"x", "Star", false},
+		{"starred", "Star", false},
+	}
+	for _, test := range tests {
+		t.Run(test.pattern+" in "+test.text, func(t *testing.T) {
+			if _, ok := fuzzyScore(test.pattern, test.text); ok != test.ok {
+				t.Errorf("fuzzyScore(%q, %q) matched = %v, want %v", test.pattern, test.text, ok, test.ok)
+			}
+		})
+	}
+}
+
+func TestFuzzyScoreRanks(t *testing.T) {
+	tests := []struct {
+		name          string
+		pattern       string
+		better, worse string
+	}{
+		{"consecutive", "star", "Star article", "Set a mark"},
+		{"word starts", "fl", "Feed list", "Full article"},
+		{"earlier", "re", "Refresh", "Mark read"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			better, _ := fuzzyScore(test.pattern, test.better)
+			worse, _ := fuzzyScore(test.pattern, test.worse)
+			if better <= worse {
+				t.Errorf("%q scores %d in %q, no better than %d in %q", test.pattern, better, test.better, worse, test.worse)
+			}
+		})
+	}
+}
+
+func TestFuzzyFilter(t *testing.T) {
+	candidates := []string{"Mark all read", "Refresh", "Refresh all", "Star article"}
+	tests := []struct {
+		pattern string
+		want    []int
+	}{
+		{"", []int{0, 1, 2, 3}},
+		{"refresh", []int{1, 2}},
+		{"ra", []int{2, 0, 3}},
+		{"zzz", []int{}},
+	}
+	for _, test := range tests {
+		t.Run(test.pattern, func(t *testing.T) {
+			if got := fuzzyFilter(test.pattern, candidates); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("fuzzyFilter(%q) = %v, want %v", test.pattern, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// groupNames lists the feed groups in alphabetical order.
+func (m model) groupNames() []string {
+	var names []string
+	for name := range m.groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// openGroupPicker offers the feed groups to refresh.
+func (m *model) openGroupPicker() tea.Cmd {
+	names := m.groupNames()
+	if len(names) == 0 {
+		return m.setStatus("No feed groups are configured")
+	}
+	var labels []string
+	for _, name := range names {
+		labels = append(labels, fmt.Sprintf("%-30s %d feed(s)", name, len(m.groups[name])))
+	}
+	m.picker = newPicker(pickerGroup, "refresh which group?", labels)
+	return nil
+}
+
+// groupFeeds are the indices of the feeds in a group, in the order they're
+// read in.
+func (m model) groupFeeds(name string) []int {
+	inGroup := make(map[string]bool)
+	for _, feedUrl := range m.groups[name] {
+		inGroup[feedUrl] = true
+	}
+	var indices []int
+	for i, feedUrl := range m.feedUrls {
+		if inGroup[feedUrl] {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// toggleGroup folds a group's feeds away in the feed list, or unfolds them.
+func (m *model) toggleGroup(name string) {
+	if m.collapsedGroups[name] {
+		delete(m.collapsedGroups, name)
+	} else {
+		m.collapsedGroups[name] = true
+	}
+}
+
+// refreshGroup fetches every feed in a group, leaving the others on their
+// usual schedule.
+func (m model) refreshGroup(chosen int) (tea.Model, tea.Cmd) {
+	name := m.groupNames()[chosen]
+	before := len(m.refreshing)
+	cmd := m.refreshFeeds(m.groupFeeds(name))
+	if len(m.refreshing) == before {
+		return m, cmd
+	}
+	return m, tea.Batch(cmd, m.setStatus(fmt.Sprintf("Refreshing %d feed(s) in %s", len(m.refreshing)-before, name)))
+}
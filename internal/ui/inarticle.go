@@ -0,0 +1,232 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// highlightOn and highlightOff turn reverse video on and off around matches,
+// leaving the colors glamour styled the text with alone.
+const (
+	highlightOn  = "\x1b[7m"
+	highlightOff = "\x1b[27m"
+)
+
+// matchContext is how many lines are left above a match scrolled to, so it
+// isn't read out of context.
+const matchContext = 2
+
+// articleSearch is a search within the article being read, as in less: the
+// matches are highlighted and the next and previous ones scrolled to.
+type articleSearch struct {
+	// typing is whether the query is being typed
+	typing bool
+	input  textinput.Model
+	// re is what the query looks for, nil with no search going
+	re    *regexp.Regexp
+	query string
+	// lines are the lines with a match in them, and current which of them
+	// was scrolled to last
+	lines   []int
+	current int
+}
+
+// highlightMatches puts every match of re in styled content in reverse
+// video, returning the lines they're on. Matching goes by the text as it
+// reads, so the escape sequences styling it don't get in the way.
+func highlightMatches(content string, re *regexp.Regexp) (string, []int) {
+	var matched []int
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		plain, positions := stripEscapes(line)
+		found := re.FindAllStringIndex(plain, -1)
+		if len(found) == 0 {
+			continue
+		}
+		matched = append(matched, i)
+
+		var b strings.Builder
+		copied, next, inMatch := 0, 0, false
+		for p, pos := range positions {
+			if inMatch && p == found[next][1] {
+				b.WriteString(highlightOff)
+				inMatch = false
+				next++
+			}
+			if escapes := line[copied:pos]; escapes != "" {
+				b.WriteString(escapes)
+				if inMatch {
+					// a reset in the styling would end the highlight
+					b.WriteString(highlightOn)
+				}
+			}
+			// empty matches have nothing to highlight
+			for next < len(found) && found[next][0] == found[next][1] {
+				next++
+			}
+			if !inMatch && next < len(found) && p == found[next][0] {
+				b.WriteString(highlightOn)
+				inMatch = true
+			}
+			b.WriteByte(line[pos])
+			copied = pos + 1
+		}
+		if inMatch {
+			b.WriteString(highlightOff)
+		}
+		b.WriteString(line[copied:])
+		lines[i] = b.String()
+	}
+	return strings.Join(lines, "\n"), matched
+}
+
+// stripEscapes is a line without its escape sequences, along with where each
+// of its bytes is in the line.
+func stripEscapes(line string) (string, []int) {
+	var plain []byte
+	var positions []int
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\x1b' && i+1 < len(line) && line[i+1] == '[' {
+			// CSI sequences end at the first byte from @ to ~
+			j := i + 2
+			for j < len(line) && (line[j] < 0x40 || line[j] > 0x7e) {
+				j++
+			}
+			i = j
+			continue
+		}
+		plain = append(plain, line[i])
+		positions = append(positions, i)
+	}
+	return string(plain), positions
+}
+
+// setContent puts an article in the viewport, with the matches of the
+// search in it highlighted.
+func (m *model) setContent(content string) {
+	m.content = content
+	if m.inArticle.re == nil {
+		m.viewport.SetContent(content)
+		return
+	}
+	highlighted, lines := highlightMatches(content, m.inArticle.re)
+	m.inArticle.lines = lines
+	m.viewport.SetContent(highlighted)
+}
+
+// openArticleSearch asks for something to look for in the article.
+func (m *model) openArticleSearch() {
+	input := textinput.NewModel()
+	input.Prompt = "/"
+	input.Placeholder = "text, or re:<regexp>"
+	input.SetCursorMode(textinput.CursorStatic)
+	input.Focus()
+	m.inArticle.input = input
+	m.inArticle.typing = true
+}
+
+func (m model) updateArticleSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.inArticle.typing = false
+		return m, nil
+	case tea.KeyCtrlC:
+		return m, m.quit()
+	case tea.KeyEnter:
+		m.inArticle.typing = false
+		query := m.inArticle.input.Value()
+		if query == "" {
+			// like less, an empty search repeats the last one
+			query = m.inArticle.query
+		}
+		if query == "" {
+			return m, nil
+		}
+		re, err := searchRegexp(query)
+		if err != nil {
+			cmd := m.setStatus("Not a regular expression: " + err.Error())
+			return m, cmd
+		}
+		// matches can't be found in what isn't styled yet
+		if m.lazy != nil {
+			m.lazy.styleUntil(math.MaxInt32)
+			m.content = m.lazy.content
+		}
+		m.inArticle.re = re
+		m.inArticle.query = query
+		yOffset := m.viewport.YOffset
+		m.setContent(m.content)
+		m.viewport.YOffset = yOffset
+		if len(m.inArticle.lines) == 0 {
+			m.clearArticleSearch()
+			cmd := m.setStatus(fmt.Sprintf("%q isn't in this article", query))
+			return m, cmd
+		}
+		// the first match from where the reader is
+		m.inArticle.current = len(m.inArticle.lines) - 1
+		for i, line := range m.inArticle.lines {
+			if line >= yOffset {
+				m.inArticle.current = i
+				break
+			}
+		}
+		cmd := m.gotoMatch(m.inArticle.current)
+		return m, cmd
+	}
+	var cmd tea.Cmd
+	m.inArticle.input, cmd = m.inArticle.input.Update(msg)
+	return m, cmd
+}
+
+// nextMatch scrolls to the next match, or the previous one going backwards,
+// wrapping around at the ends.
+func (m *model) nextMatch(backwards bool) tea.Cmd {
+	count := len(m.inArticle.lines)
+	if backwards {
+		return m.gotoMatch((m.inArticle.current + count - 1) % count)
+	}
+	return m.gotoMatch((m.inArticle.current + 1) % count)
+}
+
+// gotoMatch scrolls a match into view, near the top.
+func (m *model) gotoMatch(index int) tea.Cmd {
+	m.inArticle.current = index
+	yOffset := m.inArticle.lines[index] - matchContext
+	if yOffset < 0 {
+		yOffset = 0
+	}
+	m.viewport.YOffset = yOffset
+	if m.viewport.PastBottom() {
+		m.viewport.GotoBottom()
+	}
+	return m.setStatus(fmt.Sprintf("Match %d of %d", index+1, len(m.inArticle.lines)))
+}
+
+// searchingArticle reports whether the article has a search going, for n
+// and N to step through.
+func (m model) searchingArticle() bool {
+	return m.inArticle.re != nil
+}
+
+// clearArticleSearch ends the search, taking the highlights away. The query
+// is kept, to search for again.
+func (m *model) clearArticleSearch() {
+	m.inArticle.re = nil
+	m.inArticle.lines = nil
+	yOffset := m.viewport.YOffset
+	m.setContent(m.content)
+	m.viewport.YOffset = yOffset
+}
+
+// assembleArticleSearch puts the search input over the last line of the
+// article, where less has it.
+func assembleArticleSearch(body string, m model) string {
+	lines := strings.Split(body, "\n")
+	lines[len(lines)-1] = m.inArticle.input.View()
+	return strings.Join(lines, "\n")
+}
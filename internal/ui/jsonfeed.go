@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/homielabs/golang-rss-client/internal/fetch"
+	"github.com/homielabs/golang-rss-client/internal/render"
+	"github.com/homielabs/golang-rss-client/internal/store"
+	"github.com/mmcdole/gofeed"
+)
+
+// itemExtras sums up what an item points to besides its own page, for the
+// footer and the article list: the page a JSON Feed linkblog item is about,
+// its attachments (enclosures, in RSS), how many feeds it's in if it's a
+// press release, and whether it's behind a paywall. Brief ones are only the
+// site and how many attachments there are, for when space is short.
+func (m model) itemExtras(item *gofeed.Item, brief bool) []string {
+	var extras []string
+	if severity := m.severityExtra(item, brief); severity != "" {
+		extras = append(extras, severity)
+	}
+	if external := item.Custom[fetch.ExternalURLKey]; external != "" {
+		extras = append(extras, m.symbol("↗ ", "-> ")+hostOf(external))
+	}
+	if len(item.Enclosures) > 0 {
+		attachment := describeAttachment(item.Enclosures[0], itemDuration(item))
+		if more := len(item.Enclosures) - 1; more > 0 {
+			attachment += fmt.Sprintf(" +%d", more)
+		}
+		if brief {
+			attachment = strconv.Itoa(len(item.Enclosures))
+		}
+		// podcasts' episodes get a badge of their own
+		badge := m.symbol("📎 ", "attached: ")
+		if media := mediaEnclosure(item); media != nil && isMedia(media.Type) {
+			if strings.HasPrefix(media.Type, "video/") {
+				badge = m.symbol("▶ ", "video: ")
+			} else {
+				badge = m.symbol("♫ ", "audio: ")
+			}
+		}
+		extras = append(extras, badge+attachment)
+	}
+	if feeds := m.syndicated[store.ItemKey(item)]; feeds > 0 {
+		if brief {
+			extras = append(extras, m.symbol("⧉ ", "copies: ")+strconv.Itoa(feeds))
+		} else {
+			extras = append(extras, m.symbol("⧉ ", "")+fmt.Sprintf("press release in %d feeds", feeds))
+		}
+	}
+	if m.paywalled(item) {
+		extras = append(extras, m.symbol("🔒 ", "")+"paywall")
+	}
+	return extras
+}
+
+// itemLinks are the links of an item that aren't in its text, offered in link
+// selection after the ones that are: the external page, the attachments and
+// the author's avatar.
+func itemLinks(item *gofeed.Item) []articleLink {
+	var links []articleLink
+	if external := item.Custom[fetch.ExternalURLKey]; external != "" {
+		links = append(links, articleLink{text: "external link", url: render.StripControl(external)})
+	}
+	for i, enclosure := range item.Enclosures {
+		duration := 0
+		if i == 0 {
+			duration = itemDuration(item)
+		}
+		links = append(links, articleLink{
+			text: "attachment: " + describeAttachment(enclosure, duration),
+			url:  render.StripControl(enclosure.URL),
+		})
+	}
+	if avatar := item.Custom[fetch.AvatarKey]; avatar != "" {
+		text := "author's avatar"
+		if len(item.Authors) > 0 && item.Authors[0].Name != "" {
+			text = "avatar of " + item.Authors[0].Name
+		}
+		links = append(links, articleLink{text: text, url: render.StripControl(avatar)})
+	}
+	return links
+}
+
+// appendLinks adds links to a list of them, skipping those already in it.
+func appendLinks(links []articleLink, more []articleLink) []articleLink {
+	seen := make(map[string]bool)
+	for _, link := range links {
+		seen[link.url] = true
+	}
+	for _, link := range more {
+		if link.url != "" && !seen[link.url] {
+			seen[link.url] = true
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// describeAttachment is an attachment's type, size and duration, whichever
+// are known.
+func describeAttachment(enclosure *gofeed.Enclosure, seconds int) string {
+	var parts []string
+	if enclosure.Type != "" {
+		parts = append(parts, render.StripControl(enclosure.Type))
+	}
+	if size, err := strconv.ParseInt(enclosure.Length, 10, 64); err == nil && size > 0 {
+		parts = append(parts, formatBytes(size))
+	}
+	if seconds > 0 {
+		parts = append(parts, formatDuration(seconds))
+	}
+	if len(parts) == 0 {
+		return "file"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatDuration is a number of seconds the way players show it, 1:02:03 or
+// 4:05.
+func formatDuration(seconds int) string {
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+// hostOf is the site a URL is on, without the www.
+func hostOf(link string) string {
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" {
+		return render.StripControl(link)
+	}
+	return strings.TrimPrefix(u.Hostname(), "www.")
+}
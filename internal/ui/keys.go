@@ -0,0 +1,274 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// keyAction is a binding by the name it's remapped by in the keys section of
+// the config.
+type keyAction struct {
+	name    string
+	binding *key.Binding
+}
+
+// keyActions are every binding that can be remapped, in the order they're
+// listed in the README.
+var keyActions = []keyAction{
+	{"up", &defaultKeyMap.Up},
+	{"down", &defaultKeyMap.Down},
+	{"pageUp", &defaultKeyMap.PageUp},
+	{"pageDown", &defaultKeyMap.PageDown},
+	{"halfPageUp", &defaultKeyMap.HalfPageUp},
+	{"halfPageDown", &defaultKeyMap.HalfPageDown},
+	{"prevArticle", &defaultKeyMap.Left},
+	{"nextArticle", &defaultKeyMap.Right},
+	{"feedList", &defaultKeyMap.FeedList},
+	{"articleList", &defaultKeyMap.ArticleList},
+	{"topStories", &defaultKeyMap.Top},
+	{"authorArticles", &defaultKeyMap.Author},
+	{"followAuthor", &defaultKeyMap.Follow},
+	{"languageArticles", &defaultKeyMap.Language},
+	{"prevFeed", &defaultKeyMap.PrevFeed},
+	{"nextFeed", &defaultKeyMap.NextFeed},
+	{"jumpToNew", &defaultKeyMap.Fresh},
+	{"refresh", &defaultKeyMap.Refresh},
+	{"refreshAll", &defaultKeyMap.RefreshAll},
+	{"refreshGroup", &defaultKeyMap.RefreshGroup},
+	{"pause", &defaultKeyMap.Pause},
+	{"followMovedFeed", &defaultKeyMap.Move},
+	{"stats", &defaultKeyMap.Stats},
+	{"star", &defaultKeyMap.Star},
+	{"openInBrowser", &defaultKeyMap.Browser},
+	{"fullArticle", &defaultKeyMap.FullText},
+	{"showChanges", &defaultKeyMap.Changes},
+	{"copyLink", &defaultKeyMap.Yank},
+	{"share", &defaultKeyMap.Share},
+	{"capture", &defaultKeyMap.Capture},
+	{"readLater", &defaultKeyMap.ReadLater},
+	{"wayback", &defaultKeyMap.Wayback},
+	{"copyInstallCommand", &defaultKeyMap.Install},
+	{"download", &defaultKeyMap.Download},
+	{"play", &defaultKeyMap.Play},
+	{"selectLinks", &defaultKeyMap.Links},
+	{"open", &defaultKeyMap.Open},
+	{"subscribeToLink", &defaultKeyMap.Subscribe},
+	{"back", &defaultKeyMap.Back},
+	{"manageSubscriptions", &defaultKeyMap.Manage},
+	{"setMark", &defaultKeyMap.Mark},
+	{"jumpToMark", &defaultKeyMap.GotoMark},
+	{"toggleRead", &defaultKeyMap.ToggleRead},
+	{"markAllRead", &defaultKeyMap.MarkAllRead},
+	// what marking a group read on the feed list was called before it was
+	// one with marking a feed read, so configs using it still work
+	{"markGroupRead", &defaultKeyMap.MarkAllRead},
+	{"unreadOnly", &defaultKeyMap.UnreadOnly},
+	{"severeOnly", &defaultKeyMap.SevereOnly},
+	{"palette", &defaultKeyMap.Palette},
+	{"find", &defaultKeyMap.Find},
+	{"search", &defaultKeyMap.Search},
+	{"searchArticle", &defaultKeyMap.SearchIn},
+	{"nextMatch", &defaultKeyMap.NextMatch},
+	{"prevMatch", &defaultKeyMap.PrevMatch},
+	{"help", &defaultKeyMap.Help},
+	{"quit", &defaultKeyMap.Quit},
+	{"addSubscription", &subscriptionKeys.Add},
+	{"renameSubscription", &subscriptionKeys.Rename},
+	{"removeSubscription", &subscriptionKeys.Remove},
+	{"foldGroup", &feedListKeys.Collapse},
+}
+
+// keyScreens are the sets of bindings that are active at the same time, so
+// can't share a key. Bindings on different screens can.
+var keyScreens = []struct {
+	name     string
+	bindings []*key.Binding
+}{
+	{"reading", []*key.Binding{
+		&defaultKeyMap.Up, &defaultKeyMap.Down,
+		&defaultKeyMap.PageUp, &defaultKeyMap.PageDown,
+		&defaultKeyMap.HalfPageUp, &defaultKeyMap.HalfPageDown,
+		&defaultKeyMap.Left, &defaultKeyMap.Right,
+		&defaultKeyMap.FeedList, &defaultKeyMap.ArticleList, &defaultKeyMap.Top,
+		&defaultKeyMap.Author, &defaultKeyMap.Follow, &defaultKeyMap.Language, &defaultKeyMap.PrevFeed, &defaultKeyMap.NextFeed, &defaultKeyMap.Fresh,
+		&defaultKeyMap.Refresh, &defaultKeyMap.RefreshAll, &defaultKeyMap.RefreshGroup,
+		&defaultKeyMap.Pause, &defaultKeyMap.Move, &defaultKeyMap.Stats,
+		&defaultKeyMap.Star, &defaultKeyMap.Browser, &defaultKeyMap.FullText, &defaultKeyMap.Changes,
+		&defaultKeyMap.Yank, &defaultKeyMap.Share, &defaultKeyMap.Capture, &defaultKeyMap.ReadLater, &defaultKeyMap.Wayback, &defaultKeyMap.Install, &defaultKeyMap.Links,
+		&defaultKeyMap.Manage,
+		&defaultKeyMap.Mark, &defaultKeyMap.GotoMark, &defaultKeyMap.Palette,
+		&defaultKeyMap.ToggleRead, &defaultKeyMap.MarkAllRead, &defaultKeyMap.UnreadOnly, &defaultKeyMap.SevereOnly,
+		&defaultKeyMap.Find, &defaultKeyMap.Search, &defaultKeyMap.SearchIn,
+		&defaultKeyMap.NextMatch, &defaultKeyMap.PrevMatch, &defaultKeyMap.Download,
+		&defaultKeyMap.Play, &defaultKeyMap.Help, &defaultKeyMap.Quit,
+	}},
+	{"link selection", []*key.Binding{
+		&defaultKeyMap.Up, &defaultKeyMap.Down, &defaultKeyMap.Yank,
+		&defaultKeyMap.Subscribe, &defaultKeyMap.Open, &defaultKeyMap.Back,
+		&defaultKeyMap.Links, &defaultKeyMap.Help,
+	}},
+	{"feed list", []*key.Binding{
+		&defaultKeyMap.Up, &defaultKeyMap.Down, &defaultKeyMap.Open,
+		&defaultKeyMap.Pause, &defaultKeyMap.Refresh, &defaultKeyMap.FeedList,
+		&defaultKeyMap.Back, &defaultKeyMap.Help, &defaultKeyMap.Quit,
+		&feedListKeys.Collapse, &defaultKeyMap.MarkAllRead,
+	}},
+	{"article list", []*key.Binding{
+		&defaultKeyMap.Open, &defaultKeyMap.Back, &defaultKeyMap.ArticleList,
+		&defaultKeyMap.Top, &defaultKeyMap.UnreadOnly, &defaultKeyMap.SevereOnly, &defaultKeyMap.Follow,
+		&defaultKeyMap.Quit,
+	}},
+	{"subscriptions", []*key.Binding{
+		&defaultKeyMap.Up, &defaultKeyMap.Down,
+		&subscriptionKeys.Add, &subscriptionKeys.Rename, &subscriptionKeys.Remove,
+		&defaultKeyMap.Open, &defaultKeyMap.Manage, &defaultKeyMap.Back,
+		&defaultKeyMap.Quit,
+	}},
+}
+
+// parseKey turns a key as bindings write it, like "j", "ctrl+d", "pgdown" or
+// "alt+enter", into the key press it stands for.
+func parseKey(k string) (tea.KeyMsg, bool) {
+	var msg tea.KeyMsg
+	if strings.HasPrefix(k, "alt+") && len(k) > len("alt+") {
+		msg.Alt = true
+		k = strings.TrimPrefix(k, "alt+")
+	}
+	if utf8.RuneCountInString(k) == 1 {
+		msg.Type = tea.KeyRunes
+		msg.Runes = []rune(k)
+		return msg, true
+	}
+	// bubbletea doesn't export its key names, but every key type knows its
+	// own: the control characters and the special keys just below zero
+	for t := tea.KeyType(-32); t < 128; t++ {
+		if t != tea.KeyRunes && t.String() == k {
+			msg.Type = t
+			return msg, true
+		}
+	}
+	return msg, false
+}
+
+// RemapKeys replaces the keys of the actions named in keys, as read from the
+// config's keys section. An empty list unbinds an action. Unknown actions,
+// keys that don't exist and keys given to two actions on the same screen are
+// errors, unless the two share it out of the box (like esc for back and
+// quit).
+func RemapKeys(keys map[string][]string) error {
+	defaults := make(map[*key.Binding][]string)
+	for _, action := range keyActions {
+		defaults[action.binding] = action.binding.Keys()
+	}
+	byName := make(map[string]keyAction)
+	for _, action := range keyActions {
+		// viper lowercases the names
+		byName[strings.ToLower(action.name)] = action
+	}
+
+	var names []string
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		action, ok := byName[strings.ToLower(name)]
+		if !ok {
+			return fmt.Errorf("keys: no action called %q", name)
+		}
+		var bound []string
+		for _, k := range keys[name] {
+			if k == "space" {
+				// spaces arrive as a rune like any other character
+				k = " "
+			}
+			msg, ok := parseKey(k)
+			if k == "true" || k == "false" {
+				// YAML reads an unquoted y or n as a boolean
+				return fmt.Errorf("keys.%s: %q isn't a key, quote it", action.name, k)
+			} else if !ok || msg.String() != k {
+				return fmt.Errorf("keys.%s: %q isn't a key", action.name, k)
+			}
+			bound = append(bound, k)
+		}
+		if len(bound) == 0 {
+			action.binding.Unbind()
+			continue
+		}
+		action.binding.SetKeys(bound...)
+		action.binding.SetHelp(helpKeys(action.binding, bound), action.binding.Help().Desc)
+	}
+
+	nameOf := make(map[*key.Binding]string)
+	for _, action := range keyActions {
+		// aliases come after the name errors go by
+		if _, ok := nameOf[action.binding]; !ok {
+			nameOf[action.binding] = action.name
+		}
+	}
+	for _, screen := range keyScreens {
+		boundTo := make(map[string]*key.Binding)
+		for _, binding := range screen.bindings {
+			for _, k := range binding.Keys() {
+				other, taken := boundTo[k]
+				if taken && other != binding && !(hasKey(defaults[other], k) && hasKey(defaults[binding], k)) {
+					return fmt.Errorf("keys: %s and %s both use %q in %s", nameOf[other], nameOf[binding], k, screen.name)
+				}
+				boundTo[k] = binding
+			}
+		}
+	}
+	return nil
+}
+
+// helpKeys is how the help view shows a binding's new keys, keeping what's
+// typed after them for the bindings that take a letter.
+func helpKeys(binding *key.Binding, keys []string) string {
+	var suffix string
+	if strings.Contains(binding.Help().Key, "<letter>") {
+		suffix = "<letter>"
+	}
+	var shown []string
+	for _, k := range keys {
+		if k == " " {
+			k = "space"
+		}
+		shown = append(shown, k+suffix)
+	}
+	return strings.Join(shown, "/")
+}
+
+func hasKey(keys []string, k string) bool {
+	for _, bound := range keys {
+		if bound == k {
+			return true
+		}
+	}
+	return false
+}
+
+// scrollKeyMsg is the key the viewport scrolls by for a key press matching one
+// of the scrolling bindings, whatever they've been remapped to, and nil for
+// any other key.
+func scrollKeyMsg(msg tea.KeyMsg) tea.Msg {
+	switch {
+	case key.Matches(msg, defaultKeyMap.Up):
+		return tea.KeyMsg{Type: tea.KeyUp}
+	case key.Matches(msg, defaultKeyMap.Down):
+		return tea.KeyMsg{Type: tea.KeyDown}
+	case key.Matches(msg, defaultKeyMap.PageUp):
+		return tea.KeyMsg{Type: tea.KeyPgUp}
+	case key.Matches(msg, defaultKeyMap.PageDown):
+		return tea.KeyMsg{Type: tea.KeyPgDown}
+	case key.Matches(msg, defaultKeyMap.HalfPageUp):
+		return tea.KeyMsg{Type: tea.KeyCtrlU}
+	case key.Matches(msg, defaultKeyMap.HalfPageDown):
+		return tea.KeyMsg{Type: tea.KeyCtrlD}
+	}
+	return nil
+}
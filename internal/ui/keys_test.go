@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		k    string
+		want tea.KeyMsg
+		ok   bool
+	}{
+		{"j", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")}, true},
+		{"é", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("é")}, true},
+		{" ", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(" ")}, true},
+		{"ctrl+d", tea.KeyMsg{Type: tea.KeyCtrlD}, true},
+		{"pgdown", tea.KeyMsg{Type: tea.KeyPgDown}, true},
+		{"enter", tea.KeyMsg{Type: tea.KeyEnter}, true},
+		{"esc", tea.KeyMsg{Type: tea.KeyEsc}, true},
+		{"alt+j", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j"), Alt: true}, true},
+		{"alt+enter", tea.KeyMsg{Type: tea.KeyEnter, Alt: true}, true},
+		{"alt+", tea.KeyMsg{}, false},
+		{"hyper+j", tea.KeyMsg{}, false},
+		{"jj", tea.KeyMsg{}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.k, func(t *testing.T) {
+			got, ok := parseKey(test.k)
+			if ok != test.ok {
+				t.Fatalf("ok = %v, want %v", ok, test.ok)
+			}
+			if ok && (got.Type != test.want.Type || got.Alt != test.want.Alt || string(got.Runes) != string(test.want.Runes)) {
+				t.Errorf("parseKey(%q) = %#v, want %#v", test.k, got, test.want)
+			}
+			if ok && got.String() != test.k {
+				t.Errorf("parseKey(%q) reads back as %q", test.k, got.String())
+			}
+		})
+	}
+}
+
+func TestRemapKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		keys    map[string][]string
+		wantErr bool
+	}{
+		{"nothing", nil, false},
+		{"new key", map[string][]string{"down": {"v"}}, false},
+		{"names are case-insensitive", map[string][]string{"pagedown": {"ctrl+b", "space"}}, false},
+		{"unbinding", map[string][]string{"star": {}}, false},
+		{"swapping two keys", map[string][]string{"up": {"j"}, "down": {"k"}}, false},
+		{"shared out of the box", map[string][]string{"back": {"esc"}}, false},
+		{"same key on different screens", map[string][]string{"foldGroup": {"s"}}, false},
+		{"old name for marking read", map[string][]string{"markGroupRead": {"G"}}, false},
+		{"marks back on m", map[string][]string{"setMark": {"m"}, "toggleRead": {"v"}}, false},
+		{"unknown action", map[string][]string{"launchRockets": {"r"}}, true},
+		{"unknown key", map[string][]string{"down": {"hyper+j"}}, true},
+		{"unquoted y", map[string][]string{"down": {"true"}}, true},
+		{"taken on the same screen", map[string][]string{"star": {"j"}}, true},
+		{"given to two actions", map[string][]string{"star": {"z"}, "help": {"z"}}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer restoreKeys()()
+			err := RemapKeys(test.keys)
+			if (err != nil) != test.wantErr {
+				t.Errorf("RemapKeys() error = %v, want error %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestRemapKeysRebinds(t *testing.T) {
+	defer restoreKeys()()
+	if err := RemapKeys(map[string][]string{"down": {"v", "ctrl+n"}, "pageDown": {"c", "space"}, "star": {}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := defaultKeyMap.Down.Keys(); len(got) != 2 || got[0] != "v" || got[1] != "ctrl+n" {
+		t.Errorf("down is bound to %q", got)
+	}
+	if got := defaultKeyMap.Down.Help().Key; got != "v/ctrl+n" {
+		t.Errorf("down's help shows %q", got)
+	}
+	if len(defaultKeyMap.Star.Keys()) > 0 {
+		t.Error("star is still bound")
+	}
+	if msg, ok := scrollKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")}).(tea.KeyMsg); !ok || msg.Type != tea.KeyDown {
+		t.Errorf("v scrolls by %v, want down", msg)
+	}
+}
+
+func TestRemapKeysAlias(t *testing.T) {
+	defer restoreKeys()()
+	if err := RemapKeys(map[string][]string{"markGroupRead": {"G"}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := defaultKeyMap.MarkAllRead.Keys(); len(got) != 1 || got[0] != "G" {
+		t.Errorf("markAllRead is bound to %q", got)
+	}
+	err := RemapKeys(map[string][]string{"markGroupRead": {"j"}})
+	if err == nil || !strings.Contains(err.Error(), "markAllRead") {
+		t.Errorf("got %v, want the clash named after markAllRead", err)
+	}
+}
+
+// restoreKeys saves every binding RemapKeys can change, returning a function
+// that puts them back.
+func restoreKeys() func() {
+	saved := make([]keyAction, len(keyActions))
+	for i, action := range keyActions {
+		binding := *action.binding
+		saved[i] = keyAction{action.name, &binding}
+	}
+	return func() {
+		for i, action := range keyActions {
+			*action.binding = *saved[i].binding
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/homielabs/golang-rss-client/internal/fetch"
+	"github.com/mmcdole/gofeed"
+)
+
+// languageRead reports whether an item's in a language that's read in its
+// feed: one that isn't muted, and one of the feed's languages if it has any
+// (or of the languages read everywhere). Items whose language couldn't be
+// told are always read.
+func (m model) languageRead(feedUrl string, item *gofeed.Item) bool {
+	language := item.Custom[fetch.LanguageKey]
+	if language == "" {
+		return true
+	}
+	if m.mutedLanguages[language] {
+		return false
+	}
+	wanted, ok := m.feedLanguages[feedUrl]
+	if !ok {
+		wanted = m.languages
+	}
+	return len(wanted) == 0 || wanted[language]
+}
+
+// hideLanguages drops the items of a fetched feed in languages that aren't
+// read in it, before they're shown or notified about.
+func (m model) hideLanguages(feedUrl string, feed *gofeed.Feed) {
+	kept := feed.Items[:0]
+	for _, item := range feed.Items {
+		if m.languageRead(feedUrl, item) {
+			kept = append(kept, item)
+		}
+	}
+	if hidden := len(feed.Items) - len(kept); hidden > 0 {
+		log.Printf("languages: hid %d items of %s", hidden, feedUrl)
+	}
+	feed.Items = kept
+}
+
+// languageArticles are every loaded item in a language, across the feeds
+// that are fetched.
+func (m model) languageArticles(language string) []articleRef {
+	var refs []articleRef
+	for i, feed := range m.feedSlice {
+		if m.isVirtualFeed(i) {
+			continue
+		}
+		for j, item := range feed.Items {
+			if item.Custom[fetch.LanguageKey] == language {
+				refs = append(refs, articleRef{i, j})
+			}
+		}
+	}
+	return refs
+}
+
+// languageResults lists the articles in a language, each with the feed it's
+// from.
+func (m model) languageResults(language string) []list.Item {
+	var items []list.Item
+	for _, ref := range m.languageArticles(language) {
+		feedTitle := m.feedSlice[ref.feed].Title
+		if feedTitle == "" {
+			feedTitle = m.feedUrls[ref.feed]
+		}
+		items = append(items, m.newArticleItem(ref.feed, ref.index, feedTitle))
+	}
+	return items
+}
+
+// chooseLanguage asks which language to list the articles in, out of the
+// ones the loaded articles are in, most articles first. With only the one
+// there's nothing to ask.
+func (m *model) chooseLanguage() tea.Cmd {
+	counts := make(map[string]int)
+	for i, feed := range m.feedSlice {
+		if m.isVirtualFeed(i) {
+			continue
+		}
+		for _, item := range feed.Items {
+			if language := item.Custom[fetch.LanguageKey]; language != "" {
+				counts[language]++
+			}
+		}
+	}
+	var languages []string
+	for language := range counts {
+		languages = append(languages, language)
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		if counts[languages[i]] != counts[languages[j]] {
+			return counts[languages[i]] > counts[languages[j]]
+		}
+		return languages[i] < languages[j]
+	})
+	switch len(languages) {
+	case 0:
+		return m.setStatus("No article's language could be told")
+	case 1:
+		return m.setStatus(fmt.Sprintf("Every article is in %s", fetch.LanguageName(languages[0])))
+	}
+	var labels []string
+	for _, language := range languages {
+		labels = append(labels, fmt.Sprintf("%s (%s), %d article(s)", fetch.LanguageName(language), language, counts[language]))
+	}
+	m.languageChoices = languages
+	m.picker = newPicker(pickerLanguage, "List articles in which language?", labels)
+	return nil
+}
+
+// languageChosen lists the articles in the language picked.
+func (m *model) languageChosen(chosen int) {
+	m.articleListLanguage = m.languageChoices[chosen]
+	m.openArticleList(articleListLanguage)
+}
@@ -0,0 +1,315 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/homielabs/golang-rss-client/internal/fetch"
+	"github.com/homielabs/golang-rss-client/internal/render"
+)
+
+// maxPreviewBytes caps how much of a linked page we read while looking for
+// its <title>, since the title lives in the <head> anyway.
+const maxPreviewBytes = 512 * 1024
+
+type articleLink struct {
+	text string
+	url  string
+}
+
+type linkPreview struct {
+	title string
+	// feedUrl is the feed the page advertises, if any
+	feedUrl string
+	err     error
+}
+
+type linkPreviewMsg struct {
+	url     string
+	preview linkPreview
+}
+
+// extractLinks pulls every anchor out of an item's HTML, resolving relative
+// hrefs against the item's own link. Duplicate targets are only listed once.
+func extractLinks(content string, base string) []articleLink {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	if err != nil {
+		return nil
+	}
+	baseUrl, _ := url.Parse(base)
+
+	var links []articleLink
+	seen := make(map[string]bool)
+	doc.Find("a[href]").Each(func(_ int, s *goquery.Selection) {
+		href, _ := s.Attr("href")
+		target, err := url.Parse(strings.TrimSpace(href))
+		if err != nil {
+			return
+		}
+		if baseUrl != nil {
+			target = baseUrl.ResolveReference(target)
+		}
+		// skip mailto:, javascript: and in-page anchors
+		if target.Scheme != "http" && target.Scheme != "https" {
+			return
+		}
+		if seen[target.String()] {
+			return
+		}
+		seen[target.String()] = true
+		links = append(links, articleLink{
+			text: render.StripControl(strings.Join(strings.Fields(s.Text()), " ")),
+			url:  target.String(),
+		})
+	})
+	return links
+}
+
+// discoverFeed finds the first feed a page advertises, if any.
+func discoverFeed(doc *goquery.Document, page string) string {
+	if feeds := fetch.DiscoverFeeds(doc, page); len(feeds) > 0 {
+		return feeds[0].URL
+	}
+	return ""
+}
+
+// fetchLinkPreviewCmd fetches the page behind a link and reports its <title>
+// and feed.
+func fetchLinkPreviewCmd(ctx context.Context, link string, timeout time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+		if err != nil {
+			return linkPreviewMsg{url: link, preview: linkPreview{err: err}}
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return linkPreviewMsg{url: link, preview: linkPreview{err: err}}
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return linkPreviewMsg{
+				url:     link,
+				preview: linkPreview{err: fmt.Errorf("%s", resp.Status)},
+			}
+		}
+
+		doc, err := goquery.NewDocumentFromReader(
+			io.LimitReader(resp.Body, maxPreviewBytes),
+		)
+		if err != nil {
+			return linkPreviewMsg{url: link, preview: linkPreview{err: err}}
+		}
+		title := render.StripControl(
+			strings.Join(strings.Fields(doc.Find("title").First().Text()), " "),
+		)
+		return linkPreviewMsg{url: link, preview: linkPreview{
+			title:   title,
+			feedUrl: discoverFeed(doc, resp.Request.URL.String()),
+		}}
+	}
+}
+
+// webURL reports whether a link is an absolute http or https URL. Links come
+// from feeds, so nothing else is opened: the platform's handler would launch
+// whatever a file:, smb: or other URL's scheme is registered to.
+func webURL(link string) bool {
+	u, err := url.Parse(link)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// openURL hands a web link off to the configured browser command, or else
+// the platform's default handler. The URL replaces a %s in the command, or is
+// added to the end of it.
+func (m model) openURL(target string) error {
+	if !webURL(target) {
+		return fmt.Errorf("%q isn't a web link", target)
+	}
+	var cmd *exec.Cmd
+	switch {
+	case m.browserCommand != "":
+		fields := commandLine(m.browserCommand, target)
+		cmd = exec.Command(fields[0], fields[1:]...)
+	case runtime.GOOS == "darwin":
+		cmd = exec.Command("open", target)
+	case runtime.GOOS == "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", target)
+	default:
+		cmd = exec.Command("xdg-open", target)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// reap the browser (or the handler) once it's done, so it doesn't
+	// linger as a zombie
+	go cmd.Wait()
+	return nil
+}
+
+// commandLine splits a configured command line into its arguments, with the
+// target replacing any %s in them or else added last.
+func commandLine(command string, target string) []string {
+	fields := strings.Fields(command)
+	substituted := false
+	for i, field := range fields {
+		if i > 0 && strings.Contains(field, "%s") {
+			fields[i] = strings.ReplaceAll(field, "%s", target)
+			substituted = true
+		}
+	}
+	if !substituted {
+		fields = append(fields, target)
+	}
+	return fields
+}
+
+// openInBrowser opens the article being read.
+func (m *model) openInBrowser() tea.Cmd {
+	if m.feedIndex >= m.feedSlice[m.feedSliceIndex].Len() {
+		return m.setStatus("Nothing to open")
+	}
+	link := m.feedSlice[m.feedSliceIndex].Items[m.feedIndex].Link
+	if link == "" {
+		return m.setStatus("This article has no link")
+	}
+	link = m.openedURL(link)
+	if err := m.openURL(link); err != nil {
+		log.Println(err)
+		return m.setStatus("Couldn't open browser: " + err.Error())
+	}
+	return m.setStatus("Opened " + link)
+}
+
+func assembleLinkPopup(link articleLink, m model) string {
+	domain := ""
+	if u, err := url.Parse(link.url); err == nil {
+		domain = u.Hostname()
+	}
+
+	title := "Fetching title..."
+	if m.lowBandwidth {
+		title = "(not fetched in low-bandwidth mode)"
+	}
+	if preview, ok := m.linkPreviews[link.url]; ok {
+		if preview.err != nil {
+			title = "Couldn't fetch title: " + preview.err.Error()
+		} else if preview.title == "" {
+			title = "(page has no title)"
+		} else {
+			title = preview.title
+		}
+	}
+
+	// only mention subscribing when there's a feed to subscribe to
+	feedUrl := m.linkPreviews[link.url].feedUrl
+	hints := []string{"enter: open in browser"}
+	if feedUrl != "" {
+		hints = append(hints, "a: subscribe")
+	}
+	hints = append(hints, "esc: back to article")
+
+	lines := []string{
+		"Title: " + title,
+		"Domain: " + domain,
+		"URL: " + link.url,
+	}
+	if feedUrl != "" {
+		lines = append(lines, "Feed: "+feedUrl)
+	}
+	lines = append(lines, "", strings.Join(hints, ", "))
+	if m.accessible {
+		return lipgloss.NewStyle().
+			PaddingLeft(m.horzPadding).
+			Width(m.viewport.Width).
+			Render(strings.Join(lines, "\n"))
+	}
+
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(m.feedAccent()))
+	lines = []string{
+		labelStyle.Render("Title  ") + title,
+		labelStyle.Render("Domain ") + domain,
+		labelStyle.Render("URL    ") + link.url,
+	}
+	if feedUrl != "" {
+		lines = append(lines, labelStyle.Render("Feed   ")+feedUrl)
+	}
+	lines = append(lines, "", strings.Join(hints, m.symbol(" • ", " | ")))
+	return lipgloss.NewStyle().
+		Border(m.border(lipgloss.RoundedBorder())).
+		BorderForeground(lipgloss.Color(m.feedAccent())).
+		PaddingLeft(m.horzPadding).
+		PaddingRight(m.horzPadding).
+		// leave room for the border and padding on either side
+		Width(m.viewport.Width - 2).
+		Render(strings.Join(lines, "\n"))
+}
+
+// assembleLinkSelection renders the list of links in the current article with
+// the selected one highlighted, and a preview popup for it underneath.
+func assembleLinkSelection(m model) string {
+	if len(m.links) == 0 {
+		return lipgloss.NewStyle().
+			Height(m.viewport.Height).
+			Render("No links in this article.")
+	}
+
+	popup := assembleLinkPopup(m.links[m.linkIndex], m)
+	listHeight := m.viewport.Height - lipgloss.Height(popup)
+	if listHeight < 1 {
+		listHeight = 1
+	}
+
+	// keep the cursor on screen by scrolling the window along with it
+	start := 0
+	if m.linkIndex >= listHeight {
+		start = m.linkIndex - listHeight + 1
+	}
+	end := start + listHeight
+	if end > len(m.links) {
+		end = len(m.links)
+	}
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Background(lipgloss.Color(m.feedAccent())).
+		Foreground(lipgloss.Color(m.textColor))
+
+	var rows []string
+	for i := start; i < end; i++ {
+		text := m.links[i].text
+		if text == "" {
+			text = m.links[i].url
+		}
+		row := fmt.Sprintf(" %d. %s", i+1, text)
+		if m.accessible {
+			// mark the selection with text rather than color alone
+			marker := "  "
+			if i == m.linkIndex {
+				marker = "> "
+			}
+			row = marker + row
+		} else if i == m.linkIndex {
+			row = selectedStyle.Render(row)
+		}
+		rows = append(rows, row)
+	}
+
+	list := lipgloss.NewStyle().
+		Height(listHeight).
+		MaxWidth(m.viewport.Width).
+		Render(strings.Join(rows, "\n"))
+	return lipgloss.JoinVertical(lipgloss.Left, list, popup)
+}
@@ -0,0 +1,63 @@
+package ui
+
+import "testing"
+
+func TestWebURL(t *testing.T) {
+	tests := []struct {
+		link string
+		want bool
+	}{
+		{"https://example.com/post", true},
+		{"http://example.com", true},
+		{"HTTPS://example.com", true},
+		{"//example.com/post", false},
+		{"/post", false},
+		{"example.com/post", false},
+		{"https:///post", false},
+		{"file:///etc/passwd", false},
+		{"javascript:alert(1)", false},
+		{"mailto:bob@example.com", false},
+		{"-oProxyCommand=evil", false},
+		{"", false},
+	}
+	for _, test := range tests {
+		t.Run(test.link, func(t *testing.T) {
+			if got := webURL(test.link); got != test.want {
+				t.Errorf("webURL(%q) = %v, want %v", test.link, got, test.want)
+			}
+		})
+	}
+}
+
+func TestCommandLine(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+	}{
+		{"firefox", []string{"firefox", "https://x/"}},
+		{"firefox --new-tab", []string{"firefox", "--new-tab", "https://x/"}},
+		{"firefox --new-tab %s --private", []string{"firefox", "--new-tab", "https://x/", "--private"}},
+		{"sh -c open('%s')", []string{"sh", "-c", "open('https://x/')"}},
+		{"%s", []string{"%s", "https://x/"}},
+		{"  lynx   ", []string{"lynx", "https://x/"}},
+	}
+	for _, test := range tests {
+		t.Run(test.command, func(t *testing.T) {
+			if got := commandLine(test.command, "https://x/"); !equalStrings(got, test.want) {
+				t.Errorf("commandLine(%q) = %q, want %q", test.command, got, test.want)
+			}
+		})
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
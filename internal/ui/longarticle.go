@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"log"
+	"strings"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+	"github.com/charmbracelet/glamour"
+	"github.com/homielabs/golang-rss-client/internal/render"
+)
+
+// longArticleLines is how many lines of markdown an article can have before
+// it's styled a chunk at a time as it's scrolled through, rather than all at
+// once.
+const longArticleLines = 1000
+
+// chunkLines is about how many lines of markdown are styled at a time.
+const chunkLines = 200
+
+// lookahead is how many lines past the bottom of the screen are kept styled,
+// so scrolling never has to wait.
+const lookahead = 100
+
+// lazyArticle is a long article being styled as it's scrolled through.
+type lazyArticle struct {
+	// pending are the chunks of markdown not styled yet
+	pending  []string
+	renderer *glamour.TermRenderer
+	// content is the article styled so far, lines long, from doneLines lines
+	// of markdown, with pendingLines lines to go
+	content      string
+	lines        int
+	doneLines    int
+	pendingLines int
+}
+
+// renderArticle renders an item's HTML, leaving the longest articles to be
+// finished off by renderAhead.
+func (m *model) renderArticle(html string, converter *md.Converter, display render.Display) (string, error) {
+	markdown, styled, err := render.Prepare(html, converter, display, m.viewport.Width)
+	if err != nil || !styled {
+		return markdown, err
+	}
+	renderer, err := m.renderer(display.WrapWidth(m.viewport.Width))
+	if err != nil {
+		return "", err
+	}
+	if strings.Count(markdown, "\n") < longArticleLines {
+		return render.Style(markdown, renderer)
+	}
+
+	lazy := &lazyArticle{
+		pending:  render.Chunks(markdown, chunkLines),
+		renderer: renderer,
+	}
+	for _, chunk := range lazy.pending {
+		lazy.pendingLines += strings.Count(chunk, "\n") + 1
+	}
+	m.lazy = lazy
+	lazy.styleUntil(m.viewport.Height + lookahead)
+	return lazy.content, nil
+}
+
+// renderer is the glamour renderer wrapping at width, built the first time
+// it's needed at that width.
+func (m *model) renderer(width int) (*glamour.TermRenderer, error) {
+	if renderer, ok := m.renderers[width]; ok {
+		return renderer, nil
+	}
+	renderer, err := render.NewRenderer(m.glamourStyle(), width)
+	if err != nil {
+		return nil, err
+	}
+	m.renderers[width] = renderer
+	return renderer, nil
+}
+
+// styleUntil styles chunks until there are at least lines lines, or none are
+// left.
+func (a *lazyArticle) styleUntil(lines int) {
+	for len(a.pending) > 0 && a.lines < lines {
+		chunk := a.pending[0]
+		a.pending = a.pending[1:]
+		styled, err := render.Style(chunk, a.renderer)
+		if err != nil {
+			log.Println(err)
+			styled = "Couldn't render this part of the article: " + err.Error() + "\n"
+		}
+		a.content += styled
+		a.lines = strings.Count(a.content, "\n") + 1
+		chunkLines := strings.Count(chunk, "\n") + 1
+		a.doneLines += chunkLines
+		a.pendingLines -= chunkLines
+	}
+}
+
+// renderAhead styles more of a long article once the reader gets near the end
+// of what's been styled so far.
+func (m *model) renderAhead() {
+	if m.lazy == nil {
+		return
+	}
+	if m.lazy.lines >= m.viewport.YOffset+m.viewport.Height+lookahead {
+		return
+	}
+	m.lazy.styleUntil(m.viewport.YOffset + m.viewport.Height + lookahead)
+	yOffset := m.viewport.YOffset
+	m.setContent(m.lazy.content)
+	m.viewport.YOffset = yOffset
+	if m.viewport.PastBottom() {
+		m.viewport.GotoBottom()
+	}
+	if len(m.lazy.pending) == 0 {
+		m.lazy = nil
+	}
+}
+
+// contentLines is how long the article being shown is, or is likely to be
+// once it's all styled.
+func (m model) contentLines(content string) int {
+	if m.lazy == nil || m.lazy.doneLines == 0 {
+		return strings.Count(content, "\n") + 1
+	}
+	return m.lazy.lines + m.lazy.pendingLines*m.lazy.lines/m.lazy.doneLines
+}
+
+// scrollPercent is how far through the article the reader is, counting the
+// parts of a long article that haven't been styled yet.
+func (m model) scrollPercent() float64 {
+	if m.lazy == nil {
+		return m.viewport.ScrollPercent()
+	}
+	scrollable := m.contentLines(m.lazy.content) - m.viewport.Height
+	if scrollable <= 0 {
+		return 1
+	}
+	percent := float64(m.viewport.YOffset) / float64(scrollable)
+	if percent > 1 {
+		percent = 1
+	}
+	return percent
+}
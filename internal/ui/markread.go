@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/homielabs/golang-rss-client/internal/readinglog"
+	"github.com/homielabs/golang-rss-client/internal/store"
+	"github.com/mmcdole/gofeed"
+)
+
+type markReadMsg struct {
+	// key is the ItemKey of the item that was showing when the timer started
+	key string
+}
+
+// markReadCmd waits out the time an item has to be on screen to count as
+// read.
+func markReadCmd(key string, after time.Duration) tea.Cmd {
+	return tea.Tick(after, func(time.Time) tea.Msg {
+		return markReadMsg{key: key}
+	})
+}
+
+// marksReadLater reports whether items wait to be scrolled through or looked
+// at for a while before they count as read, rather than as soon as they're
+// shown.
+func (m model) marksReadLater() bool {
+	return m.markReadOnScroll || m.markReadAfter > 0
+}
+
+// markRead records an item as read, saving the read state if that's news and
+// adding it to the reading log.
+func (m model) markRead(item *gofeed.Item) {
+	if store.ItemKey(item) == m.keptUnread {
+		return
+	}
+	if m.readState.MarkRead(item) {
+		if err := m.readState.Save(); err != nil {
+			log.Println(err)
+		}
+		m.logReading("Read", item)
+	}
+}
+
+// toggleRead marks the article being read read, or unread again.
+func (m *model) toggleRead() tea.Cmd {
+	feed := m.feedSlice[m.feedSliceIndex]
+	if m.feedIndex >= feed.Len() {
+		return m.setStatus("Nothing to mark")
+	}
+	item := feed.Items[m.feedIndex]
+	if !m.readState.IsRead(item) {
+		m.keptUnread = ""
+		m.markRead(item)
+		return m.setStatus("Marked read")
+	}
+	m.readState.MarkUnread(item)
+	m.keptUnread = store.ItemKey(item)
+	if err := m.readState.Save(); err != nil {
+		log.Println(err)
+	}
+	return m.setStatus("Marked unread")
+}
+
+// markFeedsRead marks every item of the feeds read, name being what they go
+// by in the status.
+func (m *model) markFeedsRead(name string, indices []int) tea.Cmd {
+	marked := 0
+	for _, i := range indices {
+		for _, item := range m.feedSlice[i].Items {
+			if m.readState.MarkRead(item) {
+				marked++
+			}
+		}
+		delete(m.freshItems, i)
+	}
+	m.keptUnread = ""
+	if marked > 0 {
+		if err := m.readState.Save(); err != nil {
+			log.Println(err)
+		}
+	}
+	return m.setStatus(fmt.Sprintf("Marked %d article(s) in %s read", marked, name))
+}
+
+type readingLogCommittedMsg struct {
+	err error
+}
+
+// commitReadingLogCmd commits the reading log in the background, since git
+// may take a while about it, pushing especially.
+func commitReadingLogCmd(readingLog *readinglog.Log) tea.Cmd {
+	return func() tea.Msg {
+		return readingLogCommittedMsg{err: readingLog.Commit()}
+	}
+}
+
+// logReading adds something done with an item of the feed being read to the
+// reading log.
+func (m model) logReading(action string, item *gofeed.Item) {
+	m.readingLog.Add(readinglog.Entry{
+		Action: action,
+		Title:  item.Title,
+		Link:   item.Link,
+		Feed:   m.feedSlice[m.feedSliceIndex].Title,
+		At:     time.Now().In(m.timezone),
+	})
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"fmt"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/homielabs/golang-rss-client/internal/store"
+)
+
+// mark is a remembered spot: an article and how far down it was scrolled.
+type mark struct {
+	feedUrl string
+	item    string
+	yOffset int
+}
+
+// lastJump is the mark for where the reader was before jumping to a mark, so
+// ” goes back there as in vim.
+const lastJump = '\''
+
+// currentMark is a mark for the spot being read.
+func (m model) currentMark() (mark, bool) {
+	feed := m.feedSlice[m.feedSliceIndex]
+	if m.feedIndex >= feed.Len() {
+		return mark{}, false
+	}
+	return mark{
+		feedUrl: m.feedUrls[m.feedSliceIndex],
+		item:    store.ItemKey(feed.Items[m.feedIndex]),
+		yOffset: m.viewport.YOffset,
+	}, true
+}
+
+// updateMark finishes a mark command once the letter naming the mark has
+// been typed after m or '.
+func (m model) updateMark(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	command := m.pendingMark
+	m.pendingMark = 0
+	if msg.Type != tea.KeyRunes || len(msg.Runes) != 1 {
+		return m, nil
+	}
+	name := msg.Runes[0]
+	if name != lastJump && !unicode.IsLetter(name) {
+		cmd := m.setStatus(fmt.Sprintf("Marks are named with letters, not %q", name))
+		return m, cmd
+	}
+
+	if command == 'm' {
+		spot, ok := m.currentMark()
+		if !ok {
+			cmd := m.setStatus("Nothing here to mark")
+			return m, cmd
+		}
+		m.marks[name] = spot
+		cmd := m.setStatus(fmt.Sprintf("Mark %c set", name))
+		return m, cmd
+	}
+	return m.jumpToMark(name)
+}
+
+// jumpToMark goes back to a marked spot, if its article is still loaded.
+func (m model) jumpToMark(name rune) (tea.Model, tea.Cmd) {
+	spot, ok := m.marks[name]
+	if !ok {
+		cmd := m.setStatus(fmt.Sprintf("Mark %c isn't set", name))
+		return m, cmd
+	}
+	for i, feedUrl := range m.feedUrls {
+		if feedUrl != spot.feedUrl {
+			continue
+		}
+		for j, item := range m.feedSlice[i].Items {
+			if store.ItemKey(item) != spot.item {
+				continue
+			}
+			if here, ok := m.currentMark(); ok {
+				m.marks[lastJump] = here
+			}
+			m.feedSliceIndex = i
+			m.feedIndex = j
+			return m.Update(rerenderMsg{scroll: true, yOffset: spot.yOffset})
+		}
+	}
+	cmd := m.setStatus(fmt.Sprintf("Mark %c's article is no longer loaded", name))
+	return m, cmd
+}
@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"log"
+	"reflect"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/homielabs/golang-rss-client/internal/config"
+)
+
+// feedMoved handles a feed that answered with a permanent redirect, either
+// switching it over to its new URL straight away or offering to.
+func (m *model) feedMoved(index int, newUrl string) tea.Cmd {
+	oldUrl := m.feedUrls[index]
+	if m.updateMovedFeeds {
+		if err := m.moveFeed(index, newUrl); err != nil {
+			return m.setStatus("Couldn't update moved feed: " + err.Error())
+		}
+		// the polling loop for the old URL stops by itself
+		return tea.Batch(m.scheduleRefresh(index), m.setStatus("Feed moved, now following "+newUrl))
+	}
+	if m.movedFeeds[oldUrl] == newUrl {
+		// already offered
+		return nil
+	}
+	m.movedFeeds[oldUrl] = newUrl
+	return m.setStatus("Feed moved to " + newUrl + ", press M to update")
+}
+
+// moveFeed points a feed at its new URL, here and in the config.
+func (m *model) moveFeed(index int, newUrl string) error {
+	oldUrl := m.feedUrls[index]
+	if err := config.ReplaceFeedURL(oldUrl, newUrl); err != nil {
+		return err
+	}
+	log.Printf("feed moved: %s -> %s", oldUrl, newUrl)
+
+	m.feedUrls[index] = newUrl
+	m.rekeyFeed(oldUrl, newUrl)
+	return nil
+}
+
+// rekeyFeed moves everything the reader keeps by a feed's URL over to a new
+// one, or drops it if newUrl is empty, the way config.ReplaceFeedURL and
+// config.RemoveFeedURL do in the config: its place in the lists and groups,
+// its title and color, its notification override and its other settings,
+// and what the fetcher keeps for it.
+func (m *model) rekeyFeed(oldUrl string, newUrl string) {
+	// fetches running in the background read the fetcher's, so it sees to
+	// its own
+	m.fetcher.MoveFeed(oldUrl, newUrl)
+	for _, byUrl := range []interface{}{
+		m.pausedFeeds, m.notifyFeeds, m.priorityFeeds, m.notifyOverrides,
+		m.feedTitles, m.feedColors, m.installCommands, m.feedConverters,
+		m.feedDisplays, m.feedLanguages, m.expireAfter,
+	} {
+		rekey(byUrl, oldUrl, newUrl)
+	}
+	for name, members := range m.groups {
+		var kept []string
+		for _, member := range members {
+			if member == oldUrl {
+				member = newUrl
+			}
+			if member != "" {
+				kept = append(kept, member)
+			}
+		}
+		m.groups[name] = kept
+	}
+	delete(m.movedFeeds, oldUrl)
+}
+
+// rekey moves the entry for oldUrl in a map keyed by feed URL, whatever it
+// holds, over to newUrl, or deletes it if newUrl is empty.
+func rekey(byUrl interface{}, oldUrl string, newUrl string) {
+	v := reflect.ValueOf(byUrl)
+	old := reflect.ValueOf(oldUrl)
+	value := v.MapIndex(old)
+	if !value.IsValid() {
+		return
+	}
+	v.SetMapIndex(old, reflect.Value{})
+	if newUrl != "" {
+		v.SetMapIndex(reflect.ValueOf(newUrl), value)
+	}
+}
+
+// updateMovedFeed accepts the offer to switch a moved feed to its new URL.
+func (m *model) updateMovedFeed(index int) tea.Cmd {
+	newUrl, ok := m.movedFeeds[m.feedUrls[index]]
+	if !ok {
+		return m.setStatus("This feed hasn't moved")
+	}
+	if err := m.moveFeed(index, newUrl); err != nil {
+		log.Println(err)
+		return m.setStatus("Couldn't update feed: " + err.Error())
+	}
+	return tea.Batch(m.scheduleRefresh(index), m.setStatus("Now following "+newUrl))
+}
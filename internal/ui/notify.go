@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/homielabs/golang-rss-client/internal/fetch"
+	"github.com/homielabs/golang-rss-client/internal/push"
+	"github.com/homielabs/golang-rss-client/internal/store"
+	"github.com/mmcdole/gofeed"
+)
+
+// newItems returns the items in next that weren't present in prev.
+func newItems(prev gofeed.Feed, next gofeed.Feed) []*gofeed.Item {
+	seen := make(map[string]bool)
+	for _, item := range prev.Items {
+		seen[store.ItemKey(item)] = true
+	}
+
+	var fresh []*gofeed.Item
+	for _, item := range next.Items {
+		if !seen[store.ItemKey(item)] {
+			fresh = append(fresh, item)
+		}
+	}
+	return fresh
+}
+
+// windowsToast shows a toast notification from PowerShell, with the title and
+// text it's given in the environment, so neither needs quoting.
+const windowsToast = `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$xml = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $xml.GetElementsByTagName('text')
+$text.Item(0).AppendChild($xml.CreateTextNode($env:RSS_NOTIFY_TITLE)) > $null
+$text.Item(1).AppendChild($xml.CreateTextNode($env:RSS_NOTIFY_BODY)) > $null
+$toast = [Windows.UI.Notifications.ToastNotification]::new($xml)
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe').Show($toast)`
+
+// sendDesktopNotification pops up a notification using whatever the platform
+// provides: terminal-notifier, if it's installed, or else osascript on macOS,
+// notify-send on Linux and the BSDs, and a toast on Windows.
+func sendDesktopNotification(title string, body string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		if _, err := exec.LookPath("terminal-notifier"); err == nil {
+			cmd = exec.Command("terminal-notifier", "-title", title, "-message", body, "-group", "golang-rss-client")
+			break
+		}
+		cmd = exec.Command(
+			"osascript", "-e",
+			fmt.Sprintf("display notification %q with title %q", body, title),
+		)
+	case "linux", "freebsd", "openbsd", "netbsd":
+		cmd = exec.Command("notify-send", "--app-name=golang-rss-client", title, body)
+	case "windows":
+		cmd = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", windowsToast)
+		cmd.Env = append(os.Environ(), "RSS_NOTIFY_TITLE="+title, "RSS_NOTIFY_BODY="+body)
+	default:
+		return fmt.Errorf("desktop notifications aren't supported on %s", runtime.GOOS)
+	}
+	return cmd.Run()
+}
+
+// maxPushes is how many new items of a feed are notified about one by one;
+// more than that at once are notified about in a single summary instead.
+const maxPushes = 5
+
+// pushMessages are the notifications for a feed's new items.
+func pushMessages(feedTitle string, items []*gofeed.Item) []push.Message {
+	if len(items) > maxPushes {
+		var titles []string
+		for _, item := range items[:maxPushes] {
+			titles = append(titles, item.Title)
+		}
+		return []push.Message{{
+			Title: feedTitle,
+			Body:  fmt.Sprintf("%d new items: %s, ...", len(items), strings.Join(titles, ", ")),
+		}}
+	}
+	var messages []push.Message
+	for _, item := range items {
+		messages = append(messages, push.Message{Title: feedTitle, Body: item.Title, Link: item.Link})
+	}
+	return messages
+}
+
+// notifyNewItemsCmd sends a desktop notification per new item, unless
+// they're turned off, and pushes them to every push target. It never
+// produces a message; failures only end up in the log.
+func (m model) notifyNewItemsCmd(feedTitle string, items []*gofeed.Item) tea.Cmd {
+	ctx, desktop, targets := m.ctx, m.desktopNotifications, m.pushTargets
+	return func() tea.Msg {
+		messages := pushMessages(feedTitle, items)
+		if desktop {
+			for _, msg := range messages {
+				if err := sendDesktopNotification(msg.Title, msg.Body); err != nil {
+					log.Println(err)
+					break
+				}
+			}
+		}
+		for _, target := range targets {
+			for _, msg := range messages {
+				if err := push.Send(ctx, target, msg); err != nil {
+					log.Println(err)
+					break
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// shouldNotify reports whether new items in a feed should trigger
+// notifications. Feeds listed in notifyFeeds do, and every feed does with
+// notifications enabled, unless a feed's own setting says otherwise; never
+// during quiet hours. Everything else accumulates quietly.
+func (m model) shouldNotify(index int) bool {
+	if m.isQuiet() {
+		return false
+	}
+	feedUrl := m.feedUrls[index]
+	if notify, ok := m.notifyOverrides[feedUrl]; ok {
+		return notify
+	}
+	return m.notifyFeeds[feedUrl] || m.notifyAll
+}
+
+// notableItems are the new items of a feed that are notified about even
+// when the feed's others aren't: incidents opening on a status page, and
+// items scoring at least notifications.minScore.
+func (m model) notableItems(index int, feed *gofeed.Feed, items []*gofeed.Item) []*gofeed.Item {
+	if !m.notifiesIncidents(index) {
+		return nil
+	}
+	var notable []*gofeed.Item
+	for _, item := range items {
+		if fetch.IncidentOpen(item) || m.scoresHighEnough(index, feed, item) {
+			notable = append(notable, item)
+		}
+	}
+	return notable
+}
+
+// scoresHighEnough reports whether an item's score reaches
+// notifications.minScore, if that's set and there are rules to score it by.
+func (m model) scoresHighEnough(index int, feed *gofeed.Feed, item *gofeed.Item) bool {
+	if !m.notifyOnScore || m.score == nil {
+		return false
+	}
+	return m.score(m.feedUrls[index], feed, item) >= m.notifyMinScore
+}
@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/homielabs/golang-rss-client/internal/fetch"
+	"github.com/mmcdole/gofeed"
+)
+
+func TestNotableItems(t *testing.T) {
+	byTitle := func(feedUrl string, feed *gofeed.Feed, item *gofeed.Item) float64 {
+		if item.Title == "release" {
+			return 10
+		}
+		return 0
+	}
+	feed := &gofeed.Feed{Items: []*gofeed.Item{
+		{GUID: "release", Title: "release"},
+		{GUID: "post", Title: "post"},
+		{GUID: "incident", Title: "outage", Custom: map[string]string{fetch.IncidentKey: "investigating"}},
+	}}
+	tests := []struct {
+		name      string
+		score     ScoreFunc
+		minScore  float64
+		onScore   bool
+		overrides map[string]bool
+		want      []string
+	}{
+		{"no threshold", byTitle, 0, false, nil, []string{"incident"}},
+		{"at the threshold", byTitle, 10, true, nil, []string{"release", "incident"}},
+		{"above every score", byTitle, 11, true, nil, []string{"incident"}},
+		{"no score rules", nil, 10, true, nil, []string{"incident"}},
+		{"feed turned off", byTitle, 10, true, map[string]bool{"https://example.com/feed": false}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := model{
+				feedUrls:        []string{"https://example.com/feed"},
+				score:           test.score,
+				notifyMinScore:  test.minScore,
+				notifyOnScore:   test.onScore,
+				notifyOverrides: test.overrides,
+			}
+			var got []string
+			for _, item := range m.notableItems(0, feed, feed.Items) {
+				got = append(got, item.GUID)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// paletteActions are the actions offered in the command palette: every key
+// binding that does something while reading.
+func paletteActions() []key.Binding {
+	// by description, as keys can mean different things in different places
+	skip := map[string]bool{
+		// open, subscribe and back only mean something while selecting links
+		defaultKeyMap.Open.Help().Desc:      true,
+		defaultKeyMap.Subscribe.Help().Desc: true,
+		defaultKeyMap.Back.Help().Desc:      true,
+		defaultKeyMap.Palette.Help().Desc:   true,
+		defaultKeyMap.Find.Help().Desc:      true,
+	}
+	var actions []key.Binding
+	for _, column := range defaultKeyMap.FullHelp() {
+		for _, binding := range column {
+			// unbound in the config, so there's no key to press
+			if !binding.Enabled() {
+				continue
+			}
+			if !skip[binding.Help().Desc] {
+				actions = append(actions, binding)
+			}
+		}
+	}
+	return actions
+}
+
+// keyMsgFor builds the key press for a binding's key, so choosing an action
+// from the palette does exactly what pressing its key would.
+func keyMsgFor(k string) tea.KeyMsg {
+	msg, _ := parseKey(k)
+	return msg
+}
+
+// openPalette shows the command palette with every action listed.
+func (m *model) openPalette() {
+	var labels []string
+	for _, action := range paletteActions() {
+		labels = append(labels, fmt.Sprintf("%-30s %s", action.Help().Desc, action.Help().Key))
+	}
+	m.picker = newPicker(pickerPalette, "type to filter actions", labels)
+}
+
+// runAction does whatever the chosen palette action's key does.
+func (m model) runAction(chosen int) (tea.Model, tea.Cmd) {
+	action := paletteActions()[chosen]
+	return m.Update(keyMsgFor(action.Keys()[0]))
+}
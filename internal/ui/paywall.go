@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/homielabs/golang-rss-client/internal/fetch"
+	"github.com/mmcdole/gofeed"
+)
+
+// paywalledLink reports whether a link goes to one of the paywalled domains,
+// or to somewhere under one.
+func (m model) paywalledLink(link string) bool {
+	u, err := url.Parse(link)
+	if err != nil || len(m.paywallDomains) == 0 {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	for host != "" {
+		if m.paywallDomains[host] {
+			return true
+		}
+		dot := strings.IndexByte(host, '.')
+		if dot < 0 {
+			break
+		}
+		host = host[dot+1:]
+	}
+	return false
+}
+
+// paywalled reports whether an item's page, or the page a linkblog item is
+// about, is behind a paywall.
+func (m model) paywalled(item *gofeed.Item) bool {
+	return m.paywalledLink(item.Link) || m.paywalledLink(item.Custom[fetch.ExternalURLKey])
+}
+
+// openedURL is where a link's opened: through the archive service, if it's
+// paywalled and there's one.
+func (m model) openedURL(link string) string {
+	if m.paywallArchiveURL == "" || !m.paywalledLink(link) {
+		return link
+	}
+	return strings.ReplaceAll(m.paywallArchiveURL, "{{url}}", link)
+}
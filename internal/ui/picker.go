@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type pickerKind int
+
+const (
+	pickerClosed pickerKind = iota
+	pickerPalette
+	pickerFinder
+	pickerGroup
+	pickerDiscovered
+	pickerShare
+	pickerCapture
+	pickerAuthor
+	pickerFollow
+	pickerLanguage
+	pickerWayback
+)
+
+// picker is a filter input over a list of choices, narrowed down fuzzily as
+// the user types. It backs the command palette and the finder.
+type picker struct {
+	kind    pickerKind
+	input   textinput.Model
+	labels  []string
+	matches []int
+	index   int
+}
+
+func newPicker(kind pickerKind, placeholder string, labels []string) picker {
+	p := picker{kind: kind, labels: labels}
+	p.input = textinput.NewModel()
+	p.input.Prompt = "> "
+	p.input.Placeholder = placeholder
+	p.input.SetCursorMode(textinput.CursorStatic)
+	p.input.Focus()
+	p.filter()
+	return p
+}
+
+func (p *picker) filter() {
+	p.matches = fuzzyFilter(p.input.Value(), p.labels)
+	p.index = 0
+}
+
+// updatePicker moves through, narrows down or picks from the open picker.
+func (m model) updatePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.picker.kind = pickerClosed
+		return m, nil
+	case tea.KeyCtrlC:
+		return m, m.quit()
+	case tea.KeyUp, tea.KeyCtrlP:
+		if m.picker.index > 0 {
+			m.picker.index--
+		}
+		return m, nil
+	case tea.KeyDown, tea.KeyCtrlN:
+		if m.picker.index < len(m.picker.matches)-1 {
+			m.picker.index++
+		}
+		return m, nil
+	case tea.KeyEnter:
+		kind := m.picker.kind
+		m.picker.kind = pickerClosed
+		if len(m.picker.matches) == 0 {
+			return m, nil
+		}
+		chosen := m.picker.matches[m.picker.index]
+		switch kind {
+		case pickerPalette:
+			return m.runAction(chosen)
+		case pickerFinder:
+			return m.jumpTo(chosen)
+		case pickerGroup:
+			return m.refreshGroup(chosen)
+		case pickerDiscovered:
+			cmd := m.checkFeed(m.subscriptions.discovered[chosen].URL)
+			return m, cmd
+		case pickerShare:
+			cmd := m.shareTo(chosen)
+			return m, cmd
+		case pickerCapture:
+			cmd := m.captureTo(m.capture.Targets()[chosen])
+			return m, cmd
+		case pickerAuthor, pickerFollow:
+			cmd := m.authorChosen(kind, m.picker.labels[chosen])
+			return m, cmd
+		case pickerLanguage:
+			m.languageChosen(chosen)
+			return m, nil
+		case pickerWayback:
+			cmd := m.waybackChosen(chosen)
+			return m, cmd
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.picker.input, cmd = m.picker.input.Update(msg)
+	m.picker.filter()
+	return m, cmd
+}
+
+// assemblePicker renders the filter input with the matching choices under
+// it, the selected one highlighted.
+func assemblePicker(m model) string {
+	p := m.picker
+	listHeight := m.viewport.Height - 2
+	if listHeight < 1 {
+		listHeight = 1
+	}
+
+	start := 0
+	if p.index >= listHeight {
+		start = p.index - listHeight + 1
+	}
+	end := start + listHeight
+	if end > len(p.matches) {
+		end = len(p.matches)
+	}
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Background(lipgloss.Color(m.feedAccent())).
+		Foreground(lipgloss.Color(m.textColor))
+
+	var rows []string
+	for i := start; i < end; i++ {
+		row := " " + p.labels[p.matches[i]]
+		if m.accessible {
+			marker := "  "
+			if i == p.index {
+				marker = "> "
+			}
+			row = marker + row
+		} else if i == p.index {
+			row = selectedStyle.Render(row)
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		rows = append(rows, " No matches.")
+	}
+
+	return lipgloss.NewStyle().
+		Height(m.viewport.Height).
+		MaxHeight(m.viewport.Height).
+		MaxWidth(m.viewport.Width).
+		PaddingLeft(m.horzPadding).
+		Render(p.input.View() + "\n\n" + strings.Join(rows, "\n"))
+}
@@ -0,0 +1,336 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/homielabs/golang-rss-client/internal/fetch"
+	"github.com/mmcdole/gofeed"
+)
+
+// downloadTick is how often the progress of downloads is redrawn.
+const downloadTick = 250 * time.Millisecond
+
+// progressWidth is how wide the progress bar of a download is.
+const progressWidth = 20
+
+// download is an enclosure being downloaded. done and total are updated from
+// the download's goroutine, so they're read and written atomically; total is
+// 0 while it isn't known.
+type download struct {
+	done  int64
+	total int64
+	url   string
+	name  string
+}
+
+type downloadedMsg struct {
+	url  string
+	path string
+	err  error
+}
+
+type downloadTickMsg struct{}
+
+// mediaEnclosure is the enclosure an item's audio or video is in, or its
+// first enclosure if none of them say they're either.
+func mediaEnclosure(item *gofeed.Item) *gofeed.Enclosure {
+	for _, enclosure := range item.Enclosures {
+		if isMedia(enclosure.Type) && enclosure.URL != "" {
+			return enclosure
+		}
+	}
+	for _, enclosure := range item.Enclosures {
+		if enclosure.URL != "" {
+			return enclosure
+		}
+	}
+	return nil
+}
+
+func isMedia(mimeType string) bool {
+	return strings.HasPrefix(mimeType, "audio/") || strings.HasPrefix(mimeType, "video/")
+}
+
+// itemDuration is how long an item's enclosure plays for in seconds, from its
+// JSON Feed attachment or its iTunes tags, 0 if it isn't known.
+func itemDuration(item *gofeed.Item) int {
+	if seconds, err := strconv.Atoi(item.Custom[fetch.DurationKey]); err == nil {
+		return seconds
+	}
+	if item.ITunesExt == nil {
+		return 0
+	}
+	// iTunes durations are seconds, or [hh:]mm:ss
+	seconds := 0
+	for _, part := range strings.Split(strings.TrimSpace(item.ITunesExt.Duration), ":") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return 0
+		}
+		seconds = seconds*60 + n
+	}
+	return seconds
+}
+
+// downloadFilename is the name an enclosure is saved under: the last part
+// of its URL's path, made safe for the filesystem.
+func downloadFilename(link string) string {
+	name := ""
+	// the path is unescaped already, and a bare / or . names nothing
+	if u, err := url.Parse(link); err == nil && strings.Trim(u.Path, "/") != "" {
+		name = path.Base(u.Path)
+	}
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`\/:*?"<>|`, r) || r < ' ' {
+			return '_'
+		}
+		return r
+	}, name)
+	name = strings.Trim(name, ". ")
+	if name == "" {
+		return "enclosure"
+	}
+	return name
+}
+
+// downloadCmd downloads an enclosure into dir, to a .part file that's renamed
+// when it's complete, so a failed download doesn't pass for a finished one.
+// A name that's already taken is numbered. It goes over the transport the
+// feed's fetched over, through the same proxies, and the fetch timeout is how
+// long the server has to answer in; the download itself takes as long as it
+// takes.
+func downloadCmd(ctx context.Context, d *download, dir string, transport http.RoundTripper, timeout time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		fail := func(err error) tea.Msg {
+			return downloadedMsg{url: d.url, err: err}
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fail(err)
+		}
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.url, nil)
+		if err != nil {
+			return fail(err)
+		}
+		req.Header.Set("User-Agent", "golang-rss-client")
+		var unanswered *time.Timer
+		if timeout > 0 {
+			unanswered = time.AfterFunc(timeout, cancel)
+		}
+		resp, err := (&http.Client{Transport: transport}).Do(req)
+		if err != nil {
+			return fail(err)
+		}
+		defer resp.Body.Close()
+		if unanswered != nil && !unanswered.Stop() {
+			return fail(fmt.Errorf("no answer within %s", timeout))
+		}
+		if resp.StatusCode != http.StatusOK {
+			return fail(fmt.Errorf("%s", resp.Status))
+		}
+		if resp.ContentLength > 0 {
+			atomic.StoreInt64(&d.total, resp.ContentLength)
+		}
+
+		ext := filepath.Ext(d.name)
+		target := filepath.Join(dir, d.name)
+		for n := 2; ; n++ {
+			if _, err := os.Stat(target); os.IsNotExist(err) {
+				break
+			}
+			target = filepath.Join(dir, fmt.Sprintf("%s %d%s", strings.TrimSuffix(d.name, ext), n, ext))
+		}
+		part, err := os.Create(target + ".part")
+		if err != nil {
+			return fail(err)
+		}
+		_, err = io.Copy(part, io.TeeReader(resp.Body, progressWriter{d}))
+		if closeErr := part.Close(); err == nil {
+			err = closeErr
+		}
+		if err == nil {
+			err = os.Rename(target+".part", target)
+		}
+		if err != nil {
+			os.Remove(target + ".part")
+			return fail(err)
+		}
+		return downloadedMsg{url: d.url, path: target}
+	}
+}
+
+// progressWriter counts what's been written towards a download.
+type progressWriter struct {
+	d *download
+}
+
+func (w progressWriter) Write(p []byte) (int, error) {
+	atomic.AddInt64(&w.d.done, int64(len(p)))
+	return len(p), nil
+}
+
+func downloadTickCmd() tea.Cmd {
+	return tea.Tick(downloadTick, func(time.Time) tea.Msg {
+		return downloadTickMsg{}
+	})
+}
+
+// downloadEnclosure starts downloading the enclosure of the article being
+// read into the download directory.
+func (m *model) downloadEnclosure() tea.Cmd {
+	feed := m.feedSlice[m.feedSliceIndex]
+	if m.feedIndex >= feed.Len() {
+		return m.setStatus("Nothing to download")
+	}
+	enclosure := mediaEnclosure(feed.Items[m.feedIndex])
+	if enclosure == nil {
+		return m.setStatus("This article has no enclosure")
+	}
+	if !webURL(enclosure.URL) {
+		return m.setStatus("Not downloading " + enclosure.URL + ": it isn't a web link")
+	}
+	for _, d := range m.downloads {
+		if d.url == enclosure.URL {
+			return m.setStatus("Already downloading " + d.name)
+		}
+	}
+	if local, ok := m.downloadedFiles[enclosure.URL]; ok {
+		if _, err := os.Stat(local); err == nil {
+			return m.setStatus("Already downloaded to " + local)
+		}
+	}
+	d := &download{url: enclosure.URL, name: downloadFilename(enclosure.URL)}
+	m.downloads = append(m.downloads, d)
+	cmds := []tea.Cmd{downloadCmd(
+		m.ctx, d, m.downloadDir, m.fetcher.Proxy.Transport(m.feedUrls[m.feedSliceIndex]), m.fetcher.Timeout,
+	)}
+	// one tick at a time is enough, however many downloads there are
+	if len(m.downloads) == 1 {
+		cmds = append(cmds, downloadTickCmd())
+	}
+	return tea.Batch(cmds...)
+}
+
+// downloaded reports a finished download.
+func (m *model) downloaded(msg downloadedMsg) tea.Cmd {
+	for i, d := range m.downloads {
+		if d.url == msg.url {
+			m.downloads = append(m.downloads[:i], m.downloads[i+1:]...)
+			break
+		}
+	}
+	if msg.err != nil {
+		log.Println(msg.err)
+		return m.setStatus("Couldn't download: " + msg.err.Error())
+	}
+	m.downloadedFiles[msg.url] = msg.path
+	return m.setStatus("Downloaded " + msg.path)
+}
+
+// downloadBanner shows how the first download is going, with a count of any
+// others, for the breadcrumb bar.
+func (m model) downloadBanner() string {
+	if len(m.downloads) == 0 {
+		return ""
+	}
+	d := m.downloads[0]
+	done, total := atomic.LoadInt64(&d.done), atomic.LoadInt64(&d.total)
+	banner := d.name + " "
+	if total > 0 {
+		fraction := float64(done) / float64(total)
+		if m.accessible {
+			banner += fmt.Sprintf("%.f%%", fraction*100)
+		} else {
+			bar := progress.NewModel(
+				progress.WithSolidFill(m.feedAccent()),
+				progress.WithoutPercentage(),
+				progress.WithWidth(progressWidth),
+			)
+			if m.asciiOnly {
+				bar.Full, bar.Empty = '#', '-'
+			}
+			banner += bar.ViewAs(fraction) + fmt.Sprintf(" %3.f%%", fraction*100)
+		}
+	} else {
+		banner += formatBytes(done)
+	}
+	if more := len(m.downloads) - 1; more > 0 {
+		banner += fmt.Sprintf(" (+%d)", more)
+	}
+	return banner
+}
+
+// playerCommandLine is the player's command line for a target, like
+// commandLine's but with -- ending the player's options before the target
+// when it stands on its own, so no enclosure's URL can pass for one.
+func playerCommandLine(command string, target string) []string {
+	var fields []string
+	substituted := false
+	for i, field := range strings.Fields(command) {
+		switch {
+		case i > 0 && field == "%s":
+			fields = append(fields, "--", target)
+			substituted = true
+		case i > 0 && strings.Contains(field, "%s"):
+			fields = append(fields, strings.ReplaceAll(field, "%s", target))
+			substituted = true
+		default:
+			fields = append(fields, field)
+		}
+	}
+	if !substituted {
+		fields = append(fields, "--", target)
+	}
+	return fields
+}
+
+// playEnclosure starts the player on the enclosure of the article being read,
+// the downloaded copy if there is one.
+func (m *model) playEnclosure() tea.Cmd {
+	feed := m.feedSlice[m.feedSliceIndex]
+	if m.feedIndex >= feed.Len() {
+		return m.setStatus("Nothing to play")
+	}
+	enclosure := mediaEnclosure(feed.Items[m.feedIndex])
+	if enclosure == nil {
+		return m.setStatus("This article has no enclosure")
+	}
+	target := enclosure.URL
+	downloaded := false
+	if local, ok := m.downloadedFiles[target]; ok {
+		if _, err := os.Stat(local); err == nil {
+			target, downloaded = local, true
+		}
+	}
+	if !downloaded && !webURL(target) {
+		return m.setStatus("Not playing " + target + ": it isn't a web link or a downloaded file")
+	}
+	if strings.TrimSpace(m.playerCommand) == "" {
+		return m.setStatus("No player is configured")
+	}
+	fields := playerCommandLine(m.playerCommand, target)
+	cmd := exec.Command(fields[0], fields[1:]...)
+	if err := cmd.Start(); err != nil {
+		log.Println(err)
+		return m.setStatus("Couldn't start the player: " + err.Error())
+	}
+	// reap the player once it's done, so it doesn't linger as a zombie
+	go cmd.Wait()
+	return m.setStatus("Playing in " + filepath.Base(fields[0]))
+}
@@ -0,0 +1,49 @@
+package ui
+
+import "testing"
+
+func TestPlayerCommandLine(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+	}{
+		{"mpv", []string{"mpv", "--", "https://x/a.mp3"}},
+		{"mpv --no-video", []string{"mpv", "--no-video", "--", "https://x/a.mp3"}},
+		{"mpv %s --no-video", []string{"mpv", "--", "https://x/a.mp3", "--no-video"}},
+		{"vlc --url=%s", []string{"vlc", "--url=https://x/a.mp3"}},
+		{"%s", []string{"%s", "--", "https://x/a.mp3"}},
+	}
+	for _, test := range tests {
+		t.Run(test.command, func(t *testing.T) {
+			if got := playerCommandLine(test.command, "https://x/a.mp3"); !equalStrings(got, test.want) {
+				t.Errorf("playerCommandLine(%q) = %q, want %q", test.command, got, test.want)
+			}
+		})
+	}
+}
+
+func TestDownloadFilename(t *testing.T) {
+	tests := []struct {
+		link string
+		want string
+	}{
+		{"https://x/episodes/42.mp3", "42.mp3"},
+		{"https://x/episodes/42.mp3?token=abc#t=10", "42.mp3"},
+		{"https://x/episodes/my%20show%3A%2042.mp3", "my show_ 42.mp3"},
+		{"https://x/a%2F..%2F..%2Fetc%2Fpasswd", "passwd"},
+		{"https://x/100%2525.mp3", "100%25.mp3"},
+		{"https://x/..", "enclosure"},
+		{"https://x/", "enclosure"},
+		{"https://x", "enclosure"},
+		{"https://x/.hidden.", "hidden"},
+		{"https://x/a%0Ab", "a_b"},
+		{"::not a url", "enclosure"},
+	}
+	for _, test := range tests {
+		t.Run(test.link, func(t *testing.T) {
+			if got := downloadFilename(test.link); got != test.want {
+				t.Errorf("downloadFilename(%q) = %q, want %q", test.link, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"log"
+	"strings"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/homielabs/golang-rss-client/internal/render"
+	"github.com/homielabs/golang-rss-client/internal/store"
+)
+
+// prerenderKey identifies an article rendered ahead of time: the item, and
+// the width it was wrapped for.
+type prerenderKey struct {
+	item  string
+	width int
+}
+
+type prerenderedMsg struct {
+	key prerenderKey
+	// content is empty if the article is best left to renderArticle
+	content string
+}
+
+// articleHTML is the HTML an item of a feed is rendered from, and how the
+// feed wants it displayed. That's what changed in it instead, when that's
+// asked for, or the full article once it's been fetched. GitHub releases are
+// laid out as releases.
+func (m model) articleHTML(feedIndex int, itemIndex int) (string, render.Display) {
+	item := m.feedSlice[feedIndex].Items[itemIndex]
+	display := m.feedDisplays[m.feedUrls[feedIndex]]
+	if m.showingChanges[store.ItemKey(item)] {
+		if changes, ok := m.changesHTML(item, display); ok {
+			return changes, display
+		}
+	}
+	var content string
+	if full, ok := m.fullText[store.ItemKey(item)]; ok {
+		content = full
+	} else if display.DescriptionOnly {
+		content = item.Description
+	} else {
+		// inject a <hr> so the HTML -> MD converter will render the break
+		content = item.Description + "<hr>" + item.Content
+	}
+	if version, ok := render.ReleaseVersion(item.Link); ok {
+		content = render.Release(version, content)
+	}
+	return content, display
+}
+
+// prerenderCmd renders an article in the background, with a renderer of its
+// own since glamour's can't be shared between goroutines. Long articles are
+// skipped, as they're only styled a chunk at a time anyway.
+func prerenderCmd(key prerenderKey, html string, converter *md.Converter, style string, display render.Display) tea.Cmd {
+	return func() tea.Msg {
+		content, styled, err := render.Prepare(html, converter, display, key.width)
+		if err != nil {
+			log.Println(err)
+			return prerenderedMsg{key: key}
+		}
+		if !styled {
+			return prerenderedMsg{key: key, content: content}
+		}
+		if strings.Count(content, "\n") >= longArticleLines {
+			return prerenderedMsg{key: key}
+		}
+		renderer, err := render.NewRenderer(style, display.WrapWidth(key.width))
+		if err == nil {
+			content, err = render.Style(content, renderer)
+		}
+		if err != nil {
+			log.Println(err)
+			return prerenderedMsg{key: key}
+		}
+		return prerenderedMsg{key: key, content: content}
+	}
+}
+
+// prerenderNeighbours renders the articles either side of the one being read
+// ahead of time, so moving to them is instant. Anything rendered for articles
+// further away is dropped.
+func (m model) prerenderNeighbours() tea.Cmd {
+	feed := m.feedSlice[m.feedSliceIndex]
+	keep := make(map[prerenderKey]bool)
+	var cmds []tea.Cmd
+	for _, index := range []int{m.feedIndex - 1, m.feedIndex, m.feedIndex + 1} {
+		if index < 0 || index >= feed.Len() {
+			continue
+		}
+		key := prerenderKey{item: store.ItemKey(feed.Items[index]), width: m.viewport.Width}
+		keep[key] = true
+		if _, ok := m.prerendered[key]; ok || index == m.feedIndex {
+			continue
+		}
+		html, display := m.articleHTML(m.feedSliceIndex, index)
+		cmds = append(cmds, prerenderCmd(key, html, m.converterFor(m.feedSliceIndex), m.glamourStyle(), display))
+	}
+	for key := range m.prerendered {
+		if !keep[key] {
+			delete(m.prerendered, key)
+		}
+	}
+	return tea.Batch(cmds...)
+}
@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"context"
+	"log"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/homielabs/golang-rss-client/internal/readlater"
+)
+
+type savedForLaterMsg struct {
+	service string
+	err     error
+}
+
+// saveForLaterCmd saves an article to the read-later service in the
+// background.
+func saveForLaterCmd(ctx context.Context, service readlater.Config, article readlater.Article) tea.Cmd {
+	return func() tea.Msg {
+		return savedForLaterMsg{service: service.Name(), err: service.Save(ctx, article)}
+	}
+}
+
+// saveForLater saves the article being read to the read-later service.
+func (m *model) saveForLater() tea.Cmd {
+	if m.feedIndex >= m.feedSlice[m.feedSliceIndex].Len() {
+		return m.setStatus("Nothing to save")
+	}
+	item := m.feedSlice[m.feedSliceIndex].Items[m.feedIndex]
+	if item.Link == "" {
+		return m.setStatus("This article has no link")
+	}
+	if m.readLater.Service == "" {
+		return m.setStatus("No read-later service is configured")
+	}
+	status := m.setStatus("Saving to " + m.readLater.Name() + m.symbol("…", "..."))
+	article := readlater.Article{Title: item.Title, Link: item.Link}
+	return tea.Batch(status, saveForLaterCmd(m.ctx, m.readLater, article))
+}
+
+// savedForLater reports how saving went.
+func (m *model) savedForLater(msg savedForLaterMsg) tea.Cmd {
+	if msg.err != nil {
+		log.Println(msg.err)
+		return m.setStatus("Couldn't save for later: " + msg.err.Error())
+	}
+	return m.setStatus("Saved to " + msg.service)
+}
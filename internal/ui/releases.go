@@ -0,0 +1,26 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/homielabs/golang-rss-client/internal/render"
+)
+
+// copyInstallCommand copies the command that installs the release being
+// read, as configured for its feed, with its version filled in.
+func (m *model) copyInstallCommand() tea.Cmd {
+	feed := m.feedSlice[m.feedSliceIndex]
+	if m.feedIndex >= feed.Len() {
+		return m.setStatus("Nothing to install")
+	}
+	version, ok := render.ReleaseVersion(feed.Items[m.feedIndex].Link)
+	if !ok {
+		return m.setStatus("This article isn't a GitHub release")
+	}
+	command, ok := m.installCommands[m.feedUrls[m.feedSliceIndex]]
+	if !ok {
+		return m.setStatus("No install command is configured for this feed")
+	}
+	return m.yank(strings.ReplaceAll(command, "{{version}}", version))
+}
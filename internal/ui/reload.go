@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/homielabs/golang-rss-client/internal/backend"
+	"github.com/homielabs/golang-rss-client/internal/config"
+	"github.com/homielabs/golang-rss-client/internal/fetch"
+	"github.com/spf13/viper"
+)
+
+// reloadMsg asks for the config file to be read again, sent on SIGHUP.
+type reloadMsg struct{}
+
+// reloadConfig reads the config file again and brings the feeds in line with
+// it: feeds added to it are fetched, feeds gone from it are dropped, and the
+// paused, notify and priority lists, notifications, groups, titles and colors
+// are replaced. Everything else still needs a restart.
+func (m *model) reloadConfig() tea.Cmd {
+	if err := config.Reload(); err != nil {
+		log.Println(err)
+		return m.setStatus("Couldn't reload the config: " + err.Error())
+	}
+	feedColors, err := config.FeedColors()
+	if err != nil {
+		log.Println(err)
+		return m.setStatus("Couldn't reload the config: " + err.Error())
+	}
+	notifyAll, notifyOverrides, err := config.Notifications()
+	if err != nil {
+		log.Println(err)
+		return m.setStatus("Couldn't reload the config: " + err.Error())
+	}
+
+	wasPaused := m.pausedFeeds
+	m.pausedFeeds = config.Set("pausedFeeds")
+	m.notifyFeeds = config.Set("notifyFeeds")
+	m.notifyAll, m.notifyOverrides = notifyAll, notifyOverrides
+	m.notifyMinScore, m.notifyOnScore = config.NotifyMinScore()
+	m.priorityFeeds = config.Set("priorityFeeds")
+	m.groups = config.Groups()
+	m.feedTitles = config.FeedTitles()
+	m.feedColors = feedColors
+
+	wanted := make(map[string]bool)
+	for _, feedUrl := range viper.GetStringSlice("feedUrls") {
+		wanted[feedUrl] = true
+	}
+	removed := 0
+	for index := len(m.feedUrls) - 1; index >= 0; index-- {
+		// the sync servers' feeds aren't in the file, but listed by them
+		if !m.isVirtualFeed(index) && m.backends.For(m.feedUrls[index]).Name() == backend.DirectName && !wanted[m.feedUrls[index]] {
+			log.Println("reload: dropped", m.feedUrls[index])
+			m.dropFeed(index)
+			removed++
+		}
+	}
+
+	var cmds []tea.Cmd
+	for index, feedUrl := range m.feedUrls {
+		if title, ok := m.feedTitles[feedUrl]; ok {
+			m.feedSlice[index].Title = title
+		}
+		// feeds unpaused in the file are fetched straight away, as if
+		// unpaused from the reader
+		if wasPaused[feedUrl] && !m.isPaused(index) && !m.loading[index] {
+			cmds = append(cmds, fetchFeedCmd(m.ctx, index, feedUrl, m.fetcher))
+		}
+	}
+	added := 0
+	for _, feedUrl := range viper.GetStringSlice("feedUrls") {
+		if m.isSubscribed(feedUrl) {
+			continue
+		}
+		log.Println("reload: added", feedUrl)
+		index := m.appendFeed(feedUrl, fetch.Placeholder(feedUrl))
+		added++
+		cmds = append(cmds, m.scheduleRefresh(index))
+		if !m.isPaused(index) {
+			m.loading[index] = true
+			cmds = append(cmds, fetchFeedCmd(m.ctx, index, feedUrl, m.fetcher))
+		}
+	}
+	if added > 0 {
+		cmds = append(cmds, spinner.Tick)
+	}
+
+	status := "Reloaded the config"
+	if added > 0 || removed > 0 {
+		status += fmt.Sprintf(": %d feed(s) added, %d removed", added, removed)
+	}
+	return tea.Batch(append(cmds, m.setStatus(status))...)
+}
@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// regexPrefix marks a search as a regular expression rather than text to
+// look for.
+const regexPrefix = "re:"
+
+// searchScreen is the input a search across every feed is typed into, and
+// the last search made, for going back to its results.
+type searchScreen struct {
+	open  bool
+	input textinput.Model
+	query string
+}
+
+// searchRegexp is what a query looks for: a case-insensitive substring, or a
+// regular expression after re:.
+func searchRegexp(query string) (*regexp.Regexp, error) {
+	if strings.HasPrefix(query, regexPrefix) {
+		return regexp.Compile(strings.TrimPrefix(query, regexPrefix))
+	}
+	return regexp.Compile("(?i)" + regexp.QuoteMeta(query))
+}
+
+// searchMatcher reports whether text matches a query.
+func searchMatcher(query string) (func(string) bool, error) {
+	re, err := searchRegexp(query)
+	if err != nil {
+		return nil, err
+	}
+	return re.MatchString, nil
+}
+
+// plainText is an item's HTML without the tags, so searches only match what
+// can be read.
+func plainText(content string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	if err != nil {
+		return content
+	}
+	doc.Find("script, style").Remove()
+	return doc.Text()
+}
+
+// openSearch asks for something to search for, starting from the last
+// search.
+func (m *model) openSearch() {
+	input := textinput.NewModel()
+	input.Prompt = "/"
+	input.Placeholder = "text in the title or article, or re:<regexp>"
+	input.SetCursorMode(textinput.CursorStatic)
+	input.SetValue(m.search.query)
+	input.CursorEnd()
+	input.Focus()
+	m.search.input = input
+	m.search.open = true
+}
+
+func (m model) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.search.open = false
+		return m, nil
+	case tea.KeyCtrlC:
+		return m, m.quit()
+	case tea.KeyEnter:
+		query := strings.TrimSpace(m.search.input.Value())
+		if query == "" {
+			m.search.open = false
+			return m, nil
+		}
+		matches, err := searchMatcher(query)
+		if err != nil {
+			cmd := m.setStatus("Not a regular expression: " + err.Error())
+			return m, cmd
+		}
+		if len(m.searchResults(matches)) == 0 {
+			cmd := m.setStatus(fmt.Sprintf("No articles match %q", query))
+			return m, cmd
+		}
+		m.search.open = false
+		m.search.query = query
+		m.openArticleList(articleListSearch)
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.search.input, cmd = m.search.input.Update(msg)
+	return m, cmd
+}
+
+// searchResults lists the items in every feed whose title or text matches,
+// feed by feed.
+func (m model) searchResults(matches func(string) bool) []list.Item {
+	var items []list.Item
+	for i, feed := range m.feedSlice {
+		// starred items and followed authors' are already in their own feeds
+		if m.isVirtualFeed(i) {
+			continue
+		}
+		feedTitle := feed.Title
+		if feedTitle == "" {
+			feedTitle = m.feedUrls[i]
+		}
+		for j, item := range feed.Items {
+			if matches(item.Title) || matches(plainText(item.Description+"\n"+item.Content)) {
+				items = append(items, m.newArticleItem(i, j, feedTitle))
+			}
+		}
+	}
+	return items
+}
+
+// assembleSearch renders the search input, with a reminder of what it takes.
+func assembleSearch(m model) string {
+	hint := lipgloss.NewStyle().Faint(!m.accessible).Width(m.viewport.Width).Render(
+		"Searches the titles and text of every feed, ignoring case, or by regular " +
+			"expression after re:. enter lists the articles that match, esc cancels.",
+	)
+	return lipgloss.NewStyle().
+		Height(m.viewport.Height).
+		MaxHeight(m.viewport.Height).
+		MaxWidth(m.viewport.Width).
+		Render(m.search.input.View() + "\n\n" + hint)
+}
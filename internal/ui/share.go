@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/homielabs/golang-rss-client/internal/share"
+	"github.com/mmcdole/gofeed"
+)
+
+// summaryRunes is about how much of an article is shared with it, to the
+// targets that show a summary.
+const summaryRunes = 300
+
+type sharedMsg struct {
+	target string
+	err    error
+}
+
+// shareCmd posts an article to a share target in the background.
+func shareCmd(ctx context.Context, target share.Target, article share.Article) tea.Cmd {
+	return func() tea.Msg {
+		return sharedMsg{target: target.Label(), err: share.Send(ctx, target, article)}
+	}
+}
+
+// shareArticle shares the article being read, straight away if there's only
+// the one target and otherwise once one's been picked.
+func (m *model) shareArticle() tea.Cmd {
+	if m.feedIndex >= m.feedSlice[m.feedSliceIndex].Len() {
+		return m.setStatus("Nothing to share")
+	}
+	if m.feedSlice[m.feedSliceIndex].Items[m.feedIndex].Link == "" {
+		return m.setStatus("This article has no link")
+	}
+	switch len(m.shareTargets) {
+	case 0:
+		return m.setStatus("No share targets are configured")
+	case 1:
+		return m.shareTo(0)
+	}
+	var labels []string
+	for _, target := range m.shareTargets {
+		labels = append(labels, fmt.Sprintf("%-30s %s", target.Label(), target.Service))
+	}
+	m.picker = newPicker(pickerShare, "share to where?", labels)
+	return nil
+}
+
+// shareTo shares the article being read to the chosen target.
+func (m *model) shareTo(chosen int) tea.Cmd {
+	item := m.feedSlice[m.feedSliceIndex].Items[m.feedIndex]
+	target := m.shareTargets[chosen]
+	status := m.setStatus("Sharing to " + target.Label() + m.symbol("…", "..."))
+	article := share.Article{Title: item.Title, Link: item.Link, Summary: shareSummary(item)}
+	return tea.Batch(status, shareCmd(m.ctx, target, article))
+}
+
+// shareSummary is the start of an item's description, or its content if it
+// has none, as plain text cut off at a word.
+func shareSummary(item *gofeed.Item) string {
+	text := item.Description
+	if strings.TrimSpace(text) == "" {
+		text = item.Content
+	}
+	var summary []string
+	length := 0
+	for _, word := range strings.Fields(plainText(text)) {
+		length += len([]rune(word)) + 1
+		if length > summaryRunes {
+			return strings.Join(summary, " ") + "…"
+		}
+		summary = append(summary, word)
+	}
+	return strings.Join(summary, " ")
+}
+
+// shared reports how sharing went.
+func (m *model) shared(msg sharedMsg) tea.Cmd {
+	if msg.err != nil {
+		log.Println(msg.err)
+		return m.setStatus("Couldn't share: " + msg.err.Error())
+	}
+	return m.setStatus("Shared to " + msg.target)
+}
@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/term"
+)
+
+// autosaveInterval is how often the reading progress is saved while reading,
+// which is about as much as a crash can lose. The read state and stars are
+// saved as soon as they change.
+const autosaveInterval = 5 * time.Second
+
+// fetchWaitTimeout is how long quitting waits for fetches already under way
+// to finish caching what they downloaded.
+const fetchWaitTimeout = 2 * time.Second
+
+type autosaveMsg struct{}
+
+func autosaveCmd() tea.Cmd {
+	return tea.Tick(autosaveInterval, func(time.Time) tea.Msg {
+		return autosaveMsg{}
+	})
+}
+
+// saveState writes out whatever hasn't been saved yet.
+func (m model) saveState() {
+	if err := m.progress.Save(); err != nil {
+		log.Println(err)
+	}
+}
+
+// handleSignals ends the program the way q does on SIGTERM (bubbletea itself
+// only listens for SIGINT), and reloads the config on SIGHUP, like a service
+// would. The returned func stops listening.
+func handleSignals(p *tea.Program) func() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGHUP)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				// a hangup also comes when the terminal is closed, and
+				// then there's nothing left to reload for
+				if sig == syscall.SIGHUP && terminalAttached() {
+					log.Println("hangup, reloading the config")
+					p.Send(reloadMsg{})
+					continue
+				}
+				log.Printf("%s, quitting", sig)
+				p.Send(tea.Quit())
+				return
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
+
+// terminalAttached reports whether the terminal is still there to draw on.
+func terminalAttached() bool {
+	_, _, err := term.GetSize(int(os.Stdout.Fd()))
+	return err == nil
+}
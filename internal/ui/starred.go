@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"log"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mmcdole/gofeed"
+)
+
+// starredFeedUrl stands in for the URL of the starred feed, which is made up
+// of starred items rather than fetched.
+const starredFeedUrl = "starred:"
+
+// isStarredFeed reports whether a feed is the starred one.
+func (m model) isStarredFeed(index int) bool {
+	return m.feedUrls[index] == starredFeedUrl
+}
+
+// starredFeed builds the virtual feed of starred items.
+func (m model) starredFeed() gofeed.Feed {
+	feed := gofeed.Feed{Title: "Starred"}
+	for _, starred := range m.stars.Items() {
+		feed.Items = append(feed.Items, starred.Item)
+	}
+	return feed
+}
+
+// toggleStar stars or unstars the item being read.
+func (m *model) toggleStar() tea.Cmd {
+	if m.feedIndex >= m.feedSlice[m.feedSliceIndex].Len() {
+		return m.setStatus("Nothing to star")
+	}
+	item := m.feedSlice[m.feedSliceIndex].Items[m.feedIndex]
+	starred := m.stars.Toggle(m.feedUrls[m.feedSliceIndex], item)
+	if err := m.stars.Save(); err != nil {
+		log.Println(err)
+		return m.setStatus("Couldn't save stars: " + err.Error())
+	}
+
+	for i := range m.feedUrls {
+		if m.isStarredFeed(i) {
+			m.feedSlice[i] = m.starredFeed()
+		}
+	}
+	if starred {
+		m.logReading("Starred", item)
+		return m.setStatus("Starred")
+	}
+	if m.isStarredFeed(m.feedSliceIndex) {
+		// the item just left this feed
+		if m.feedIndex > 0 && m.feedIndex >= m.feedSlice[m.feedSliceIndex].Len() {
+			m.feedIndex--
+		}
+		return tea.Batch(m.setStatus("Unstarred"), func() tea.Msg { return rerenderMsg{} })
+	}
+	return m.setStatus("Unstarred")
+}
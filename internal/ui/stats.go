@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/homielabs/golang-rss-client/internal/fetch"
+)
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// assembleStats lists every feed with its request count and download volume,
+// heaviest first.
+func assembleStats(m model) string {
+	type row struct {
+		title string
+		stats fetch.FeedStats
+	}
+
+	var rows []row
+	var total fetch.FeedStats
+	for i, feedUrl := range m.feedUrls {
+		if m.isVirtualFeed(i) {
+			continue
+		}
+		stats := m.fetcher.Stats.Get(feedUrl)
+		total.Requests += stats.Requests
+		total.Bytes += stats.Bytes
+		title := m.feedSlice[i].Title
+		if title == "" {
+			title = feedUrl
+		}
+		rows = append(rows, row{title: m.feedBadge(i) + title, stats: stats})
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].stats.Bytes > rows[j].stats.Bytes
+	})
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(m.accent))
+	if m.accessible {
+		headerStyle = lipgloss.NewStyle()
+	}
+	lines := []string{
+		headerStyle.Render(fmt.Sprintf("%10s %9s  %s", "Downloaded", "Requests", "Feed")),
+	}
+	for _, r := range rows {
+		lines = append(lines, fmt.Sprintf(
+			"%10s %9d  %s", formatBytes(r.stats.Bytes), r.stats.Requests, r.title,
+		))
+	}
+	lines = append(lines, "", headerStyle.Render(fmt.Sprintf(
+		"%10s %9d  %s", formatBytes(total.Bytes), total.Requests, "Total this session",
+	)))
+
+	return lipgloss.NewStyle().
+		Height(m.viewport.Height).
+		MaxHeight(m.viewport.Height).
+		MaxWidth(m.viewport.Width).
+		PaddingLeft(m.horzPadding).
+		Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,26 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// statusTimeout is how long a status message stays on screen.
+const statusTimeout = 3 * time.Second
+
+type clearStatusMsg struct {
+	id int
+}
+
+// setStatus shows a short message in the breadcrumb bar and returns the
+// command that clears it again. Only the latest message is cleared, so a
+// newer one isn't cut short by an older timer.
+func (m *model) setStatus(text string) tea.Cmd {
+	m.statusID++
+	m.status = text
+	id := m.statusID
+	return tea.Tick(statusTimeout, func(time.Time) tea.Msg {
+		return clearStatusMsg{id: id}
+	})
+}
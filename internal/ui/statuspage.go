@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"net/url"
+	"regexp"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/homielabs/golang-rss-client/internal/fetch"
+	"github.com/mmcdole/gofeed"
+)
+
+// statusPageHistory matches the URLs of statuspage.io's incident history
+// feeds, which tell a status page apart before it's been fetched.
+var statusPageHistory = regexp.MustCompile(`/history\.(?:rss|atom)$`)
+
+// isStatusPage reports whether a feed is a status page's incident history:
+// whether it's at a status page's history URL, or any of its items is an
+// incident.
+func (m model) isStatusPage(index int) bool {
+	if index >= len(m.feedSlice) {
+		return false
+	}
+	if u, err := url.Parse(m.feedUrls[index]); err == nil && statusPageHistory.MatchString(u.Path) {
+		return true
+	}
+	for _, item := range m.feedSlice[index].Items {
+		if _, ok := item.Custom[fetch.IncidentKey]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// openIncidents are the incidents in a list of items that are still going
+// on.
+func openIncidents(items []*gofeed.Item) []*gofeed.Item {
+	var open []*gofeed.Item
+	for _, item := range items {
+		if fetch.IncidentOpen(item) {
+			open = append(open, item)
+		}
+	}
+	return open
+}
+
+// statusPageState is what a status page says of the service, as the feed
+// list puts it: operational, or degraded while an incident's open.
+func (m model) statusPageState(index int) string {
+	open := openIncidents(m.feedSlice[index].Items)
+	if len(open) == 0 {
+		return "operational"
+	}
+	state := "degraded (" + open[0].Custom[fetch.IncidentKey] + ")"
+	if !m.accessible {
+		state = lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render(m.symbol("▲ ", "") + state)
+	}
+	return state
+}
+
+// notifiesIncidents reports whether incidents opening on a status page, and
+// items scoring high enough, are notified about, which they are even when its
+// other new items wouldn't be: unless its own notification setting turns them
+// off, or it's quiet hours.
+func (m model) notifiesIncidents(index int) bool {
+	if m.isQuiet() {
+		return false
+	}
+	notify, ok := m.notifyOverrides[m.feedUrls[index]]
+	return !ok || notify
+}
@@ -0,0 +1,188 @@
+package ui
+
+import (
+	"context"
+	"log"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/homielabs/golang-rss-client/internal/backend"
+	"github.com/homielabs/golang-rss-client/internal/config"
+	"github.com/homielabs/golang-rss-client/internal/fetch"
+	"github.com/mmcdole/gofeed"
+)
+
+// subscribe adds the feed a linked page advertises, saving it to the config
+// and fetching it straight away.
+func (m *model) subscribe(feedUrl string) tea.Cmd {
+	if feedUrl == "" {
+		if m.lowBandwidth {
+			return m.setStatus("Linked pages aren't fetched in low-bandwidth mode")
+		}
+		return m.setStatus("No feed found on this page")
+	}
+	if m.isSubscribed(feedUrl) {
+		return m.setStatus("Already subscribed to " + feedUrl)
+	}
+	if !m.subscribesHere() {
+		return m.subscribeOnServer(feedUrl)
+	}
+	index, err := m.addFeed(feedUrl, fetch.Placeholder(feedUrl))
+	if err != nil {
+		log.Println(err)
+		return m.setStatus("Couldn't subscribe: " + err.Error())
+	}
+	return m.fetchNewFeed(index, "Subscribed to "+feedUrl)
+}
+
+// fetchNewFeed fetches a feed just subscribed to.
+func (m *model) fetchNewFeed(index int, status string) tea.Cmd {
+	m.loading[index] = true
+	return tea.Batch(
+		fetchFeedCmd(m.ctx, index, m.feedUrls[index], m.fetcher),
+		m.scheduleRefresh(index),
+		// restarts the spinner if it had stopped; its tags retire the
+		// old tick loop otherwise
+		spinner.Tick,
+		m.setStatus(status),
+	)
+}
+
+// subscribesHere reports whether subscriptions are made with the direct
+// backend, straight away, rather than on a server.
+func (m model) subscribesHere() bool {
+	return m.backend.Name() == backend.DirectName
+}
+
+type subscribedMsg struct {
+	// feedUrl is the feed subscribed to, and readUrl what the backend reads
+	// it by
+	feedUrl string
+	readUrl string
+	err     error
+}
+
+// subscribeCmd subscribes to a feed with a backend in the background.
+func subscribeCmd(ctx context.Context, b backend.Backend, feedUrl string) tea.Cmd {
+	return func() tea.Msg {
+		readUrl, err := b.Subscribe(ctx, feedUrl)
+		return subscribedMsg{feedUrl: feedUrl, readUrl: readUrl, err: err}
+	}
+}
+
+// subscribeOnServer subscribes to a feed with the backend subscriptions are
+// made with, as it's a server's.
+func (m *model) subscribeOnServer(feedUrl string) tea.Cmd {
+	return tea.Batch(
+		m.setStatus("Subscribing on "+m.backend.Name()+m.symbol("…", "...")),
+		subscribeCmd(m.ctx, m.backend, feedUrl),
+	)
+}
+
+// subscribed adds a feed subscribed to on a server, and fetches it from
+// there.
+func (m *model) subscribed(msg subscribedMsg) tea.Cmd {
+	if msg.err != nil {
+		log.Println(msg.err)
+		return m.setStatus("Couldn't subscribe: " + msg.err.Error())
+	}
+	if m.isSubscribed(msg.readUrl) {
+		return m.setStatus("Already subscribed to " + msg.feedUrl)
+	}
+	log.Println("subscribed:", msg.feedUrl, "as", msg.readUrl)
+	index := m.appendFeed(msg.readUrl, fetch.Placeholder(msg.readUrl))
+	return m.fetchNewFeed(index, "Subscribed to "+msg.feedUrl+" on "+m.backend.Name())
+}
+
+func (m model) isSubscribed(feedUrl string) bool {
+	for _, subscribed := range m.feedUrls {
+		if subscribed == feedUrl {
+			return true
+		}
+	}
+	return false
+}
+
+// addFeed subscribes to a feed with the direct backend, which saves it to the
+// config, and adds it after the other feeds, returning its index.
+func (m *model) addFeed(feedUrl string, feed gofeed.Feed) (int, error) {
+	if _, err := m.backend.Subscribe(m.ctx, feedUrl); err != nil {
+		return 0, err
+	}
+	log.Println("subscribed:", feedUrl)
+	return m.appendFeed(feedUrl, feed), nil
+}
+
+// appendFeed adds a feed after the others, returning its index.
+func (m *model) appendFeed(feedUrl string, feed gofeed.Feed) int {
+	index := len(m.feedUrls)
+	m.feedUrls = append(m.feedUrls, feedUrl)
+	m.feedSlice = append(m.feedSlice, feed)
+	return index
+}
+
+// removeFeed unsubscribes from a feed, here and in the config.
+func (m *model) removeFeed(index int) error {
+	feedUrl := m.feedUrls[index]
+	if err := config.RemoveFeedURL(feedUrl); err != nil {
+		return err
+	}
+	log.Println("unsubscribed:", feedUrl)
+	m.dropFeed(index)
+	return nil
+}
+
+// dropFeed takes a feed out of the reader. Everything kept by feed index
+// shuffles up to fill the gap.
+func (m *model) dropFeed(index int) {
+	feedUrl := m.feedUrls[index]
+	m.feedUrls = append(m.feedUrls[:index:index], m.feedUrls[index+1:]...)
+	m.feedSlice = append(m.feedSlice[:index:index], m.feedSlice[index+1:]...)
+	m.rekeyFeed(feedUrl, "")
+
+	shifted := func(i int) int {
+		if i > index {
+			return i - 1
+		}
+		return i
+	}
+	loading := make(map[int]bool)
+	for i := range m.loading {
+		if i != index {
+			loading[shifted(i)] = true
+		}
+	}
+	m.loading = loading
+	refreshing := make(map[int]bool)
+	for i := range m.refreshing {
+		if i != index {
+			refreshing[shifted(i)] = true
+		}
+	}
+	m.refreshing = refreshing
+	freshItems := make(map[int][]string)
+	for i, keys := range m.freshItems {
+		if i != index {
+			freshItems[shifted(i)] = keys
+		}
+	}
+	m.freshItems = freshItems
+	feedErrors := make(map[int]error)
+	for i, err := range m.feedErrors {
+		if i != index {
+			feedErrors[shifted(i)] = err
+		}
+	}
+	m.feedErrors = feedErrors
+
+	if m.feedSliceIndex == index {
+		m.feedIndex = 0
+	}
+	m.feedSliceIndex = shifted(m.feedSliceIndex)
+	if m.feedSliceIndex >= len(m.feedSlice) {
+		m.feedSliceIndex = len(m.feedSlice) - 1
+	}
+	m.feedListIndex = m.feedListRowOf(m.feedSliceIndex)
+	// followed authors' articles in the feed go with it
+	m.updateAuthorFeeds()
+}
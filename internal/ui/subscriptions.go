@@ -0,0 +1,344 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/homielabs/golang-rss-client/internal/config"
+	"github.com/homielabs/golang-rss-client/internal/fetch"
+	"github.com/mmcdole/gofeed"
+)
+
+// subscriptionEdit is what the subscriptions screen is asking for, if
+// anything.
+type subscriptionEdit int
+
+const (
+	editNone subscriptionEdit = iota
+	// the URL of a feed to subscribe to
+	editAdd
+	// a new title for the selected feed
+	editRename
+	// confirmation before unsubscribing from the selected feed
+	editRemove
+)
+
+// subscriptionScreen is the state of the screen for adding, renaming and
+// removing feeds.
+type subscriptionScreen struct {
+	open bool
+	// cursor is the selected feed's position in subscribedIndices
+	cursor int
+	edit   subscriptionEdit
+	input  textinput.Model
+	// checking is the URL being fetched to make sure it's a feed before
+	// it's subscribed to
+	checking string
+	// discovered are the feeds advertised by the web page given instead of
+	// a feed, to pick from
+	discovered []fetch.DiscoveredFeed
+}
+
+// subscriptionKeys are the keys that only mean something on the
+// subscriptions screen.
+var subscriptionKeys = struct {
+	Add    key.Binding
+	Rename key.Binding
+	Remove key.Binding
+}{
+	Add: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "add"),
+	),
+	Rename: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "rename"),
+	),
+	Remove: key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "unsubscribe"),
+	),
+}
+
+type feedCheckedMsg struct {
+	feedUrl string
+	feed    *gofeed.Feed
+	err     error
+}
+
+// checkFeedCmd test fetches a feed that's about to be subscribed to.
+func checkFeedCmd(ctx context.Context, feedUrl string, fetcher fetch.Fetcher) tea.Cmd {
+	return func() tea.Msg {
+		feed, err := fetcher.Fetch(ctx, feedUrl)
+		return feedCheckedMsg{feedUrl: feedUrl, feed: feed, err: err}
+	}
+}
+
+// subscribedIndices are the feeds listed on the subscriptions screen: all of
+// them but the starred one and followed authors'.
+func (m model) subscribedIndices() []int {
+	var indices []int
+	for i := range m.feedSlice {
+		if !m.isVirtualFeed(i) {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// openSubscriptions shows the subscriptions screen, starting on the feed
+// being read.
+func (m *model) openSubscriptions() {
+	m.subscriptions = subscriptionScreen{open: true}
+	for cursor, index := range m.subscribedIndices() {
+		if index == m.feedSliceIndex {
+			m.subscriptions.cursor = cursor
+		}
+	}
+}
+
+// startEdit asks for a URL or title in the input line.
+func (m *model) startEdit(edit subscriptionEdit, placeholder string, value string) {
+	input := textinput.NewModel()
+	input.Prompt = "> "
+	input.Placeholder = placeholder
+	input.SetCursorMode(textinput.CursorStatic)
+	input.SetValue(value)
+	input.CursorEnd()
+	input.Focus()
+	m.subscriptions.edit = edit
+	m.subscriptions.input = input
+}
+
+func (m model) updateSubscriptions(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	screen := &m.subscriptions
+	indices := m.subscribedIndices()
+
+	switch screen.edit {
+	case editAdd, editRename:
+		switch msg.Type {
+		case tea.KeyEsc:
+			screen.edit = editNone
+			return m, nil
+		case tea.KeyCtrlC:
+			return m, m.quit()
+		case tea.KeyEnter:
+			edit := screen.edit
+			screen.edit = editNone
+			value := strings.TrimSpace(screen.input.Value())
+			if edit == editAdd {
+				cmd := m.checkFeed(value)
+				return m, cmd
+			}
+			cmd := m.renameFeed(indices[screen.cursor], value)
+			return m, cmd
+		}
+		var cmd tea.Cmd
+		screen.input, cmd = screen.input.Update(msg)
+		return m, cmd
+	case editRemove:
+		screen.edit = editNone
+		if msg.String() != "y" {
+			return m, nil
+		}
+		index := indices[screen.cursor]
+		title := m.feedSlice[index].Title
+		if err := m.removeFeed(index); err != nil {
+			log.Println(err)
+			cmd := m.setStatus("Couldn't unsubscribe: " + err.Error())
+			return m, cmd
+		}
+		if screen.cursor >= len(indices)-1 && screen.cursor > 0 {
+			screen.cursor--
+		}
+		status := m.setStatus("Unsubscribed from " + title)
+		model, cmd := m.Update(rerenderMsg{})
+		return model, tea.Batch(status, cmd)
+	}
+
+	switch {
+	case key.Matches(msg, defaultKeyMap.Up):
+		if screen.cursor > 0 {
+			screen.cursor--
+		}
+	case key.Matches(msg, defaultKeyMap.Down):
+		if screen.cursor < len(indices)-1 {
+			screen.cursor++
+		}
+	case key.Matches(msg, subscriptionKeys.Add):
+		m.startEdit(editAdd, "feed URL", "")
+	case key.Matches(msg, subscriptionKeys.Rename) && len(indices) > 0:
+		m.startEdit(editRename, "title, or empty for the feed's own", m.feedSlice[indices[screen.cursor]].Title)
+	case key.Matches(msg, subscriptionKeys.Remove) && len(indices) > 0:
+		screen.edit = editRemove
+	case key.Matches(msg, defaultKeyMap.Open) && len(indices) > 0:
+		screen.open = false
+		if indices[screen.cursor] != m.feedSliceIndex {
+			m.feedSliceIndex = indices[screen.cursor]
+			m.feedIndex = 0
+			return m.Update(rerenderMsg{})
+		}
+	case key.Matches(msg, defaultKeyMap.Manage), key.Matches(msg, defaultKeyMap.Back):
+		screen.open = false
+	case key.Matches(msg, defaultKeyMap.Quit):
+		return m, m.quit()
+	}
+	return m, nil
+}
+
+// checkFeed starts the test fetch of a feed to be subscribed to, once the URL
+// at least looks like one.
+func (m *model) checkFeed(feedUrl string) tea.Cmd {
+	parsed, err := url.Parse(feedUrl)
+	isWeb := err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+	if !isWeb && !m.fetcher.IsLocal(feedUrl) {
+		return m.setStatus(fmt.Sprintf("%q isn't an http(s) URL", feedUrl))
+	}
+	if m.isSubscribed(feedUrl) {
+		return m.setStatus("Already subscribed to " + feedUrl)
+	}
+	m.subscriptions.checking = feedUrl
+	return checkFeedCmd(m.ctx, feedUrl, m.fetcher)
+}
+
+// feedChecked subscribes to a feed once the test fetch shows it works.
+func (m *model) feedChecked(msg feedCheckedMsg) tea.Cmd {
+	m.subscriptions.checking = ""
+	var page *fetch.PageError
+	if errors.As(msg.err, &page) && len(page.Feeds) > 0 {
+		m.pickDiscovered(page.Feeds)
+		return nil
+	}
+	if msg.err != nil {
+		log.Println(msg.err)
+		return m.setStatus(fmt.Sprintf("Couldn't fetch %s: %s", msg.feedUrl, msg.err))
+	}
+	if m.isSubscribed(msg.feedUrl) {
+		return m.setStatus("Already subscribed to " + msg.feedUrl)
+	}
+	if !m.subscribesHere() {
+		return m.subscribeOnServer(msg.feedUrl)
+	}
+	index, err := m.addFeed(msg.feedUrl, *msg.feed)
+	if err != nil {
+		log.Println(err)
+		return m.setStatus("Couldn't subscribe: " + err.Error())
+	}
+	for cursor, subscribed := range m.subscribedIndices() {
+		if subscribed == index {
+			m.subscriptions.cursor = cursor
+		}
+	}
+	title := msg.feed.Title
+	if title == "" {
+		title = msg.feedUrl
+	}
+	return tea.Batch(m.scheduleRefresh(index), m.setStatus("Subscribed to "+title))
+}
+
+// pickDiscovered offers the feeds a web page advertises to subscribe to.
+func (m *model) pickDiscovered(feeds []fetch.DiscoveredFeed) {
+	m.subscriptions.discovered = feeds
+	var labels []string
+	for _, feed := range feeds {
+		title := feed.Title
+		if title == "" {
+			title = "(untitled)"
+		}
+		labels = append(labels, fmt.Sprintf("%-30s %-9s %s", title, feed.Format, feed.URL))
+	}
+	m.picker = newPicker(pickerDiscovered, "subscribe to which feed?", labels)
+}
+
+// renameFeed gives a feed a title of its own choosing, or back its own title
+// if title is empty, which means fetching it again.
+func (m *model) renameFeed(index int, title string) tea.Cmd {
+	feedUrl := m.feedUrls[index]
+	if err := config.SetFeedTitle(feedUrl, title); err != nil {
+		log.Println(err)
+		return m.setStatus("Couldn't rename feed: " + err.Error())
+	}
+	if title == "" {
+		delete(m.feedTitles, feedUrl)
+		return tea.Batch(m.refreshFeeds([]int{index}), m.setStatus("Using the feed's own title"))
+	}
+	m.feedTitles[feedUrl] = title
+	m.feedSlice[index].Title = title
+	return m.setStatus("Renamed to " + title)
+}
+
+// assembleSubscriptions renders the subscriptions screen: every feed with its
+// URL, scrolled to keep the selected one in view, over whatever's being asked
+// for and the keys that work here.
+func assembleSubscriptions(m model) string {
+	screen := m.subscriptions
+	indices := m.subscribedIndices()
+
+	var footer []string
+	switch {
+	case screen.checking != "":
+		footer = append(footer, "Checking "+screen.checking+m.symbol("…", "..."))
+	case screen.edit == editAdd, screen.edit == editRename:
+		footer = append(footer, screen.input.View())
+	case screen.edit == editRemove:
+		footer = append(footer, fmt.Sprintf("Unsubscribe from %s? y/n", m.feedSlice[indices[screen.cursor]].Title))
+	}
+	var hints []string
+	for _, binding := range []key.Binding{subscriptionKeys.Add, subscriptionKeys.Rename, subscriptionKeys.Remove} {
+		hints = append(hints, binding.Help().Key+" "+binding.Help().Desc)
+	}
+	hints = append(hints,
+		defaultKeyMap.Open.Help().Key+" read",
+		defaultKeyMap.Back.Help().Key+" close",
+	)
+	footer = append(footer, strings.Join(hints, m.symbol(" · ", ", ")))
+
+	visible := m.viewport.Height - len(footer) - 1
+	if visible < 1 {
+		visible = 1
+	}
+	start := 0
+	if screen.cursor >= visible {
+		start = screen.cursor - visible + 1
+	}
+	end := start + visible
+	if end > len(indices) {
+		end = len(indices)
+	}
+
+	selectedStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(m.feedAccent()))
+	dimStyle := lipgloss.NewStyle().Faint(!m.accessible)
+	var lines []string
+	for cursor := start; cursor < end; cursor++ {
+		index := indices[cursor]
+		title := m.feedSlice[index].Title
+		if title == "" {
+			title = m.feedUrls[index]
+		}
+		marker := "  "
+		if cursor == screen.cursor {
+			marker = m.symbol("▌ ", "> ")
+			title = selectedStyle.Render(title)
+		}
+		lines = append(lines, marker+title+"  "+dimStyle.Render(m.feedUrls[index]))
+	}
+	if len(indices) == 0 {
+		lines = append(lines, "  Not subscribed to anything yet")
+	}
+
+	list := lipgloss.NewStyle().
+		Height(m.viewport.Height - len(footer)).
+		MaxWidth(m.viewport.Width).
+		Render(strings.Join(lines, "\n"))
+	return lipgloss.NewStyle().
+		MaxHeight(m.viewport.Height).
+		Render(list + "\n" + strings.Join(footer, "\n"))
+}
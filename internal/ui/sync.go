@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"log"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/homielabs/golang-rss-client/internal/greader"
+	"github.com/homielabs/golang-rss-client/internal/store"
+)
+
+// syncWaitTimeout is how long quitting waits to tell the sync server what
+// was read since the last sync.
+const syncWaitTimeout = 5 * time.Second
+
+type readSyncedMsg struct {
+	// changes are the items read (true) or unread (false) on the server, by
+	// ItemKey
+	changes map[string]bool
+	err     error
+}
+
+// syncReadStateCmd syncs the read state of the server's items with the
+// server in the background.
+func syncReadStateCmd(m model) tea.Cmd {
+	local := make(map[string]bool)
+	for i, feed := range m.feedSlice {
+		if !greader.IsFeedURL(m.feedUrls[i]) {
+			continue
+		}
+		for _, item := range feed.Items {
+			local[store.ItemKey(item)] = m.readState.IsRead(item)
+		}
+	}
+	sync, ctx := m.sync, m.ctx
+	return func() tea.Msg {
+		changes, err := sync.Sync(ctx, local)
+		return readSyncedMsg{changes: changes, err: err}
+	}
+}
+
+// syncReadState starts a sync if it's time for one, once every feed has
+// loaded and the last sync is done. It's checked on every autosave, so the
+// first sync follows soon after loading.
+func (m *model) syncReadState() tea.Cmd {
+	if m.syncing || len(m.loading) > 0 || !m.sync.Due(time.Now()) {
+		return nil
+	}
+	m.syncing = true
+	return syncReadStateCmd(*m)
+}
+
+// readSynced marks what was read or unread on the server the same here.
+func (m *model) readSynced(msg readSyncedMsg) tea.Cmd {
+	m.syncing = false
+	if msg.err != nil {
+		log.Println(msg.err)
+		return m.setStatus("Couldn't sync the read state: " + msg.err.Error())
+	}
+	changed := false
+	for i, feed := range m.feedSlice {
+		if !greader.IsFeedURL(m.feedUrls[i]) {
+			continue
+		}
+		for _, item := range feed.Items {
+			read, ok := msg.changes[store.ItemKey(item)]
+			switch {
+			case !ok:
+			case read:
+				changed = m.readState.MarkRead(item) || changed
+			default:
+				changed = m.readState.MarkUnread(item) || changed
+			}
+		}
+	}
+	if changed {
+		if err := m.readState.Save(); err != nil {
+			log.Println(err)
+		}
+	}
+	return nil
+}
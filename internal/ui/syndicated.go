@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"log"
+
+	"github.com/homielabs/golang-rss-client/internal/fetch"
+	"github.com/homielabs/golang-rss-client/internal/store"
+	"github.com/mmcdole/gofeed"
+)
+
+// What's done with press releases: items whose text turns up nearly word
+// for word in a few feeds.
+const (
+	// left alone
+	pressReleasesOff = "off"
+	// marked with how many feeds they're in
+	pressReleasesFlag = "flag"
+	// marked, and only the copy in the feed loaded first is kept
+	pressReleasesCollapse = "collapse"
+	// marked, and every copy marked read
+	pressReleasesMute = "mute"
+)
+
+// fingerprintBands split fingerprints into parts of a couple of hashes that
+// near-identical ones almost always share at least one of, so each only has
+// to be compared with the ones it shares a part with.
+const fingerprintBands = len(fetch.Fingerprint{}) / 2
+
+// bandKeys are the keys a fingerprint is looked up by, one per band.
+func bandKeys(fingerprint fetch.Fingerprint) [fingerprintBands]uint64 {
+	var keys [fingerprintBands]uint64
+	for band := range keys {
+		keys[band] = uint64(band)<<56 ^ uint64(fingerprint[2*band])<<24 ^ uint64(fingerprint[2*band+1])
+	}
+	return keys
+}
+
+// findPressReleases looks for the items of a fetched feed in the other
+// feeds, before they're shown or notified about. Those in enough feeds are
+// press releases: they're marked with how many, and collapsed or muted if
+// that's what's configured.
+func (m model) findPressReleases(index int, feed *gofeed.Feed) {
+	if m.pressReleases == "" || m.pressReleases == pressReleasesOff {
+		return
+	}
+	copies := make(map[uint64][]articleRef)
+	for i, other := range m.feedSlice {
+		if i == index || m.isVirtualFeed(i) {
+			continue
+		}
+		for j, item := range other.Items {
+			if fingerprint, ok := fetch.ItemFingerprint(item); ok {
+				for _, key := range bandKeys(fingerprint) {
+					copies[key] = append(copies[key], articleRef{i, j})
+				}
+			}
+		}
+	}
+	if len(copies) == 0 {
+		return
+	}
+
+	kept := feed.Items[:0]
+	collapsed, muted := 0, 0
+	for _, item := range feed.Items {
+		fingerprint, ok := fetch.ItemFingerprint(item)
+		if !ok {
+			kept = append(kept, item)
+			continue
+		}
+		var matches []*gofeed.Item
+		feeds := map[int]bool{index: true}
+		seen := make(map[articleRef]bool)
+		for _, key := range bandKeys(fingerprint) {
+			for _, ref := range copies[key] {
+				other := m.feedSlice[ref.feed].Items[ref.index]
+				if seen[ref] {
+					continue
+				}
+				seen[ref] = true
+				if theirs, _ := fetch.ItemFingerprint(other); fingerprint.Similarity(theirs) >= fetch.NearIdentical {
+					matches = append(matches, other)
+					feeds[ref.feed] = true
+				}
+			}
+		}
+		if len(feeds) < m.pressReleaseFeeds {
+			kept = append(kept, item)
+			continue
+		}
+		for _, duplicate := range append(matches, item) {
+			m.syndicated[store.ItemKey(duplicate)] = len(feeds)
+		}
+		switch m.pressReleases {
+		case pressReleasesCollapse:
+			collapsed++
+			continue
+		case pressReleasesMute:
+			for _, duplicate := range append(matches, item) {
+				if m.readState.MarkRead(duplicate) {
+					muted++
+				}
+			}
+		}
+		kept = append(kept, item)
+	}
+	feed.Items = kept
+	if collapsed > 0 {
+		log.Printf("press releases: collapsed %d items of %s", collapsed, m.feedUrls[index])
+	}
+	if muted > 0 {
+		log.Printf("press releases: muted %d items", muted)
+		if err := m.readState.Save(); err != nil {
+			log.Println(err)
+		}
+	}
+}
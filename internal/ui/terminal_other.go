@@ -0,0 +1,25 @@
+//go:build !windows
+// +build !windows
+
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// resizePollMsg is only sent on Windows; elsewhere bubbletea listens for
+// SIGWINCH itself.
+type resizePollMsg struct {
+	width  int
+	height int
+}
+
+// PrepareTerminal gets the terminal ready for the UI, which only takes any
+// work on Windows.
+func PrepareTerminal() error {
+	return nil
+}
+
+func watchResizeCmd() tea.Cmd {
+	return nil
+}
@@ -0,0 +1,44 @@
+//go:build windows
+// +build windows
+
+package ui
+
+import (
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/sys/windows"
+	"golang.org/x/term"
+)
+
+// resizePollInterval is how often the console size is checked, since Windows
+// has no SIGWINCH to tell us about resizes.
+const resizePollInterval = 250 * time.Millisecond
+
+type resizePollMsg struct {
+	width  int
+	height int
+}
+
+// PrepareTerminal turns on ANSI escape processing for the console. Windows
+// Terminal does this already, but the classic console host doesn't.
+func PrepareTerminal() error {
+	handle := windows.Handle(os.Stdout.Fd())
+	var mode uint32
+	if err := windows.GetConsoleMode(handle, &mode); err != nil {
+		return err
+	}
+	return windows.SetConsoleMode(handle, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING)
+}
+
+// watchResizeCmd polls the console size.
+func watchResizeCmd() tea.Cmd {
+	return tea.Tick(resizePollInterval, func(time.Time) tea.Msg {
+		width, height, err := term.GetSize(int(os.Stdout.Fd()))
+		if err != nil {
+			return resizePollMsg{}
+		}
+		return resizePollMsg{width: width, height: height}
+	})
+}
@@ -0,0 +1,43 @@
+package ui
+
+import "github.com/charmbracelet/lipgloss"
+
+// themeColors are the header and footer colors that go with each of
+// glamour's own styles, unless textColor or backgroundColor say otherwise.
+// notty has none.
+var themeColors = map[string]struct {
+	text       string
+	background string
+}{
+	"dark":  {text: "15", background: "233"},
+	"light": {text: "235", background: "254"},
+	"notty": {},
+}
+
+// resolveTheme turns auto into dark or light, going by the terminal's
+// background as lipgloss found it before the reader started (asking the
+// terminal once bubbletea is reading its input would swallow the answer).
+func resolveTheme(theme string) string {
+	if theme != "auto" {
+		return theme
+	}
+	if lipgloss.HasDarkBackground() {
+		return "dark"
+	}
+	return "light"
+}
+
+// applyTheme fills in the colors the config leaves to the theme. A style
+// file gets the colors of whichever of dark and light suits the terminal.
+func (m *model) applyTheme() {
+	colors, builtIn := themeColors[m.theme]
+	if !builtIn {
+		colors = themeColors[resolveTheme("auto")]
+	}
+	if m.textColor == "" {
+		m.textColor = colors.text
+	}
+	if m.backgroundColor == "" {
+		m.backgroundColor = colors.background
+	}
+}
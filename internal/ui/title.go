@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// windowTitle is what the terminal title is set to for the current article.
+func (m model) windowTitle() string {
+	feed := m.feedSlice[m.feedSliceIndex]
+	if m.feedIndex >= feed.Len() {
+		return feed.Title
+	}
+	return feed.Title + " – " + feed.Items[m.feedIndex].Title
+}
+
+// setWindowTitleCmd sets the terminal window title. Inside tmux the same
+// sequence sets the pane title, and the window name is set as well.
+func setWindowTitleCmd(title string) tea.Cmd {
+	// the title has already been stripped of control characters, but make
+	// doubly sure it can't terminate the sequence early
+	title = strings.Map(func(r rune) rune {
+		if r == '\a' || r == '\x1b' {
+			return -1
+		}
+		return r
+	}, title)
+
+	return func() tea.Msg {
+		fmt.Fprintf(os.Stdout, "\x1b]2;%s\a", title)
+		if os.Getenv("TMUX") != "" {
+			fmt.Fprintf(os.Stdout, "\x1bk%s\x1b\\", title)
+		}
+		return nil
+	}
+}
+
+// saveWindowTitle and restoreWindowTitle push and pop the terminal's title
+// stack (xterm's XTWINOPS), so the original title comes back on quit.
+func saveWindowTitle() {
+	fmt.Fprint(os.Stdout, "\x1b[22;0t")
+}
+
+func restoreWindowTitle() {
+	fmt.Fprint(os.Stdout, "\x1b[23;0t")
+}
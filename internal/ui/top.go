@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+const (
+	// topStoriesLimit is how many items the top stories list goes up to.
+	topStoriesLimit = 50
+	// topGravity is how quickly items sink down the list as they age; the
+	// higher, the more recency beats score.
+	topGravity = 1.5
+)
+
+// rank weighs an item's score against how long ago it was published, the
+// way link aggregators do, so a high-scoring item holds its place for a while
+// but fresh ones keep coming through.
+func (m model) rank(feedIndex int, itemIndex int, now time.Time) float64 {
+	item := m.feedSlice[feedIndex].Items[itemIndex]
+	score := 0.0
+	if m.score != nil {
+		score = m.score(m.feedUrls[feedIndex], &m.feedSlice[feedIndex], item)
+	}
+	// undated items count as two days old
+	age := 48.0
+	if published := item.PublishedParsed; published != nil {
+		age = now.Sub(*published).Hours()
+		if age < 0 {
+			age = 0
+		}
+	}
+	return (1 + score) / math.Pow(age+2, topGravity)
+}
+
+// topStories lists the highest-ranked unread items across every feed.
+func (m model) topStories() []list.Item {
+	type ranked struct {
+		feed, item int
+		rank       float64
+	}
+	now := time.Now()
+	var candidates []ranked
+	for i, feed := range m.feedSlice {
+		// starred items and followed authors' are already in their own feeds
+		if m.isVirtualFeed(i) {
+			continue
+		}
+		for j, item := range feed.Items {
+			if !m.readState.IsRead(item) {
+				candidates = append(candidates, ranked{i, j, m.rank(i, j, now)})
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return candidates[a].rank > candidates[b].rank
+	})
+	if len(candidates) > topStoriesLimit {
+		candidates = candidates[:topStoriesLimit]
+	}
+
+	var items []list.Item
+	for _, c := range candidates {
+		feedTitle := m.feedSlice[c.feed].Title
+		if feedTitle == "" {
+			feedTitle = m.feedUrls[c.feed]
+		}
+		context := feedTitle
+		if m.score != nil {
+			score := m.score(m.feedUrls[c.feed], &m.feedSlice[c.feed], m.feedSlice[c.feed].Items[c.item])
+			context = fmt.Sprintf("%s (score %g)", feedTitle, score)
+		}
+		items = append(items, m.newArticleItem(c.feed, c.item, context))
+	}
+	return items
+}
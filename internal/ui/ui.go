@@ -0,0 +1,1851 @@
+// Package ui is the terminal reader: a bubbletea program paging through the
+// items of a list of feeds.
+package ui
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/homielabs/golang-rss-client/internal/backend"
+	"github.com/homielabs/golang-rss-client/internal/capture"
+	"github.com/homielabs/golang-rss-client/internal/config"
+	"github.com/homielabs/golang-rss-client/internal/fetch"
+	"github.com/homielabs/golang-rss-client/internal/greader"
+	"github.com/homielabs/golang-rss-client/internal/push"
+	"github.com/homielabs/golang-rss-client/internal/readinglog"
+	"github.com/homielabs/golang-rss-client/internal/readlater"
+	"github.com/homielabs/golang-rss-client/internal/render"
+	"github.com/homielabs/golang-rss-client/internal/share"
+	"github.com/homielabs/golang-rss-client/internal/store"
+	"github.com/mmcdole/gofeed"
+	"github.com/spf13/viper"
+)
+
+const (
+	headerHeight               = 3
+	breadcrumbHeight           = 1
+	footerHeight               = 3
+	useHighPerformanceRenderer = false
+)
+
+type model struct {
+	// ctx is cancelled on quit, taking any fetches still in flight with it
+	ctx         context.Context
+	cancel      context.CancelFunc
+	feedSlice   []gofeed.Feed
+	feedUrls    []string
+	pausedFeeds map[string]bool
+	// freshItems holds the ItemKeys of items brought in by background
+	// refreshes that haven't been looked at yet, by feed index
+	freshItems map[int][]string
+	// loading holds the indices of feeds that haven't been fetched for the
+	// first time yet and refreshing those being refreshed on request, while
+	// spinner turns
+	loading     map[int]bool
+	refreshing  map[int]bool
+	loadStarted time.Time
+	spinner     spinner.Model
+	// groups maps group names to the URLs of the feeds in them
+	groups map[string][]string
+	// collapsedGroups are the groups folded away in the feed list
+	collapsedGroups map[string]bool
+	// movedFeeds maps feeds that have moved permanently to their new URLs,
+	// until the move is accepted
+	movedFeeds map[string]string
+	// feedErrors are why the last fetch of a feed failed, by feed index
+	feedErrors map[int]error
+	// feedTitles are the titles feeds have been renamed to, by URL
+	feedTitles    map[string]string
+	subscriptions subscriptionScreen
+	notifyFeeds   map[string]bool
+	// notifyAll has every feed notified about, and notifyOverrides say
+	// whether particular feeds are, whatever notifyAll and notifyFeeds say
+	notifyAll       bool
+	notifyOverrides map[string]bool
+	// notifyMinScore is the score from which items are notified about
+	// whatever their feed, if notifyOnScore is set
+	notifyMinScore float64
+	notifyOnScore  bool
+	// desktopNotifications and pushTargets are where notifications go, and
+	// shareTargets where articles can be shared to with x, capture where
+	// they're filed away with E, and readLater where w saves them
+	desktopNotifications bool
+	pushTargets          []push.Target
+	shareTargets         []share.Target
+	capture              capture.Config
+	readLater            readlater.Config
+	languages            map[string]bool
+	feedLanguages        map[string]map[string]bool
+	mutedLanguages       map[string]bool
+	pressReleases        string
+	pressReleaseFeeds    int
+	// paywallDomains are the sites behind paywalls, and paywallArchiveURL
+	// the template their articles are opened through, if any
+	paywallDomains    map[string]bool
+	paywallArchiveURL string
+	// syndicated are how many feeds each press release is in, by ItemKey
+	syndicated    map[string]int
+	priorityFeeds map[string]bool
+	fetcher       fetch.Fetcher
+	readState     *store.ReadState
+	stars         *store.Stars
+	progress      *store.Progress
+	readingLog    *readinglog.Log
+	sync          *greader.Syncer
+	// syncing is set while the read state's being synced with the server
+	syncing bool
+	// backends are where the feeds are from, backend the one subscriptions
+	// are made with, and tracked the backends whose items are marked read
+	// and starred there as they are here
+	backends backend.Backends
+	backend  backend.Backend
+	tracked  []*tracked
+	// shown is the ItemKey of the item in the viewport
+	shown             string
+	statsMode         bool
+	feedSliceIndex    int
+	feedIndex         int
+	ready             bool
+	width             int
+	height            int
+	viewport          viewport.Model
+	help              help.Model
+	markdownConverter *md.Converter
+	// accent colors for feeds that have their own, keyed by URL
+	feedColors map[string]string
+	// installCommands are the commands I copies to install the release
+	// being read, by feed URL
+	installCommands map[string]string
+	// converters for feeds with their own markdown rules, keyed by URL
+	feedConverters map[string]*md.Converter
+	// feedDisplays override how individual feeds are rendered, by URL
+	feedDisplays map[string]render.Display
+	// renderers are glamour renderers by the width they wrap at, dropped
+	// when the window is resized
+	renderers map[int]*glamour.TermRenderer
+	// prerendered are the articles either side of the one being read,
+	// rendered ahead of time
+	prerendered map[prerenderKey]string
+	// fullText are the articles fetched from items' links with f, shown
+	// instead of the feed's copy, by ItemKey
+	fullText         map[string]string
+	fetchingFullText map[string]bool
+	// showingChanges are the re-published items shown as what changed in
+	// them with D, by ItemKey
+	showingChanges map[string]bool
+	// downloads are the enclosures being downloaded with d, and
+	// downloadedFiles where finished ones were saved, by enclosure URL, for p
+	// to play instead
+	downloads       []*download
+	downloadedFiles map[string]string
+	// lazy is the article being shown if it's long enough to be styled as
+	// it's scrolled through, nil otherwise
+	lazy *lazyArticle
+	// transient message shown in the breadcrumb bar
+	status   string
+	statusID int
+	// feed list
+	feedListMode  bool
+	feedListIndex int
+	// article list, and whether esc goes back to it from the article
+	articleListMode bool
+	articleListKind articleListKind
+	articleList     list.Model
+	fromArticleList bool
+	// whose articles the author article list shows
+	articleListAuthor string
+	// the language the language article list shows, and the ones to pick it
+	// from
+	articleListLanguage string
+	languageChoices     []string
+	search              searchScreen
+	// content is the article in the viewport, before the matches of the
+	// search in it (inArticle) are highlighted
+	content   string
+	inArticle articleSearch
+	// score rates items for the top stories
+	score ScoreFunc
+	// link selection
+	linkMode     bool
+	links        []articleLink
+	linkIndex    int
+	linkPreviews map[string]linkPreview
+	// marks, and the mark command (m or ') waiting for the mark's name
+	marks       map[rune]mark
+	pendingMark rune
+	// keptUnread is the item marked unread while it's being read, which
+	// isn't marked read again until it's been left
+	keptUnread string
+	// unreadOnly leaves read articles out of the article list and h/l
+	unreadOnly bool
+	// severeOnly leaves all but the high and critical security advisories out
+	// of the article list and h/l
+	severeOnly bool
+	// command palette and finder
+	picker      picker
+	findTargets []findTarget
+	// config-based
+	theme            string
+	accent           string
+	textColor        string
+	backgroundColor  string
+	horzPadding      int
+	vertPadding      int
+	lowBandwidth     bool
+	accessible       bool
+	asciiOnly        bool
+	setWindowTitle   bool
+	updateMovedFeeds bool
+	// pollInterval is how often feeds are refetched; priority feeds use
+	// priorityPollInterval instead
+	pollInterval         time.Duration
+	priorityPollInterval time.Duration
+	// during quietHours notifications are held back and polling slows down
+	// to quietPollInterval
+	quietHours        []config.TimeWindow
+	quietPollInterval time.Duration
+	// timezone is the zone dates are shown in
+	timezone *time.Location
+	// browserCommand replaces the platform's URL handler when set
+	browserCommand string
+	// downloadDir is where enclosures are downloaded to, and playerCommand
+	// what plays them
+	downloadDir   string
+	playerCommand string
+	// expireAfter is how old items of a feed get before they're marked
+	// read regardless, by feed URL
+	expireAfter map[string]time.Duration
+	// items count as read once scrolled to the end, or after being shown
+	// for markReadAfter; with neither, as soon as they're shown
+	markReadOnScroll bool
+	markReadAfter    time.Duration
+}
+
+// rerenderMsg redraws the current article, for when something other than
+// Update's own key handling has moved to another one. With scroll set the
+// article is scrolled to yOffset rather than to the top or saved progress.
+type rerenderMsg struct {
+	scroll  bool
+	yOffset int
+}
+
+type keyMap struct {
+	Up           key.Binding
+	Down         key.Binding
+	PageUp       key.Binding
+	PageDown     key.Binding
+	HalfPageUp   key.Binding
+	HalfPageDown key.Binding
+	Left         key.Binding
+	Right        key.Binding
+	FeedList     key.Binding
+	ArticleList  key.Binding
+	Top          key.Binding
+	PrevFeed     key.Binding
+	NextFeed     key.Binding
+	Pause        key.Binding
+	Star         key.Binding
+	Browser      key.Binding
+	FullText     key.Binding
+	Changes      key.Binding
+	Move         key.Binding
+	Refresh      key.Binding
+	RefreshAll   key.Binding
+	RefreshGroup key.Binding
+	Fresh        key.Binding
+	Stats        key.Binding
+	Yank         key.Binding
+	Share        key.Binding
+	Capture      key.Binding
+	ReadLater    key.Binding
+	Wayback      key.Binding
+	Install      key.Binding
+	Download     key.Binding
+	Play         key.Binding
+	Links        key.Binding
+	Open         key.Binding
+	Back         key.Binding
+	Subscribe    key.Binding
+	Manage       key.Binding
+	Mark         key.Binding
+	GotoMark     key.Binding
+	ToggleRead   key.Binding
+	MarkAllRead  key.Binding
+	UnreadOnly   key.Binding
+	SevereOnly   key.Binding
+	Author       key.Binding
+	Follow       key.Binding
+	Language     key.Binding
+	Palette      key.Binding
+	Find         key.Binding
+	Search       key.Binding
+	SearchIn     key.Binding
+	NextMatch    key.Binding
+	PrevMatch    key.Binding
+	Help         key.Binding
+	Quit         key.Binding
+}
+
+var defaultKeyMap = keyMap{
+	Up: key.NewBinding(
+		key.WithKeys("k", "up"),
+		key.WithHelp("k/up", "move up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("j", "down"),
+		key.WithHelp("j/down", "move down"),
+	),
+	PageUp: key.NewBinding(
+		key.WithKeys("pgup", "b"),
+		key.WithHelp("pgup/b", "page up"),
+	),
+	PageDown: key.NewBinding(
+		key.WithKeys("pgdown", " "),
+		key.WithHelp("pgdown/space", "page down"),
+	),
+	HalfPageUp: key.NewBinding(
+		key.WithKeys("ctrl+u"),
+		key.WithHelp("ctrl+u", "half page up"),
+	),
+	HalfPageDown: key.NewBinding(
+		key.WithKeys("ctrl+d"),
+		key.WithHelp("ctrl+d", "half page down"),
+	),
+	Left: key.NewBinding(
+		key.WithKeys("h", "left"),
+		key.WithHelp("h/left", "move left"),
+	),
+	Right: key.NewBinding(
+		key.WithKeys("l", "right"),
+		key.WithHelp("l/right", "move right"),
+	),
+	FeedList: key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "toggle feed list"),
+	),
+	ArticleList: key.NewBinding(
+		key.WithKeys("i"),
+		key.WithHelp("i", "list the feed's articles"),
+	),
+	Top: key.NewBinding(
+		key.WithKeys("T"),
+		key.WithHelp("T", "top stories"),
+	),
+	PrevFeed: key.NewBinding(
+		key.WithKeys("["),
+		key.WithHelp("[", "previous feed"),
+	),
+	NextFeed: key.NewBinding(
+		key.WithKeys("]"),
+		key.WithHelp("]", "next feed"),
+	),
+	Pause: key.NewBinding(
+		key.WithKeys("P"),
+		key.WithHelp("P", "pause/unpause feed"),
+	),
+	Fresh: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "jump to new items"),
+	),
+	Refresh: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "refresh feed"),
+	),
+	RefreshAll: key.NewBinding(
+		key.WithKeys("R"),
+		key.WithHelp("R", "refresh all feeds"),
+	),
+	RefreshGroup: key.NewBinding(
+		key.WithKeys("C"),
+		key.WithHelp("C", "refresh a feed group"),
+	),
+	Browser: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "open article in browser"),
+	),
+	FullText: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "toggle full article"),
+	),
+	Changes: key.NewBinding(
+		key.WithKeys("D"),
+		key.WithHelp("D", "toggle what changed"),
+	),
+	Star: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "star/unstar article"),
+	),
+	Move: key.NewBinding(
+		key.WithKeys("M"),
+		key.WithHelp("M", "follow moved feed"),
+	),
+	Stats: key.NewBinding(
+		key.WithKeys("S"),
+		key.WithHelp("S", "toggle fetch stats"),
+	),
+	Yank: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "copy link"),
+	),
+	Share: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "share article"),
+	),
+	Capture: key.NewBinding(
+		key.WithKeys("E"),
+		key.WithHelp("E", "capture article"),
+	),
+	ReadLater: key.NewBinding(
+		key.WithKeys("w"),
+		key.WithHelp("w", "save for later"),
+	),
+	Wayback: key.NewBinding(
+		key.WithKeys("W"),
+		key.WithHelp("W", "wayback machine snapshot"),
+	),
+	Install: key.NewBinding(
+		key.WithKeys("I"),
+		key.WithHelp("I", "copy install command"),
+	),
+	Download: key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "download enclosure"),
+	),
+	Play: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "play enclosure"),
+	),
+	Links: key.NewBinding(
+		key.WithKeys("L"),
+		key.WithHelp("L", "select links"),
+	),
+	Open: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "open link"),
+	),
+	Back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "leave link selection"),
+	),
+	Subscribe: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "subscribe to linked site"),
+	),
+	Manage: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "manage subscriptions"),
+	),
+	Mark: key.NewBinding(
+		key.WithKeys("B"),
+		key.WithHelp("B<letter>", "set mark"),
+	),
+	ToggleRead: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "mark read/unread"),
+	),
+	MarkAllRead: key.NewBinding(
+		key.WithKeys("A"),
+		key.WithHelp("A", "mark feed read"),
+	),
+	UnreadOnly: key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u", "unread only"),
+	),
+	SevereOnly: key.NewBinding(
+		key.WithKeys("!"),
+		key.WithHelp("!", "high/critical only"),
+	),
+	Author: key.NewBinding(
+		key.WithKeys("@"),
+		key.WithHelp("@", "articles by author"),
+	),
+	Follow: key.NewBinding(
+		key.WithKeys("+"),
+		key.WithHelp("+", "follow author"),
+	),
+	Language: key.NewBinding(
+		key.WithKeys("g"),
+		key.WithHelp("g", "articles by language"),
+	),
+	GotoMark: key.NewBinding(
+		key.WithKeys("'", "`"),
+		key.WithHelp("'<letter>", "jump to mark"),
+	),
+	Palette: key.NewBinding(
+		key.WithKeys("ctrl+p"),
+		key.WithHelp("ctrl+p", "command palette"),
+	),
+	Find: key.NewBinding(
+		key.WithKeys("ctrl+f"),
+		key.WithHelp("ctrl+f", "find feed or article"),
+	),
+	Search: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "search articles"),
+	),
+	SearchIn: key.NewBinding(
+		key.WithKeys("F"),
+		key.WithHelp("F", "search in article"),
+	),
+	NextMatch: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "next match"),
+	),
+	PrevMatch: key.NewBinding(
+		key.WithKeys("N"),
+		key.WithHelp("N", "previous match"),
+	),
+	Help: key.NewBinding(
+		key.WithKeys("?"),
+		key.WithHelp("?", "toggle help"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("q", "esc", "ctrl+c"),
+		key.WithHelp("q/esc/<C-c>", "quit"),
+	),
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view. It's part
+// of the key.Map interface.
+func (k keyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Help, k.Quit}
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (k keyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.PageUp, k.PageDown, k.HalfPageUp, k.HalfPageDown, k.Left, k.Right, k.UnreadOnly, k.SevereOnly},                                                                                            // first column
+		{k.FeedList, k.ArticleList, k.Top, k.Author, k.Follow, k.Language, k.PrevFeed, k.NextFeed, k.Fresh, k.Refresh, k.RefreshAll, k.RefreshGroup, k.Pause, k.Move, k.Stats},                                     // second column
+		{k.Star, k.ToggleRead, k.MarkAllRead, k.Browser, k.FullText, k.Changes, k.Yank, k.Share, k.Capture, k.ReadLater, k.Wayback, k.Install, k.Links, k.Open, k.Subscribe, k.Back, k.Manage, k.Mark, k.GotoMark}, // third column
+		{k.Palette, k.Find, k.Search, k.SearchIn, k.NextMatch, k.PrevMatch, k.Download, k.Play, k.Help, k.Quit},                                                                                                    // fourth column
+	}
+}
+
+func (m model) Init() tea.Cmd {
+	cmds := []tea.Cmd{watchResizeCmd(), spinner.Tick, autosaveCmd()}
+	for i := range m.feedSlice {
+		if m.loading[i] {
+			cmds = append(cmds, fetchFeedCmd(m.ctx, i, m.feedUrls[i], m.fetcher))
+		}
+		cmds = append(cmds, m.scheduleRefresh(i))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var (
+		cmd  tea.Cmd
+		cmds []tea.Cmd
+	)
+
+	rerender := false
+	// what the viewport gets to scroll by, at the end
+	viewportMsg := msg
+
+	switch msg := msg.(type) {
+	case feedFetchedMsg:
+		index, ok := m.indexOf(msg.index, msg.feedUrl)
+		if !ok {
+			// unsubscribed from, or moved, while it was being fetched
+			return m, nil
+		}
+		msg.index = index
+		firstLoad := m.loading[msg.index]
+		if firstLoad {
+			delete(m.loading, msg.index)
+			if len(m.loading) == 0 {
+				log.Printf("timing: %d feeds loaded in %s", len(m.feedSlice), time.Since(m.loadStarted))
+			}
+		}
+		manual := m.refreshing[msg.index]
+		delete(m.refreshing, msg.index)
+		if msg.err != nil {
+			log.Println(msg.err)
+			// kept for the feed list until a fetch works again
+			m.feedErrors[msg.index] = msg.err
+			// offline, the archive (or for a sync server's feeds, the cache)
+			// still has what was fetched before, which is better than
+			// nothing but no match for what's already shown
+			archived := ""
+			if msg.feed != nil && m.feedSlice[msg.index].Len() == 0 {
+				if title, ok := m.feedTitles[msg.feedUrl]; ok {
+					msg.feed.Title = title
+				}
+				m.hideLanguages(msg.feedUrl, msg.feed)
+				m.feedSlice[msg.index] = *msg.feed
+				archived = fmt.Sprintf(" (showing the %d saved articles)", msg.feed.Len())
+			}
+			switch {
+			case manual:
+				cmds = append(cmds, m.setStatus(fmt.Sprintf("Couldn't refresh %s: %s%s", m.feedUrls[msg.index], msg.err, archived)))
+			case firstLoad:
+				cmds = append(cmds, m.setStatus(fmt.Sprintf("Couldn't load %s: %s%s", m.feedUrls[msg.index], msg.err, archived)))
+			case archived == "":
+				// background refreshes fail quietly
+				return m, nil
+			}
+			if msg.index == m.feedSliceIndex {
+				// swap the loading message for the error
+				cmds = append(cmds, func() tea.Msg { return rerenderMsg{} })
+			}
+			return m, tea.Batch(cmds...)
+		}
+		delete(m.feedErrors, msg.index)
+		if title, ok := m.feedTitles[msg.feedUrl]; ok {
+			msg.feed.Title = title
+		}
+		m.hideLanguages(msg.feedUrl, msg.feed)
+		m.findPressReleases(msg.index, msg.feed)
+		// a feed that was never loaded has no baseline to compare against, so
+		// only notify about items that appear on later fetches
+		previous := m.feedSlice[msg.index]
+		if t := m.tracker(msg.feedUrl); t != nil {
+			m.applyTrackedState(t, msg.feedUrl, previous, msg.feed)
+		}
+		if previous.Len() > 0 {
+			if fresh := newItems(previous, *msg.feed); len(fresh) > 0 {
+				m.addFresh(msg.index, fresh)
+				if m.shouldNotify(msg.index) {
+					cmds = append(cmds, m.notifyNewItemsCmd(msg.feed.Title, fresh))
+				} else if notable := m.notableItems(msg.index, msg.feed, fresh); len(notable) > 0 {
+					cmds = append(cmds, m.notifyNewItemsCmd(msg.feed.Title, notable))
+				}
+			}
+		}
+		if msg.movedTo != "" {
+			cmds = append(cmds, m.feedMoved(msg.index, msg.movedTo))
+		}
+		if msg.index == m.feedSliceIndex {
+			// items may have changed under their old keys, so render the
+			// neighbours again
+			for key := range m.prerendered {
+				delete(m.prerendered, key)
+			}
+			// stay on the article being read, wherever it ended up in the
+			// refreshed feed
+			reading := m.feedIndex
+			m.feedIndex = 0
+			if reading < previous.Len() {
+				current := store.ItemKey(previous.Items[reading])
+				for i, item := range msg.feed.Items {
+					if store.ItemKey(item) == current {
+						m.feedIndex = i
+						break
+					}
+				}
+			}
+			rerender = true
+		}
+		m.feedSlice[msg.index] = *msg.feed
+		m.expireOld(msg.index)
+		m.updateAuthorFeeds()
+
+	case refreshTickMsg:
+		index, ok := m.indexOf(msg.index, msg.feedUrl)
+		if !ok {
+			// the feed's gone, or moved and polled under its new URL
+			return m, nil
+		}
+		msg.index = index
+		// keep the loop going even while paused so unpausing picks it back up
+		cmds = append(cmds, m.scheduleRefresh(msg.index))
+		if !m.isPaused(msg.index) {
+			cmds = append(cmds, fetchFeedCmd(m.ctx, msg.index, m.feedUrls[msg.index], m.fetcher))
+		}
+
+	case rerenderMsg:
+		rerender = true
+
+	case reloadMsg:
+		cmds = append(cmds, m.reloadConfig())
+		rerender = true
+
+	case autosaveMsg:
+		m.saveState()
+		cmds := []tea.Cmd{autosaveCmd(), m.syncReadState(), m.updateBackends()}
+		if m.readingLog.Due(time.Now()) {
+			cmds = append(cmds, commitReadingLogCmd(m.readingLog))
+		}
+		return m, tea.Batch(cmds...)
+
+	case readSyncedMsg:
+		cmds = append(cmds, m.readSynced(msg))
+
+	case backendUpdatedMsg:
+		cmds = append(cmds, m.backendUpdated(msg))
+
+	case readingLogCommittedMsg:
+		if msg.err != nil {
+			log.Println(msg.err)
+			cmds = append(cmds, m.setStatus("Couldn't commit the reading log: "+msg.err.Error()))
+		}
+
+	case markReadMsg:
+		// only if the reader stayed on the item the whole time
+		if msg.key == m.shown && m.feedIndex < m.feedSlice[m.feedSliceIndex].Len() {
+			m.markRead(m.feedSlice[m.feedSliceIndex].Items[m.feedIndex])
+		}
+		return m, nil
+
+	case feedCheckedMsg:
+		cmds = append(cmds, m.feedChecked(msg))
+
+	case downloadedMsg:
+		cmds = append(cmds, m.downloaded(msg))
+
+	case downloadTickMsg:
+		// keep redrawing the progress for as long as there's a download
+		if len(m.downloads) > 0 {
+			cmds = append(cmds, downloadTickCmd())
+		}
+
+	case fullTextMsg:
+		cmds = append(cmds, m.fullTextFetched(msg))
+
+	case sharedMsg:
+		cmds = append(cmds, m.shared(msg))
+
+	case savedForLaterMsg:
+		cmds = append(cmds, m.savedForLater(msg))
+
+	case waybackMsg:
+		cmds = append(cmds, m.waybackDone(msg))
+
+	case subscribedMsg:
+		cmds = append(cmds, m.subscribed(msg))
+
+	case capturedMsg:
+		cmds = append(cmds, m.captured(msg))
+
+	case prerenderedMsg:
+		if msg.content != "" {
+			m.prerendered[msg.key] = msg.content
+		}
+
+	case spinner.TickMsg:
+		// let the spinner stop once everything has loaded
+		if len(m.loading) == 0 && len(m.refreshing) == 0 {
+			return m, nil
+		}
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case clearStatusMsg:
+		if msg.id == m.statusID {
+			m.status = ""
+		}
+		return m, nil
+
+	case resizePollMsg:
+		cmds = append(cmds, watchResizeCmd())
+		if msg.width > 0 && (msg.width != m.width || msg.height != m.height) {
+			// handle it exactly as if the terminal had told us
+			resized, cmd := m.Update(tea.WindowSizeMsg{Width: msg.width, Height: msg.height})
+			return resized, tea.Batch(append(cmds, cmd)...)
+		}
+		return m, tea.Batch(cmds...)
+
+	case linkPreviewMsg:
+		m.linkPreviews[msg.url] = msg.preview
+		return m, nil
+
+	case tea.KeyMsg:
+		// link selection swallows navigation keys so they move the cursor
+		// instead of scrolling the article underneath
+		if m.linkMode {
+			return m.updateLinkSelection(msg)
+		}
+		if m.picker.kind != pickerClosed {
+			return m.updatePicker(msg)
+		}
+		if m.search.open {
+			return m.updateSearch(msg)
+		}
+		if m.inArticle.typing {
+			return m.updateArticleSearch(msg)
+		}
+		if m.subscriptions.open {
+			return m.updateSubscriptions(msg)
+		}
+		if m.pendingMark != 0 {
+			return m.updateMark(msg)
+		}
+		if m.articleListMode {
+			return m.updateArticleList(msg)
+		}
+		if m.feedListMode {
+			return m.updateFeedList(msg)
+		}
+
+		viewportMsg = scrollKeyMsg(msg)
+		switch {
+		// while searching the article n and N step through the matches, and
+		// esc ends the search rather than the reader
+		case key.Matches(msg, defaultKeyMap.NextMatch) && m.searchingArticle():
+			cmds = append(cmds, m.nextMatch(false))
+		case key.Matches(msg, defaultKeyMap.PrevMatch) && m.searchingArticle():
+			cmds = append(cmds, m.nextMatch(true))
+		case key.Matches(msg, defaultKeyMap.Back) && m.searchingArticle():
+			m.clearArticleSearch()
+		case key.Matches(msg, defaultKeyMap.SearchIn):
+			m.openArticleSearch()
+			return m, nil
+		case key.Matches(msg, defaultKeyMap.Links):
+			if m.feedIndex < m.feedSlice[m.feedSliceIndex].Len() {
+				item := m.feedSlice[m.feedSliceIndex].Items[m.feedIndex]
+				m.links = appendLinks(extractLinks(item.Description+item.Content, item.Link), itemLinks(item))
+				m.linkIndex = 0
+				m.linkMode = true
+				return m, m.previewSelectedLink()
+			}
+		case key.Matches(msg, defaultKeyMap.Left):
+			if previous := m.previousArticle(); previous >= 0 {
+				m.feedIndex = previous
+				rerender = true
+			} else if filter := m.articleFilter(); filter != "" && m.feedIndex > 0 {
+				cmds = append(cmds, m.setStatus("No "+filter+" before this one"))
+			}
+		case key.Matches(msg, defaultKeyMap.Right):
+			if next := m.nextArticle(); next >= 0 {
+				m.feedIndex = next
+				rerender = true
+			} else if filter := m.articleFilter(); filter != "" && m.feedIndex < getFeedLengthOrZero(m.feedSlice[m.feedSliceIndex]) {
+				cmds = append(cmds, m.setStatus("No "+filter+" after this one"))
+			}
+		case key.Matches(msg, defaultKeyMap.UnreadOnly):
+			cmds = append(cmds, m.toggleUnreadOnly())
+		case key.Matches(msg, defaultKeyMap.SevereOnly):
+			cmds = append(cmds, m.toggleSevereOnly())
+		case key.Matches(msg, defaultKeyMap.PrevFeed):
+			if m.feedSliceIndex > 0 {
+				m.feedSliceIndex--
+				m.feedIndex = 0
+				rerender = true
+			}
+		case key.Matches(msg, defaultKeyMap.NextFeed):
+			if m.feedSliceIndex < len(m.feedSlice)-1 {
+				m.feedSliceIndex++
+				m.feedIndex = 0
+				rerender = true
+			}
+		case key.Matches(msg, defaultKeyMap.Pause):
+			cmds = append(cmds, m.togglePaused(m.feedSliceIndex))
+		case key.Matches(msg, defaultKeyMap.Star):
+			cmds = append(cmds, m.toggleStar())
+		case key.Matches(msg, defaultKeyMap.Fresh):
+			if m.jumpToFresh() {
+				rerender = true
+			}
+		case key.Matches(msg, defaultKeyMap.Move):
+			cmds = append(cmds, m.updateMovedFeed(m.feedSliceIndex))
+		case key.Matches(msg, defaultKeyMap.Stats):
+			m.statsMode = !m.statsMode
+		case key.Matches(msg, defaultKeyMap.Browser):
+			cmds = append(cmds, m.openInBrowser())
+		case key.Matches(msg, defaultKeyMap.FullText):
+			cmds = append(cmds, m.toggleFullText())
+		case key.Matches(msg, defaultKeyMap.Changes):
+			cmds = append(cmds, m.toggleChanges())
+		case key.Matches(msg, defaultKeyMap.Yank):
+			if m.feedIndex < m.feedSlice[m.feedSliceIndex].Len() {
+				cmds = append(cmds, m.yank(m.feedSlice[m.feedSliceIndex].Items[m.feedIndex].Link))
+			}
+		case key.Matches(msg, defaultKeyMap.Share):
+			cmds = append(cmds, m.shareArticle())
+		case key.Matches(msg, defaultKeyMap.Capture):
+			cmds = append(cmds, m.captureArticle())
+		case key.Matches(msg, defaultKeyMap.ReadLater):
+			cmds = append(cmds, m.saveForLater())
+		case key.Matches(msg, defaultKeyMap.Wayback):
+			cmds = append(cmds, m.chooseWayback())
+		case key.Matches(msg, defaultKeyMap.Install):
+			cmds = append(cmds, m.copyInstallCommand())
+		case key.Matches(msg, defaultKeyMap.Download):
+			cmds = append(cmds, m.downloadEnclosure())
+		case key.Matches(msg, defaultKeyMap.Play):
+			cmds = append(cmds, m.playEnclosure())
+		case key.Matches(msg, defaultKeyMap.Refresh):
+			cmds = append(cmds, m.refreshFeeds([]int{m.feedSliceIndex}))
+		case key.Matches(msg, defaultKeyMap.RefreshAll):
+			var all []int
+			for i := range m.feedSlice {
+				all = append(all, i)
+			}
+			cmds = append(cmds, m.refreshFeeds(all))
+		case key.Matches(msg, defaultKeyMap.RefreshGroup):
+			cmds = append(cmds, m.openGroupPicker())
+		case key.Matches(msg, defaultKeyMap.Manage):
+			m.openSubscriptions()
+		case key.Matches(msg, defaultKeyMap.FeedList):
+			m.toggleFeedList()
+			return m.Update(rerenderMsg{})
+		case key.Matches(msg, defaultKeyMap.Back) && m.fromArticleList:
+			m.openArticleList(m.articleListKind)
+			return m, nil
+		case key.Matches(msg, defaultKeyMap.ArticleList):
+			m.openArticleList(articleListFeed)
+			return m, nil
+		case key.Matches(msg, defaultKeyMap.Top):
+			m.openArticleList(articleListTop)
+			return m, nil
+		case key.Matches(msg, defaultKeyMap.Author):
+			cmd := m.chooseAuthor(pickerAuthor, "list")
+			return m, cmd
+		case key.Matches(msg, defaultKeyMap.Follow):
+			cmd := m.chooseAuthor(pickerFollow, "follow")
+			return m, cmd
+		case key.Matches(msg, defaultKeyMap.Language):
+			cmd := m.chooseLanguage()
+			return m, cmd
+		case key.Matches(msg, defaultKeyMap.Mark):
+			m.pendingMark = 'm'
+			return m, nil
+		case key.Matches(msg, defaultKeyMap.ToggleRead):
+			cmd := m.toggleRead()
+			return m, cmd
+		case key.Matches(msg, defaultKeyMap.MarkAllRead):
+			cmd := m.markFeedsRead(m.feedSlice[m.feedSliceIndex].Title, []int{m.feedSliceIndex})
+			return m, cmd
+		case key.Matches(msg, defaultKeyMap.GotoMark):
+			m.pendingMark = '\''
+			return m, nil
+		case key.Matches(msg, defaultKeyMap.Palette):
+			m.openPalette()
+			return m, nil
+		case key.Matches(msg, defaultKeyMap.Find):
+			m.openFinder()
+			return m, nil
+		case key.Matches(msg, defaultKeyMap.Search):
+			m.openSearch()
+			return m, nil
+		case key.Matches(msg, defaultKeyMap.Help):
+			m.help.ShowAll = !m.help.ShowAll
+		case key.Matches(msg, defaultKeyMap.Quit):
+			return m, m.quit()
+		}
+
+	case tea.WindowSizeMsg:
+		// set the width on the help menu if necessary (truncate if required)
+		m.help.Width = msg.Width
+		m.width = msg.Width
+		m.height = msg.Height
+
+		verticalMargins := headerHeight + breadcrumbHeight + footerHeight
+
+		if !m.ready {
+			// Since this program is using the full size of the viewport we need
+			// to wait until we've received the window dimensions before we
+			// can initialize the viewport. The initial dimensions come in
+			// quickly, though asynchronously, which is why we wait for them
+			// here.
+			m.viewport = viewport.Model{
+				Width:  m.articleWidth(),
+				Height: msg.Height - verticalMargins,
+			}
+			m.viewport.HighPerformanceRendering = useHighPerformanceRenderer
+
+			// render content
+			rerender = true
+
+			// This is only necessary for high performance rendering, which in
+			// most cases you won't need.
+			//
+			// Render the viewport one line below the header.
+			m.viewport.YPosition = headerHeight + breadcrumbHeight + 1
+		} else {
+			// articles are wrapped to fit, so a new width means rewrapping
+			if m.articleWidth() != m.viewport.Width {
+				m.renderers = make(map[int]*glamour.TermRenderer)
+				rerender = true
+			}
+			m.viewport.Width = m.articleWidth()
+			m.viewport.Height = msg.Height - verticalMargins
+		}
+		if m.articleListMode {
+			m.articleList.SetSize(m.viewport.Width, m.viewport.Height)
+		}
+
+		if useHighPerformanceRenderer {
+			// Render (or re-render) the whole viewport. Necessary both to
+			// initialize the viewport and when the window is resized.
+			//
+			// This is needed for high-performance rendering only.
+			cmds = append(cmds, viewport.Sync(m.viewport))
+		}
+	}
+
+	if rerender {
+		// the content that will be rendered
+		var content string
+		// the item being shown, and how far through it the reader last got
+		var shown string
+		var resumeAt float64
+		m.lazy = nil
+		// if the feed is empty
+		// this case would also handle where we index out of bounds, but that case
+		// should not be handled here; it should already be handled where we attempt
+		// to increment/decrement the feedIndex
+		if m.loading[m.feedSliceIndex] {
+			content = "Loading this feed..."
+		} else if m.feedIndex >= m.feedSlice[m.feedSliceIndex].Len() {
+			content = "No content here!"
+			if err, ok := m.feedErrors[m.feedSliceIndex]; ok {
+				content = lipgloss.NewStyle().Width(m.viewport.Width).Render(fmt.Sprintf(
+					"Couldn't load this feed: %s\n\nPress %s to try again.",
+					err, defaultKeyMap.Refresh.Help().Key,
+				))
+			}
+		} else {
+			item := m.feedSlice[m.feedSliceIndex].Items[m.feedIndex]
+			shown = store.ItemKey(item)
+			m.seen(m.feedSliceIndex, shown)
+			resumeAt = m.progress.Get(item)
+			start := time.Now()
+			if prerendered, ok := m.prerendered[prerenderKey{item: shown, width: m.viewport.Width}]; ok {
+				content = prerendered
+				log.Printf("timing: %q was rendered ahead of time", item.Title)
+			} else {
+				html, display := m.articleHTML(m.feedSliceIndex, m.feedIndex)
+				rendered, err := m.renderArticle(html, m.converterFor(m.feedSliceIndex), display)
+				if err != nil {
+					log.Println(err)
+					rendered = "Couldn't render this article: " + err.Error()
+				}
+				content = rendered
+				log.Printf("timing: %q rendered in %s", item.Title, time.Since(start))
+			}
+			cmds = append(cmds, m.prerenderNeighbours())
+			if !m.marksReadLater() {
+				m.markRead(item)
+			} else if m.markReadAfter > 0 && shown != m.shown {
+				cmds = append(cmds, markReadCmd(shown, m.markReadAfter))
+			}
+		}
+		if shown != m.shown {
+			m.keptUnread = ""
+			// the search is kept to look for again, but its matches were
+			// in the last item
+			m.inArticle.re, m.inArticle.lines = nil, nil
+		}
+		m.setContent(content)
+		// the same item again, refreshed say, stays scrolled where it was
+		if shown != m.shown {
+			m.viewport.YOffset = 0
+			if resumeAt > 0 {
+				if offset := int(resumeAt * float64(m.contentLines(content)-m.viewport.Height)); offset > 0 {
+					m.viewport.YOffset = offset
+				}
+			}
+		}
+		if r, ok := msg.(rerenderMsg); ok && r.scroll {
+			m.viewport.YOffset = r.yOffset
+			resumeAt = 0
+		}
+		m.renderAhead()
+		if shown != m.shown {
+			// moving on to another item is a good time to save where the
+			// last one was left
+			if err := m.progress.Save(); err != nil {
+				log.Println(err)
+			}
+			if resumeAt > 0 {
+				cmds = append(cmds, m.setStatus(fmt.Sprintf("Resumed at %.f%% read", resumeAt*100)))
+			}
+			m.shown = shown
+		}
+		m.ready = true
+		if m.setWindowTitle {
+			cmds = append(cmds, setWindowTitleCmd(m.windowTitle()))
+		}
+	}
+
+	// Because we're using the viewport's default update function (with pager-
+	// style navigation) it's important that the viewport's update function:
+	//
+	// * Receives messages from the Bubble Tea runtime
+	// * Returns commands to the Bubble Tea runtime
+	//
+	// Keys are the exception: it hardcodes its own, so it only sees the
+	// scrolling bindings, as the keys it knows them by.
+	m.viewport, cmd = m.viewport.Update(viewportMsg)
+	if useHighPerformanceRenderer {
+		cmds = append(cmds, cmd)
+	}
+	m.renderAhead()
+	if m.feedIndex < m.feedSlice[m.feedSliceIndex].Len() {
+		item := m.feedSlice[m.feedSliceIndex].Items[m.feedIndex]
+		// short items always read as 100%, so only long ones are remembered
+		m.progress.Set(item, m.scrollPercent())
+		if m.markReadOnScroll && m.scrollPercent() >= 1 {
+			m.markRead(item)
+		}
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+// quit cancels outstanding requests, saves the reading progress and ends the
+// program.
+func (m model) quit() tea.Cmd {
+	m.cancel()
+	m.saveState()
+	return tea.Quit
+}
+
+// togglePaused flips the paused state of a feed and saves it to the config.
+// Unpausing a feed fetches it straight away rather than waiting for a restart.
+func (m model) togglePaused(index int) tea.Cmd {
+	if m.isVirtualFeed(index) {
+		return nil
+	}
+	feedUrl := m.feedUrls[index]
+	if m.pausedFeeds[feedUrl] {
+		delete(m.pausedFeeds, feedUrl)
+	} else {
+		m.pausedFeeds[feedUrl] = true
+	}
+
+	var paused []string
+	for _, u := range m.feedUrls {
+		if m.pausedFeeds[u] {
+			paused = append(paused, u)
+		}
+	}
+	viper.Set("pausedFeeds", paused)
+	if err := config.Save(); err != nil {
+		log.Println(err)
+	}
+
+	if m.pausedFeeds[feedUrl] {
+		return nil
+	}
+	return fetchFeedCmd(m.ctx, index, feedUrl, m.fetcher)
+}
+
+func (m model) updateLinkSelection(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, defaultKeyMap.Up):
+		if m.linkIndex > 0 {
+			m.linkIndex--
+			return m, m.previewSelectedLink()
+		}
+	case key.Matches(msg, defaultKeyMap.Down):
+		if m.linkIndex < len(m.links)-1 {
+			m.linkIndex++
+			return m, m.previewSelectedLink()
+		}
+	case key.Matches(msg, defaultKeyMap.Yank):
+		if len(m.links) > 0 {
+			cmd := m.yank(m.links[m.linkIndex].url)
+			return m, cmd
+		}
+	case key.Matches(msg, defaultKeyMap.Subscribe):
+		if len(m.links) > 0 {
+			cmd := m.subscribe(m.linkPreviews[m.links[m.linkIndex].url].feedUrl)
+			return m, cmd
+		}
+	case key.Matches(msg, defaultKeyMap.Open):
+		if len(m.links) > 0 {
+			if err := m.openURL(m.links[m.linkIndex].url); err != nil {
+				log.Println(err)
+				return m, m.setStatus("Couldn't open the link: " + err.Error())
+			}
+		}
+	case key.Matches(msg, defaultKeyMap.Back), key.Matches(msg, defaultKeyMap.Links):
+		m.linkMode = false
+	case key.Matches(msg, defaultKeyMap.Help):
+		m.help.ShowAll = !m.help.ShowAll
+	case msg.String() == "ctrl+c":
+		return m, m.quit()
+	}
+	return m, nil
+}
+
+// yank copies text to the clipboard and reports how that went.
+func (m *model) yank(text string) tea.Cmd {
+	if text == "" {
+		return m.setStatus("Nothing to copy")
+	}
+	if err := copyToClipboard(text); err != nil {
+		log.Println(err)
+		return m.setStatus("Couldn't copy: " + err.Error())
+	}
+	return m.setStatus("Copied " + text)
+}
+
+// previewSelectedLink kicks off a title fetch for the selected link, unless
+// one has already been made.
+func (m model) previewSelectedLink() tea.Cmd {
+	if len(m.links) == 0 {
+		return nil
+	}
+	link := m.links[m.linkIndex].url
+	if _, ok := m.linkPreviews[link]; ok || m.lowBandwidth {
+		return nil
+	}
+	return fetchLinkPreviewCmd(m.ctx, link, m.fetcher.Timeout)
+}
+
+func getFeedLengthOrZero(feed gofeed.Feed) int {
+	if feed.Len()-1 > 0 {
+		return feed.Len() - 1
+	} else {
+		return 0
+	}
+}
+
+// glamourStyle picks the glamour style articles are rendered with: the
+// theme's, unless the notty style is needed since it skips colors and
+// decorations, which is much easier on screen readers.
+func (m model) glamourStyle() string {
+	if m.accessible {
+		return "notty"
+	}
+	if m.asciiOnly {
+		return "ascii"
+	}
+	return m.theme
+}
+
+func assembleHeader(title string, m model) string {
+	if len(m.refreshing) > 0 {
+		if m.accessible {
+			title = "(refreshing) " + title
+		} else {
+			title = m.spinner.View() + " " + title
+		}
+	}
+	if m.accessible {
+		return lipgloss.NewStyle().
+			PaddingLeft(m.horzPadding).
+			PaddingTop(m.vertPadding).
+			PaddingBottom(m.vertPadding).
+			Render(title)
+	}
+	return lipgloss.NewStyle().
+		Bold(true).
+		Background(lipgloss.Color(m.feedAccent())).
+		Foreground(lipgloss.Color(m.textColor)).
+		PaddingLeft(m.horzPadding).
+		PaddingRight(m.horzPadding).
+		PaddingTop(m.vertPadding).
+		PaddingBottom(m.vertPadding).
+		Render(title)
+}
+
+// assembleBreadcrumb shows where in the feed ▸ article hierarchy the reader
+// currently is.
+func assembleBreadcrumb(m model) string {
+	feed := m.feedSlice[m.feedSliceIndex]
+	feedTitle := feed.Title
+	if feedTitle == "" {
+		feedTitle = feed.FeedLink
+	}
+
+	if m.isPaused(m.feedSliceIndex) {
+		feedTitle += " (paused)"
+	}
+	if _, ok := m.movedFeeds[m.feedUrls[m.feedSliceIndex]]; ok {
+		feedTitle += " (moved)"
+	}
+	if _, ok := m.feedErrors[m.feedSliceIndex]; ok {
+		feedTitle += " (failing)"
+	}
+
+	crumbs := []string{
+		fmt.Sprintf("Feed %d/%d: %s", m.feedSliceIndex+1, len(m.feedSlice), feedTitle),
+	}
+	if feed.Len() > 0 {
+		article := fmt.Sprintf("Article %d/%d", m.feedIndex+1, feed.Len())
+		if m.feedIndex < feed.Len() && feed.Items[m.feedIndex].Custom[fetch.RepublishedKey] != "" {
+			article += " (updated)"
+		}
+		if m.feedIndex < feed.Len() && m.stars.IsStarred(feed.Items[m.feedIndex]) {
+			article += m.symbol(" ★", " (starred)")
+		}
+		if m.feedIndex < feed.Len() {
+			item := feed.Items[m.feedIndex]
+			if _, ok := m.fullText[store.ItemKey(item)]; ok {
+				article += " (full article)"
+			}
+			if m.showingChanges[store.ItemKey(item)] {
+				article += " (what changed)"
+			} else if _, ok := item.Custom[fetch.PreviousKey]; ok {
+				article += " (edited)"
+			}
+		}
+		crumbs = append(crumbs, article)
+	}
+
+	var breadcrumb string
+	if m.accessible {
+		breadcrumb = lipgloss.NewStyle().
+			PaddingLeft(m.horzPadding).
+			Render(strings.Join(crumbs, " > "))
+	} else {
+		breadcrumb = strings.Repeat(" ", m.horzPadding) +
+			m.feedBadge(m.feedSliceIndex) +
+			lipgloss.NewStyle().
+				Foreground(lipgloss.Color(m.feedAccent())).
+				PaddingRight(m.horzPadding).
+				Render(strings.Join(crumbs, m.symbol(" ▸ ", " > ")))
+	}
+
+	// status messages sit at the right-hand end of the bar, and otherwise how
+	// downloads or loading are going or the banner for new items, if there
+	// are any
+	statusStyle := lipgloss.NewStyle().
+		Bold(!m.accessible).
+		PaddingRight(m.horzPadding)
+	text := m.status
+	if text == "" {
+		text = m.downloadBanner()
+	}
+	if text == "" && len(m.loading) > 0 {
+		text = m.loadingBanner()
+	}
+	if text == "" {
+		text = m.freshBanner()
+		if !m.accessible {
+			statusStyle = statusStyle.Foreground(lipgloss.Color(m.feedAccent()))
+		}
+	}
+	if text != "" {
+		status := statusStyle.Render(text)
+		gap := m.width - lipgloss.Width(breadcrumb) - lipgloss.Width(status)
+		if gap < 1 {
+			gap = 1
+		}
+		breadcrumb += strings.Repeat(" ", gap) + status
+	}
+	return lipgloss.NewStyle().MaxWidth(m.width).Render(breadcrumb)
+}
+
+func assembleFooter(authors []string, publishedTime time.Time, m model) string {
+	if m.accessible {
+		return assemblePlainFooter(authors, publishedTime, m)
+	}
+
+	var genericHorzPaddedStyle = lipgloss.NewStyle().
+		Background(lipgloss.Color(m.backgroundColor)).
+		PaddingLeft(m.horzPadding).
+		PaddingRight(m.horzPadding)
+
+	var progressFormattedStr = genericHorzPaddedStyle.Copy().
+		Bold(true).
+		Background(lipgloss.Color(m.feedAccent())).
+		Foreground(lipgloss.Color(m.textColor)).
+		Render(fmt.Sprintf("%3.f%%", m.scrollPercent()*100))
+
+	articleCounter := fmt.Sprintf(
+		"%d/%d articles, %d unread",
+		m.feedIndex, getFeedLengthOrZero(m.feedSlice[m.feedSliceIndex]),
+		m.readState.UnreadCount(m.feedSlice[m.feedSliceIndex]),
+	)
+	if filter := m.articleFilter(); filter != "" {
+		articleCounter += " (showing " + filter + ")"
+	}
+	if refreshed := m.lastRefreshed(); refreshed != "" {
+		articleCounter += ", " + refreshed
+	}
+	var articleCounterFormattedStr = genericHorzPaddedStyle.
+		Render(articleCounter)
+
+	var authorsFormattedStr = genericHorzPaddedStyle.Copy().
+		Align(lipgloss.Right).
+		BorderStyle(m.border(lipgloss.NormalBorder())).
+		BorderLeft(true).
+		BorderLeftForeground(lipgloss.Color(m.textColor)).
+		Render(strings.Join(authors, ", "))
+
+	var extrasFormattedStr string
+	extrasStyle := genericHorzPaddedStyle.Copy().
+		BorderStyle(m.border(lipgloss.NormalBorder())).
+		BorderLeft(true).
+		BorderLeftForeground(lipgloss.Color(m.textColor))
+
+	timeStyle := genericHorzPaddedStyle.Copy().
+		Align(lipgloss.Right).
+		BorderStyle(m.border(lipgloss.NormalBorder())).
+		BorderLeft(true).
+		BorderLeftForeground(lipgloss.Color(m.textColor))
+	var timeFormattedStr = timeStyle.Render(
+		"Last updated " + publishedTime.In(m.timezone).Format("2006-01-02 15:04:05 MST"),
+	)
+
+	// since the max width is passed into this function, create some whitespace
+	// to fill out the extra space.
+	consumedWidth := lipgloss.Width(progressFormattedStr) +
+		lipgloss.Width(articleCounterFormattedStr) +
+		lipgloss.Width(authorsFormattedStr) +
+		lipgloss.Width(timeFormattedStr)
+	// attachments and external links get what room is left, in brief and
+	// with just the date if that's what it takes
+	if m.feedIndex < m.feedSlice[m.feedSliceIndex].Len() {
+		item := m.feedSlice[m.feedSliceIndex].Items[m.feedIndex]
+		shortTime := timeStyle.Render(publishedTime.In(m.timezone).Format("2006-01-02"))
+		for _, fit := range []struct {
+			brief bool
+			time  string
+		}{{false, timeFormattedStr}, {true, timeFormattedStr}, {true, shortTime}} {
+			extras := m.itemExtras(item, fit.brief)
+			if len(extras) == 0 {
+				break
+			}
+			rendered := extrasStyle.Render(strings.Join(extras, "  "))
+			width := consumedWidth - lipgloss.Width(timeFormattedStr) +
+				lipgloss.Width(fit.time) + lipgloss.Width(rendered)
+			if width <= m.width {
+				extrasFormattedStr, timeFormattedStr, consumedWidth = rendered, fit.time, width
+				break
+			}
+		}
+	}
+	// the empty str in Render() will be turned into spaces as per bubble's
+	// whitespace docs.
+	spacerStr := lipgloss.NewStyle().
+		Background(lipgloss.Color(m.backgroundColor)).
+		Width(m.width - consumedWidth).
+		Render("")
+
+	return lipgloss.JoinHorizontal(
+		lipgloss.Bottom,
+		progressFormattedStr,
+		articleCounterFormattedStr,
+		spacerStr,
+		extrasFormattedStr,
+		authorsFormattedStr,
+		timeFormattedStr,
+	)
+}
+
+// assemblePlainFooter is the footer as a single line of text, with words
+// rather than colors and borders separating each part.
+func assemblePlainFooter(authors []string, publishedTime time.Time, m model) string {
+	parts := []string{
+		fmt.Sprintf("%.f%% read", m.scrollPercent()*100),
+		fmt.Sprintf("%d/%d articles", m.feedIndex, getFeedLengthOrZero(m.feedSlice[m.feedSliceIndex])),
+		fmt.Sprintf("%d unread", m.readState.UnreadCount(m.feedSlice[m.feedSliceIndex])),
+	}
+	if filter := m.articleFilter(); filter != "" {
+		parts[2] += " (showing " + filter + ")"
+	}
+	if refreshed := m.lastRefreshed(); refreshed != "" {
+		parts = append(parts, refreshed)
+	}
+	if m.feedIndex < m.feedSlice[m.feedSliceIndex].Len() {
+		parts = append(parts, m.itemExtras(m.feedSlice[m.feedSliceIndex].Items[m.feedIndex], false)...)
+	}
+	if len(authors) > 0 {
+		parts = append(parts, "by "+strings.Join(authors, ", "))
+	}
+	parts = append(parts,
+		"last updated "+publishedTime.In(m.timezone).Format("2006-01-02 15:04:05 MST"),
+	)
+	return lipgloss.NewStyle().
+		PaddingLeft(m.horzPadding).
+		MaxWidth(m.width).
+		Render(strings.Join(parts, " | "))
+}
+
+func (m model) View() string {
+	if !m.ready {
+		return "\n Loading content"
+	}
+
+	// return the help first if that's what was requested
+	if m.help.ShowAll {
+		helpView := m.help.View(defaultKeyMap)
+		return helpView
+	}
+
+	body := m.viewport.View()
+	if m.picker.kind != pickerClosed {
+		body = assemblePicker(m)
+	} else if m.search.open {
+		body = assembleSearch(m)
+	} else if m.subscriptions.open {
+		body = assembleSubscriptions(m)
+	} else if m.linkMode {
+		body = assembleLinkSelection(m)
+	} else if m.statsMode {
+		body = assembleStats(m)
+	} else if m.articleListMode {
+		body = lipgloss.NewStyle().
+			Height(m.viewport.Height).
+			MaxHeight(m.viewport.Height).
+			Render(m.articleList.View())
+	} else if m.inArticle.typing {
+		body = assembleArticleSearch(body, m)
+	}
+	if m.feedListMode {
+		body = lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			assembleFeedList(m),
+			lipgloss.NewStyle().MaxWidth(m.viewport.Width).Render(body),
+		)
+	}
+
+	if m.feedIndex < m.feedSlice[m.feedSliceIndex].Len() {
+		item := m.feedSlice[m.feedSliceIndex].Items[m.feedIndex]
+		var authorNames []string
+		for _, x := range item.Authors {
+			authorNames = append(authorNames, x.Name)
+		}
+
+		// try to pick a sensible "last updated" date
+		lastUpdatedDate := time.Unix(0, 0) // default to unix epoch
+		if item.PublishedParsed != nil {
+			lastUpdatedDate = *item.PublishedParsed
+		}
+		if item.UpdatedParsed != nil {
+			lastUpdatedDate = *item.UpdatedParsed
+		}
+
+		return fmt.Sprintf("%s\n%s\n%s\n%s",
+			assembleHeader(item.Title, m),
+			assembleBreadcrumb(m),
+			body,
+			assembleFooter(authorNames, lastUpdatedDate, m),
+		)
+	} else {
+		return fmt.Sprintf("%s\n%s\n%s\n%s",
+			assembleHeader("No content", m),
+			assembleBreadcrumb(m),
+			body,
+			assembleFooter(nil, time.Unix(0, 0), m),
+		)
+	}
+}
+
+// Options configure the reader.
+type Options struct {
+	// Context, if set, ends every fetch made by the reader when it's done.
+	// Quitting cancels them either way.
+	Context context.Context
+	// Feeds holds a feed for each of FeedUrls, in the same order; they're
+	// all fetched once the reader starts, so fetch.Placeholder will do.
+	Feeds    []gofeed.Feed
+	FeedUrls []string
+	// PausedFeeds, NotifyFeeds and PriorityFeeds are sets of feed URLs.
+	PausedFeeds   map[string]bool
+	NotifyFeeds   map[string]bool
+	PriorityFeeds map[string]bool
+	// NotifyAll has every feed's new items notified about, not just
+	// NotifyFeeds', and NotifyOverrides say whether particular feeds' are,
+	// by URL.
+	NotifyAll       bool
+	NotifyOverrides map[string]bool
+	// NotifyMinScore is the score from which items are notified about
+	// whichever feed they're in, if NotifyOnScore is set.
+	NotifyMinScore float64
+	NotifyOnScore  bool
+	// DesktopNotifications turns on desktop notifications for the feeds
+	// notified about; PushTargets are push services they're sent to as well.
+	DesktopNotifications bool
+	PushTargets          []push.Target
+	// ShareTargets are the chat rooms articles can be shared to.
+	ShareTargets []share.Target
+	// Capture is where articles can be captured to.
+	Capture capture.Config
+	// ReadLater is the read-later service articles are saved to.
+	ReadLater readlater.Config
+	// Languages are the languages read, by code; items in others are hidden,
+	// unless it's empty. FeedLanguages are the ones read in particular
+	// feeds, by URL, instead. Items in MutedLanguages are hidden everywhere.
+	Languages      map[string]bool
+	FeedLanguages  map[string]map[string]bool
+	MutedLanguages map[string]bool
+	// PressReleases is what's done with items whose text is in at least
+	// PressReleaseFeeds feeds: off, flag, collapse or mute.
+	PressReleases     string
+	PressReleaseFeeds int
+	// PaywallDomains are sites behind paywalls, whose articles are badged;
+	// PaywallArchiveURL, if set, opens them through an archive service, with
+	// the article's URL in place of {{url}}.
+	PaywallDomains    map[string]bool
+	PaywallArchiveURL string
+	// Groups names groups of feeds, mapping each name to feed URLs.
+	Groups map[string][]string
+	// FeedTitles rename feeds, by URL.
+	FeedTitles map[string]string
+	// Fetcher is used to fetch feeds and fetch link previews.
+	Fetcher fetch.Fetcher
+	// ReadState is required; items are marked read as they're shown.
+	ReadState *store.ReadState
+	// Stars is required too. The starred items make up a feed of their own
+	// after the others.
+	Stars *store.Stars
+	// FollowedAuthors each get a feed of their articles in the others, after
+	// the starred one.
+	FollowedAuthors []string
+	// Progress, if set, keeps the place in long items between sessions.
+	Progress *store.Progress
+	// ReadingLog, if set, has what's read and starred added to it.
+	ReadingLog *readinglog.Log
+	// Sync, if set, keeps the read state of the greader: feeds' items in step
+	// with the server they're from.
+	Sync *greader.Syncer
+	// Backends are where the feeds are from, the first Direct. The items of
+	// those that are trackers are marked read and starred there as they are
+	// here. Backend is the one new subscriptions are made with.
+	Backends backend.Backends
+	Backend  backend.Backend
+	// Converter turns article HTML into markdown; FeedConverters override it
+	// for individual feeds, keyed by URL.
+	Converter      *md.Converter
+	FeedConverters map[string]*md.Converter
+	// FeedDisplays change how individual feeds are rendered, keyed by URL.
+	FeedDisplays map[string]render.Display
+
+	// Theme is one of config.Themes or the path to a glamour style file.
+	Theme  string
+	Accent string
+	// FeedColors replace Accent for individual feeds, keyed by URL.
+	FeedColors map[string]string
+	// InstallCommands install a release of a feed's, with {{version}} for
+	// its version, by feed URL.
+	InstallCommands map[string]string
+	// TextColor and BackgroundColor are the theme's if empty.
+	TextColor       string
+	BackgroundColor string
+	HorzPadding     int
+	VertPadding     int
+
+	LowBandwidth   bool
+	Accessible     bool
+	ASCIIOnly      bool
+	SetWindowTitle bool
+	// UpdateMovedFeeds switches feeds that have moved permanently over to
+	// their new URL without asking.
+	UpdateMovedFeeds bool
+	// RefreshInterval is how often feeds are refetched, and
+	// PriorityRefreshInterval how often priority feeds are. Zero disables
+	// refetching.
+	RefreshInterval         time.Duration
+	PriorityRefreshInterval time.Duration
+	// QuietHours are times of day when there are no notifications and
+	// feeds are refetched at most every QuietRefreshInterval.
+	QuietHours           []config.TimeWindow
+	QuietRefreshInterval time.Duration
+	// Timezone is the zone dates are shown in; the system's if nil.
+	Timezone *time.Location
+	// MarkReadOnScroll and MarkReadAfter hold off marking items read until
+	// they've been scrolled to the end or shown for that long, whichever
+	// comes first.
+	MarkReadOnScroll bool
+	MarkReadAfter    time.Duration
+	// ExpireAfter marks items older than this read, by feed URL.
+	ExpireAfter map[string]time.Duration
+	// BrowserCommand opens links instead of the platform's default handler:
+	// a command line, with %s standing for the URL (or the URL added last).
+	BrowserCommand string
+	// DownloadDir is where enclosures are downloaded to, and PlayerCommand
+	// the command line they're played with, like BrowserCommand.
+	DownloadDir   string
+	PlayerCommand string
+	// Score, if set, rates items for the top stories, which otherwise go by
+	// age alone.
+	Score ScoreFunc
+}
+
+// ScoreFunc rates an item; the higher the score, the nearer the top of the
+// top stories it goes.
+type ScoreFunc func(feedUrl string, feed *gofeed.Feed, item *gofeed.Item) float64
+
+// New builds the reader's bubbletea model.
+func New(opts Options) tea.Model {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	m := model{
+		ctx:                  ctx,
+		cancel:               cancel,
+		feedIndex:            0,
+		help:                 help.NewModel(),
+		markdownConverter:    opts.Converter,
+		feedConverters:       opts.FeedConverters,
+		feedDisplays:         opts.FeedDisplays,
+		fetcher:              opts.Fetcher,
+		readState:            opts.ReadState,
+		stars:                opts.Stars,
+		progress:             opts.Progress,
+		readingLog:           opts.ReadingLog,
+		sync:                 opts.Sync,
+		backends:             opts.Backends,
+		backend:              opts.Backend,
+		tracked:              trackedBackends(opts.Backends),
+		lowBandwidth:         opts.LowBandwidth,
+		accessible:           opts.Accessible,
+		asciiOnly:            opts.ASCIIOnly,
+		setWindowTitle:       opts.SetWindowTitle,
+		updateMovedFeeds:     opts.UpdateMovedFeeds,
+		movedFeeds:           make(map[string]string),
+		feedErrors:           make(map[int]error),
+		feedTitles:           opts.FeedTitles,
+		groups:               opts.Groups,
+		collapsedGroups:      make(map[string]bool),
+		freshItems:           make(map[int][]string),
+		syndicated:           make(map[string]int),
+		loading:              make(map[int]bool),
+		refreshing:           make(map[int]bool),
+		renderers:            make(map[int]*glamour.TermRenderer),
+		prerendered:          make(map[prerenderKey]string),
+		fullText:             make(map[string]string),
+		fetchingFullText:     make(map[string]bool),
+		showingChanges:       make(map[string]bool),
+		downloadedFiles:      make(map[string]string),
+		loadStarted:          time.Now(),
+		spinner:              spinner.NewModel(),
+		marks:                make(map[rune]mark),
+		linkPreviews:         make(map[string]linkPreview),
+		theme:                resolveTheme(opts.Theme),
+		accent:               opts.Accent,
+		feedColors:           opts.FeedColors,
+		installCommands:      opts.InstallCommands,
+		textColor:            opts.TextColor,
+		backgroundColor:      opts.BackgroundColor,
+		horzPadding:          opts.HorzPadding,
+		vertPadding:          opts.VertPadding,
+		pollInterval:         opts.RefreshInterval,
+		priorityPollInterval: opts.PriorityRefreshInterval,
+		quietHours:           opts.QuietHours,
+		quietPollInterval:    opts.QuietRefreshInterval,
+		timezone:             opts.Timezone,
+		score:                opts.Score,
+		browserCommand:       opts.BrowserCommand,
+		downloadDir:          opts.DownloadDir,
+		playerCommand:        opts.PlayerCommand,
+		expireAfter:          opts.ExpireAfter,
+		markReadOnScroll:     opts.MarkReadOnScroll,
+		markReadAfter:        opts.MarkReadAfter,
+		feedSlice:            opts.Feeds,
+		feedUrls:             opts.FeedUrls,
+		pausedFeeds:          opts.PausedFeeds,
+		notifyFeeds:          opts.NotifyFeeds,
+		notifyAll:            opts.NotifyAll,
+		notifyOverrides:      opts.NotifyOverrides,
+		notifyMinScore:       opts.NotifyMinScore,
+		notifyOnScore:        opts.NotifyOnScore,
+		desktopNotifications: opts.DesktopNotifications,
+		pushTargets:          opts.PushTargets,
+		shareTargets:         opts.ShareTargets,
+		capture:              opts.Capture,
+		readLater:            opts.ReadLater,
+		languages:            opts.Languages,
+		feedLanguages:        opts.FeedLanguages,
+		mutedLanguages:       opts.MutedLanguages,
+		pressReleases:        opts.PressReleases,
+		pressReleaseFeeds:    opts.PressReleaseFeeds,
+		paywallDomains:       opts.PaywallDomains,
+		paywallArchiveURL:    opts.PaywallArchiveURL,
+		priorityFeeds:        opts.PriorityFeeds,
+		feedSliceIndex:       0,
+	}
+	if m.timezone == nil {
+		m.timezone = time.Local
+	}
+	if m.theme == "" {
+		m.theme = "dark"
+	}
+	m.applyTheme()
+	if m.pausedFeeds == nil {
+		// pausing a feed adds it to the set
+		m.pausedFeeds = make(map[string]bool)
+	}
+	if m.feedTitles == nil {
+		// renaming a feed adds to it
+		m.feedTitles = make(map[string]string)
+	}
+	// paused feeds keep their slot but aren't fetched
+	for i, feedUrl := range m.feedUrls {
+		if !m.isPaused(i) {
+			m.loading[i] = true
+		}
+		if title, ok := m.feedTitles[feedUrl]; ok {
+			m.feedSlice[i].Title = title
+		}
+	}
+	m.feedUrls = append(m.feedUrls, starredFeedUrl)
+	m.feedSlice = append(m.feedSlice, m.starredFeed())
+	for _, name := range opts.FollowedAuthors {
+		// filled in as the feeds load
+		m.appendFeed(authorFeedPrefix+name, gofeed.Feed{Title: name})
+	}
+	m.spinner.Spinner = spinner.Dot
+	if m.asciiOnly {
+		m.spinner.Spinner = spinner.Line
+	}
+	if m.asciiOnly {
+		m.help.ShortSeparator = " | "
+		m.help.Ellipsis = "..."
+	}
+	if m.accessible {
+		// separate help entries with text and drop the colors
+		m.help.ShortSeparator = " | "
+		m.help.Ellipsis = "..."
+		m.help.Styles = help.Styles{}
+	}
+	return m
+}
+
+// Run starts the reader and blocks until it's quit.
+func Run(opts Options) error {
+	parent := opts.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+	// in case the program ends some other way than through quit
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+	opts.Context = ctx
+
+	// create the bubbletea program with the starter model
+	p := tea.NewProgram(
+		New(opts),
+		tea.WithAltScreen(),
+		tea.WithMouseCellMotion(),
+	)
+
+	if opts.SetWindowTitle {
+		saveWindowTitle()
+		defer restoreWindowTitle()
+	}
+	stopListening := handleSignals(p)
+	err := p.Start()
+	stopListening()
+
+	// however the program ended, stop fetching and let the fetches already
+	// caching a feed finish, then save what's left to save
+	cancel()
+	if !opts.Fetcher.Limiter.Wait(fetchWaitTimeout) {
+		log.Println("gave up waiting for fetches to finish")
+	}
+	if err := opts.Progress.Save(); err != nil {
+		log.Println(err)
+	}
+	if err := opts.ReadingLog.Commit(); err != nil {
+		log.Println(err)
+	}
+	syncCtx, cancelSync := context.WithTimeout(context.Background(), syncWaitTimeout)
+	defer cancelSync()
+	if err := opts.Sync.Flush(syncCtx, opts.ReadState.IsKeyRead); err != nil {
+		log.Println(err)
+	}
+	return err
+}
@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mmcdole/gofeed"
+)
+
+// toggleUnreadOnly switches between showing every article and only the
+// unread ones.
+func (m *model) toggleUnreadOnly() tea.Cmd {
+	m.unreadOnly = !m.unreadOnly
+	if m.unreadOnly {
+		return m.setStatus("Showing unread articles only")
+	}
+	return m.setStatus("Showing all articles")
+}
+
+// stopsAt reports whether h and l stop at an item: every item does, unless
+// only unread articles or only severe advisories are being shown.
+func (m model) stopsAt(item *gofeed.Item) bool {
+	return (!m.unreadOnly || !m.readState.IsRead(item)) && (!m.severeOnly || severe(item))
+}
+
+// articleFilter names the articles being shown, if they aren't all of them.
+func (m model) articleFilter() string {
+	switch {
+	case m.unreadOnly && m.severeOnly:
+		return "unread high or critical advisories"
+	case m.unreadOnly:
+		return "unread articles"
+	case m.severeOnly:
+		return "high or critical advisories"
+	}
+	return ""
+}
+
+// previousArticle is the index of the article h goes back to, or -1 if
+// there's none: the one before, or while only some articles are shown the
+// one of them before.
+func (m model) previousArticle() int {
+	feed := m.feedSlice[m.feedSliceIndex]
+	for i := m.feedIndex - 1; i >= 0; i-- {
+		if m.stopsAt(feed.Items[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// nextArticle is the index of the article l goes on to, or -1 if there's
+// none: the one after, or while only some articles are shown the one of them
+// after.
+func (m model) nextArticle() int {
+	feed := m.feedSlice[m.feedSliceIndex]
+	for i := m.feedIndex + 1; i <= getFeedLengthOrZero(feed); i++ {
+		if m.stopsAt(feed.Items[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// unreadArticles leaves the read articles out of an article list's items.
+func unreadArticles(items []list.Item) []list.Item {
+	var unread []list.Item
+	for _, item := range items {
+		if article, ok := item.(articleItem); ok && !article.read {
+			unread = append(unread, item)
+		}
+	}
+	return unread
+}
@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/homielabs/golang-rss-client/internal/wayback"
+)
+
+// What can be done with the Wayback Machine, in the order they're offered.
+const (
+	waybackSave = iota
+	waybackOpen
+	waybackSaveAndOpen
+)
+
+var waybackChoices = []string{
+	"save a snapshot",
+	"open the latest snapshot",
+	"save a snapshot and open it",
+}
+
+type waybackMsg struct {
+	// snapshot is the URL of the snapshot saved or found
+	snapshot string
+	saved    bool
+	open     bool
+	err      error
+}
+
+// waybackCmd saves a snapshot of a page, or finds the latest, in the
+// background.
+func waybackCmd(ctx context.Context, page string, save bool, open bool) tea.Cmd {
+	return func() tea.Msg {
+		var snapshot string
+		var err error
+		if save {
+			snapshot, err = wayback.Save(ctx, page)
+		} else {
+			snapshot, err = wayback.Latest(ctx, page)
+		}
+		return waybackMsg{snapshot: snapshot, saved: save, open: open, err: err}
+	}
+}
+
+// chooseWayback asks what to do with the Wayback Machine for the article
+// being read.
+func (m *model) chooseWayback() tea.Cmd {
+	if m.feedIndex >= m.feedSlice[m.feedSliceIndex].Len() {
+		return m.setStatus("Nothing to archive")
+	}
+	if m.feedSlice[m.feedSliceIndex].Items[m.feedIndex].Link == "" {
+		return m.setStatus("This article has no link")
+	}
+	m.picker = newPicker(pickerWayback, "do what with the Wayback Machine?", waybackChoices)
+	return nil
+}
+
+// waybackChosen saves a snapshot of the article being read or finds its
+// latest one, as chosen.
+func (m *model) waybackChosen(chosen int) tea.Cmd {
+	link := m.feedSlice[m.feedSliceIndex].Items[m.feedIndex].Link
+	save := chosen != waybackOpen
+	status := "Looking up the latest snapshot" + m.symbol("…", "...")
+	if save {
+		status = "Saving a snapshot" + m.symbol("…", "...")
+	}
+	return tea.Batch(m.setStatus(status), waybackCmd(m.ctx, link, save, chosen != waybackSave))
+}
+
+// waybackDone reports the snapshot saved or found, opening it if that was
+// asked for.
+func (m *model) waybackDone(msg waybackMsg) tea.Cmd {
+	if msg.err != nil {
+		if errors.Is(msg.err, wayback.ErrNoSnapshot) {
+			return m.setStatus("The Wayback Machine has no snapshot of this article")
+		}
+		log.Println(msg.err)
+		if msg.saved {
+			return m.setStatus("Couldn't save a snapshot: " + msg.err.Error())
+		}
+		return m.setStatus("Couldn't look up snapshots: " + msg.err.Error())
+	}
+	if !msg.open {
+		return m.setStatus("Saved a snapshot at " + msg.snapshot)
+	}
+	if err := m.openURL(msg.snapshot); err != nil {
+		log.Println(err)
+		return m.setStatus("Couldn't open browser: " + err.Error())
+	}
+	return m.setStatus("Opened " + msg.snapshot)
+}
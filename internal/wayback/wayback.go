@@ -0,0 +1,92 @@
+// Package wayback asks the Internet Archive's Wayback Machine to save
+// snapshots of pages, and finds the latest ones it has.
+package wayback
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// saveURL is where a page's URL goes to have a snapshot of it saved
+	saveURL = "https://web.archive.org/save/"
+	// availableURL tells the latest snapshot of a page
+	availableURL = "https://archive.org/wayback/available"
+	// snapshotPath is in the URLs of snapshots
+	snapshotPath = "web.archive.org/web/"
+)
+
+// ErrNoSnapshot is returned when the Wayback Machine has no snapshot of a
+// page.
+var ErrNoSnapshot = errors.New("the Wayback Machine has no snapshot of it")
+
+// client waits a while, as saving a snapshot means the page is fetched.
+var client = &http.Client{Timeout: 2 * time.Minute}
+
+// Save has a snapshot of a page saved, returning its URL.
+func Save(ctx context.Context, page string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, saveURL+page, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", "golang-rss-client")
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("wayback: saving %s: %s", page, resp.Status)
+	}
+	// the snapshot is redirected to, or else named in the headers
+	if snapshot := resp.Request.URL.String(); strings.Contains(snapshot, snapshotPath) {
+		return snapshot, nil
+	}
+	if location := resp.Header.Get("Content-Location"); location != "" {
+		if snapshot, err := resp.Request.URL.Parse(location); err == nil {
+			return snapshot.String(), nil
+		}
+	}
+	// the latest one, which is the one just saved
+	return "https://" + snapshotPath + page, nil
+}
+
+// Latest is the URL of the latest snapshot of a page, or ErrNoSnapshot if
+// there's none.
+func Latest(ctx context.Context, page string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, availableURL+"?"+url.Values{"url": {page}}.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", "golang-rss-client")
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("wayback: looking up %s: %s", page, resp.Status)
+	}
+	var available struct {
+		Snapshots struct {
+			Closest struct {
+				Available bool   `json:"available"`
+				URL       string `json:"url"`
+			} `json:"closest"`
+		} `json:"archived_snapshots"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&available); err != nil {
+		return "", fmt.Errorf("wayback: looking up %s: %w", page, err)
+	}
+	closest := available.Snapshots.Closest
+	if !closest.Available || closest.URL == "" {
+		return "", ErrNoSnapshot
+	}
+	return strings.Replace(closest.URL, "http://", "https://", 1), nil
+}
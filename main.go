@@ -2,433 +2,680 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
-	"html"
 	"log"
+	"net/http"
+	_ "net/http/pprof"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
-	md "github.com/JohannesKaufmann/html-to-markdown"
-	"github.com/charmbracelet/bubbles/help"
-	"github.com/charmbracelet/bubbles/key"
-	"github.com/charmbracelet/bubbles/viewport"
-	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/glamour"
-	"github.com/charmbracelet/lipgloss"
+	"github.com/homielabs/golang-rss-client/internal/backend"
+	"github.com/homielabs/golang-rss-client/internal/capture"
+	"github.com/homielabs/golang-rss-client/internal/config"
+	"github.com/homielabs/golang-rss-client/internal/fetch"
+	"github.com/homielabs/golang-rss-client/internal/greader"
+	"github.com/homielabs/golang-rss-client/internal/miniflux"
+	"github.com/homielabs/golang-rss-client/internal/nextcloud"
+	"github.com/homielabs/golang-rss-client/internal/opml"
+	"github.com/homielabs/golang-rss-client/internal/push"
+	"github.com/homielabs/golang-rss-client/internal/readinglog"
+	"github.com/homielabs/golang-rss-client/internal/readlater"
+	"github.com/homielabs/golang-rss-client/internal/render"
+	"github.com/homielabs/golang-rss-client/internal/share"
+	"github.com/homielabs/golang-rss-client/internal/store"
+	"github.com/homielabs/golang-rss-client/internal/ui"
 	"github.com/mmcdole/gofeed"
 	"github.com/spf13/viper"
 )
 
-const (
-	headerHeight               = 3
-	footerHeight               = 3
-	useHighPerformanceRenderer = false
-)
+// subcommands run instead of the reader when named as the first argument.
+var subcommands = map[string]func(args []string) int{
+	"status":   runStatus,
+	"export":   runExport,
+	"query":    runQuery,
+	"backup":   runBackup,
+	"restore":  runRestore,
+	"calendar": runCalendar,
+}
 
-type model struct {
-	feedSlice         []gofeed.Feed
-	feedSliceIndex    int
-	feedIndex         int
-	ready             bool
-	viewport          viewport.Model
-	help              help.Model
-	markdownConverter md.Converter
-	// config-based
-	accent          string
-	textColor       string
-	backgroundColor string
-	horzPadding     int
-	vertPadding     int
-	fetchTimeout    int
+// openLogFile switches log output over to the log file, since the terminal
+// belongs to the UI (or to a status bar reading our output).
+func openLogFile() *os.File {
+	logFile, err := os.OpenFile(
+		config.LogFilePath(), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644,
+	)
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+	log.SetOutput(logFile)
+	return logFile
 }
 
-type keyMap struct {
-	Up    key.Binding
-	Down  key.Binding
-	Left  key.Binding
-	Right key.Binding
-	Help  key.Binding
-	Quit  key.Binding
+// newFetcher sets up fetching as configured, caching every feed fetched so
+// the subcommands can work offline.
+func newFetcher(stats *fetch.Stats) (fetch.Fetcher, error) {
+	fetcher := fetch.Fetcher{
+		Timeout:            time.Duration(viper.GetInt("fetchTimeout")) * time.Second,
+		MaxItems:           viper.GetInt("maxItems"),
+		CollapseDuplicates: viper.GetBool("collapseDuplicates"),
+		Stats:              stats,
+		Limiter:            fetch.NewLimiter(viper.GetInt("maxConcurrentFetches")),
+	}
+	// bad time zones are reported by the reader's startup; the subcommands
+	// make do without. A bad proxy fails everything, rather than feeds being
+	// fetched without the proxy they're meant to go through.
+	fetcher.Timezone, _ = config.Timezone()
+	fetcher.FeedTimezones, _ = config.FeedTimezones()
+	proxy, err := loadProxy()
+	if err != nil {
+		return fetcher, err
+	}
+	fetcher.Proxy = proxy
+	dir, err := config.CacheDir()
+	if err == nil {
+		fetcher.Cache, err = store.NewFeedCache(dir)
+	}
+	if err != nil {
+		log.Println(err)
+	}
+	if viper.GetBool("archive") {
+		fetcher.ArchiveHistory = viper.GetInt("archiveHistory")
+		dir, err := config.DataDir()
+		if err == nil {
+			fetcher.Archive, err = store.OpenArchive(filepath.Join(dir, "archive.db"))
+		}
+		if err != nil {
+			log.Println(err)
+		}
+	}
+	return fetcher, nil
 }
 
-var defaultKeyMap = keyMap{
-	Up: key.NewBinding(
-		key.WithKeys("k", "up"),
-		key.WithHelp("k/up", "move up"),
-	),
-	Down: key.NewBinding(
-		key.WithKeys("j", "down"),
-		key.WithHelp("j/down", "move down"),
-	),
-	Left: key.NewBinding(
-		key.WithKeys("h", "left"),
-		key.WithHelp("h/left", "move left"),
-	),
-	Right: key.NewBinding(
-		key.WithKeys("l", "right"),
-		key.WithHelp("l/right", "move right"),
-	),
-	Help: key.NewBinding(
-		key.WithKeys("?"),
-		key.WithHelp("?", "toggle help"),
-	),
-	Quit: key.NewBinding(
-		key.WithKeys("q", "esc", "ctrl+c"),
-		key.WithHelp("q/esc/<C-c>", "quit"),
-	),
+// loadProxy sets up the proxies feeds are fetched through, as configured.
+func loadProxy() (*fetch.Proxy, error) {
+	feedProxies, err := config.FeedProxies()
+	if err != nil {
+		return nil, err
+	}
+	return fetch.NewProxy(fetch.ProxyConfig{
+		HTTP:   viper.GetString("httpProxy"),
+		HTTPS:  viper.GetString("httpsProxy"),
+		SOCKS5: viper.GetString("socks5Proxy"),
+		Feeds:  feedProxies,
+	})
 }
 
-// ShortHelp returns keybindings to be shown in the mini help view. It's part
-// of the key.Map interface.
-func (k keyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Help, k.Quit}
+// loadBackends sets up the backends the feeds come from: the direct one,
+// then any sync servers configured, whose feeds the fetcher reads from them.
+// The Google Reader API server's is also what syncs the read state with it.
+// The servers are reached through the fetcher's proxy. The one named by
+// `backend` has to be among them.
+func loadBackends(fetcher *fetch.Fetcher) (backend.Backends, *greader.Syncer, error) {
+	backends := backend.Backends{&backend.Direct{Fetcher: fetcher}}
+	sync, err := loadGreader(fetcher.Proxy)
+	if err != nil {
+		return nil, nil, err
+	}
+	if sync != nil {
+		backends = append(backends, sync)
+	}
+	minifluxClient, err := loadMiniflux(fetcher.Proxy)
+	if err != nil {
+		return nil, nil, err
+	}
+	if minifluxClient != nil {
+		backends = append(backends, minifluxClient)
+	}
+	nextcloudClient, err := loadNextcloud(fetcher.Proxy, fetcher.Timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+	if nextcloudClient != nil {
+		backends = append(backends, nextcloudClient)
+	}
+	if fetcher.Sources == nil {
+		fetcher.Sources = make(map[string]fetch.Source)
+	}
+	for _, server := range backends[1:] {
+		// the servers' names are the schemes of their feeds' URLs
+		fetcher.Sources[server.Name()] = server.Fetch
+	}
+	if backends.Named(viper.GetString("backend")) == nil {
+		return nil, nil, fmt.Errorf("backend: %q isn't one of the configured backends, %s", viper.GetString("backend"), strings.Join(backends.Names(), ", "))
+	}
+	return backends, sync, nil
 }
 
-// FullHelp returns keybindings for the expanded help view. It's part of the
-// key.Map interface.
-func (k keyMap) FullHelp() [][]key.Binding {
-	return [][]key.Binding{
-		{k.Up, k.Down, k.Left, k.Right}, // first column
-		{k.Help, k.Quit},                // second column
+// listFeeds lists the feeds of each backend in turn, adding the groups
+// they're in there to groups. Starting without a server, the feeds it listed
+// last time are read from the cache.
+func listFeeds(backends backend.Backends, groups map[string][]string, timeout time.Duration) []string {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	var feedUrls []string
+	subscribed := make(map[string]bool)
+	for _, b := range backends {
+		subscriptions, err := b.List(ctx)
+		if err != nil {
+			log.Println(err)
+		}
+		for _, subscription := range subscriptions {
+			if !subscribed[subscription.URL] {
+				subscribed[subscription.URL] = true
+				feedUrls = append(feedUrls, subscription.URL)
+			}
+			for _, group := range subscription.Groups {
+				groups[group] = append(groups[group], subscription.URL)
+			}
+		}
 	}
+	return feedUrls
 }
 
-func (m model) Init() tea.Cmd {
-	return nil
+// loadGreader sets up syncing with a Google Reader API server, if one's
+// configured.
+func loadGreader(proxy *fetch.Proxy) (*greader.Syncer, error) {
+	var greaderConfig greader.Config
+	if err := viper.UnmarshalKey("greader", &greaderConfig); err != nil {
+		return nil, err
+	}
+	// viper leaves the defaults out of a section that's partly set
+	greaderConfig.SyncEvery = viper.GetInt("greader.syncEvery")
+	if err := greaderConfig.Validate(); err != nil {
+		return nil, err
+	}
+	if greaderConfig.URL == "" {
+		return nil, nil
+	}
+	dir, err := config.DataDir()
+	if err != nil {
+		return nil, err
+	}
+	client := greader.NewClient(greaderConfig, proxy.Transport(greaderConfig.URL))
+	return greader.NewSyncer(client, filepath.Join(dir, "greader.json"), time.Duration(greaderConfig.SyncEvery)*time.Minute)
 }
 
-func renderContent(content string, markdownConverter md.Converter) string {
-	var err error
-	// unescape HTML entities
-	content = html.UnescapeString(content)
-	// pass to HTML -> markdown converter (oops)
-	content, err = markdownConverter.ConvertString(content)
+// loadMiniflux sets up reading feeds from a Miniflux server, if one's
+// configured.
+func loadMiniflux(proxy *fetch.Proxy) (*miniflux.Client, error) {
+	var minifluxConfig miniflux.Config
+	if err := viper.UnmarshalKey("miniflux", &minifluxConfig); err != nil {
+		return nil, err
+	}
+	// viper leaves the defaults out of a section that's partly set
+	minifluxConfig.Entries = viper.GetInt("miniflux.entries")
+	if err := minifluxConfig.Validate(); err != nil {
+		return nil, err
+	}
+	if minifluxConfig.URL == "" {
+		return nil, nil
+	}
+	dir, err := config.DataDir()
 	if err != nil {
-		log.Fatal(err)
-		os.Exit(1)
+		return nil, err
+	}
+	return miniflux.NewClient(minifluxConfig, filepath.Join(dir, "miniflux.json"), proxy.Transport(minifluxConfig.URL)), nil
+}
+
+// loadNextcloud sets up reading feeds from a Nextcloud server's News app, if
+// one's configured, giving the password command timeout to print the
+// password.
+func loadNextcloud(proxy *fetch.Proxy, timeout time.Duration) (*nextcloud.Client, error) {
+	var nextcloudConfig nextcloud.Config
+	if err := viper.UnmarshalKey("nextcloud", &nextcloudConfig); err != nil {
+		return nil, err
+	}
+	// viper leaves the defaults out of a section that's partly set
+	nextcloudConfig.Items = viper.GetInt("nextcloud.items")
+	if err := nextcloudConfig.Validate(); err != nil {
+		return nil, err
 	}
-	// pass markdown content to glamour
-	content, err = glamour.Render(content, "dark")
+	if nextcloudConfig.URL == "" {
+		return nil, nil
+	}
+	dir, err := config.DataDir()
 	if err != nil {
-		log.Fatal(err)
-		os.Exit(1)
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := nextcloudConfig.ReadPassword(ctx); err != nil {
+		return nil, err
 	}
-	return content
+	return nextcloud.NewClient(nextcloudConfig, filepath.Join(dir, "nextcloud.json"), proxy.Transport(nextcloudConfig.URL)), nil
 }
 
-func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	var (
-		cmd  tea.Cmd
-		cmds []tea.Cmd
-	)
+// loadReadState reads which items have been read from the data directory.
+func loadReadState() (*store.ReadState, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return nil, err
+	}
+	return store.LoadReadState(filepath.Join(dir, "read.json"))
+}
 
-	rerender := false
+// loadProgress reads how far through long items the reader got from the
+// data directory.
+func loadProgress() (*store.Progress, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return nil, err
+	}
+	return store.LoadProgress(filepath.Join(dir, "progress.json"))
+}
 
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch {
-		case key.Matches(msg, defaultKeyMap.Left):
-			if m.feedIndex > 0 {
-				m.feedIndex--
-				rerender = true
-			}
-		case key.Matches(msg, defaultKeyMap.Right):
-			if m.feedIndex < getFeedLengthOrZero(m.feedSlice[m.feedSliceIndex]) {
-				m.feedIndex++
-				rerender = true
-			}
-		case key.Matches(msg, defaultKeyMap.Help):
-			m.help.ShowAll = !m.help.ShowAll
-		case key.Matches(msg, defaultKeyMap.Quit):
-			return m, tea.Quit
+// importOPML merges the feeds in an OPML file into the config, turning its
+// folders into groups.
+func importOPML(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	doc, err := opml.Parse(file)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	var feedUrls []string
+	groups := make(map[string][]string)
+	for _, feed := range doc.Feeds() {
+		feedUrls = append(feedUrls, feed.Url)
+		if feed.Group != "" {
+			groups[feed.Group] = append(groups[feed.Group], feed.Url)
 		}
+	}
+	added, err := config.MergeFeeds(feedUrls, groups)
+	if err != nil {
+		return err
+	}
+	log.Printf("imported %s: %d of %d feeds were new", path, added, len(feedUrls))
+	return nil
+}
 
-	case tea.WindowSizeMsg:
-		// set the width on the help menu if necessary (truncate if required)
-		m.help.Width = msg.Width
-
-		verticalMargins := headerHeight + footerHeight
-
-		if !m.ready {
-			// Since this program is using the full size of the viewport we need
-			// to wait until we've received the window dimensions before we
-			// can initialize the viewport. The initial dimensions come in
-			// quickly, though asynchronously, which is why we wait for them
-			// here.
-			m.viewport = viewport.Model{
-				Width:  msg.Width,
-				Height: msg.Height - verticalMargins,
-			}
-			m.viewport.HighPerformanceRendering = useHighPerformanceRenderer
+// loadStars reads the starred items from the data directory.
+func loadStars() (*store.Stars, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return nil, err
+	}
+	return store.LoadStars(filepath.Join(dir, "stars.json"))
+}
 
-			// render content
-			rerender = true
+// loadConfig reads the config, bugging out if there's one on disk that
+// can't be read.
+func loadConfig() {
+	if err := config.Load(); err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+}
 
-			// This is only necessary for high performance rendering, which in
-			// most cases you won't need.
-			//
-			// Render the viewport one line below the header.
-			m.viewport.YPosition = headerHeight + 1
+// pinPriorityFeeds moves priority feeds to the front of the list, otherwise
+// keeping the configured order.
+func pinPriorityFeeds(feedUrls []string, priority map[string]bool) []string {
+	var pinned, rest []string
+	for _, feedUrl := range feedUrls {
+		if priority[feedUrl] {
+			pinned = append(pinned, feedUrl)
 		} else {
-			m.viewport.Width = msg.Width
-			m.viewport.Height = msg.Height - verticalMargins
+			rest = append(rest, feedUrl)
 		}
+	}
+	return append(pinned, rest...)
+}
 
-		if useHighPerformanceRenderer {
-			// Render (or re-render) the whole viewport. Necessary both to
-			// initialize the viewport and when the window is resized.
-			//
-			// This is needed for high-performance rendering only.
-			cmds = append(cmds, viewport.Sync(m.viewport))
+func main() {
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			logFile := openLogFile()
+			loadConfig()
+			code := run(os.Args[2:])
+			logFile.Close()
+			os.Exit(code)
 		}
 	}
 
-	if rerender {
-		// the content that will be rendered
-		var content string
-		// if the feed is empty
-		// this case would also handle where we index out of bounds, but that case
-		// should not be handled here; it should already be handled where we attempt
-		// to increment/decrement the feedIndex
-		if m.feedIndex >= m.feedSlice[m.feedSliceIndex].Len() {
-			content = "No content here!"
-		} else {
-			item := m.feedSlice[m.feedSliceIndex].Items[m.feedIndex]
-			content = renderContent(
-				// inject a <hr> so the HTML -> MD converter will render the break
-				item.Description+"<hr>"+item.Content, m.markdownConverter,
-			)
+	lowBandwidthFlag := flag.Bool(
+		"low-bandwidth", false,
+		"don't fetch linked pages or show images, for metered connections",
+	)
+	// profiling is for development, so it's left out of --help
+	pprofAddr := flag.String("pprof", "", "serve net/http/pprof on this address")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage of %s:\n", os.Args[0])
+		flag.VisitAll(func(f *flag.Flag) {
+			if f.Name == "pprof" {
+				return
+			}
+			fmt.Fprintf(flag.CommandLine.Output(), "  -%s\n    \t%s\n", f.Name, f.Usage)
+		})
+	}
+	importFlag := flag.String(
+		"import-opml", "",
+		"merge the feeds in an OPML file into the config, folders becoming groups",
+	)
+	accessibleFlag := flag.Bool(
+		"accessible", false,
+		"plain, linear output without colors or box drawing, for screen readers",
+	)
+	flag.Parse()
+
+	// close the logfile after we exit
+	logFile := openLogFile()
+	defer logFile.Close()
+
+	if err := ui.PrepareTerminal(); err != nil {
+		log.Println(err)
+	}
+
+	if *pprofAddr != "" {
+		go func() {
+			log.Println(http.ListenAndServe(*pprofAddr, nil))
+		}()
+		log.Println("serving pprof on", *pprofAddr)
+	}
+
+	loadConfig()
+
+	if *lowBandwidthFlag {
+		viper.Set("lowBandwidth", true)
+	}
+	if *accessibleFlag {
+		viper.Set("accessible", true)
+	}
+	// the flag isn't copied into viper like the others, since importing
+	// saves the config and it'd be saved along with it
+	for _, path := range []string{viper.GetString("importOpml"), *importFlag} {
+		if path == "" {
+			continue
+		}
+		if err := importOPML(path); err != nil {
+			log.Fatal(err)
+			os.Exit(1)
 		}
-		m.viewport.SetContent(content)
-		m.ready = true
 	}
 
-	// Because we're using the viewport's default update function (with pager-
-	// style navigation) it's important that the viewport's update function:
-	//
-	// * Receives messages from the Bubble Tea runtime
-	// * Returns commands to the Bubble Tea runtime
-	//
-	m.viewport, cmd = m.viewport.Update(msg)
-	if useHighPerformanceRenderer {
-		cmds = append(cmds, cmd)
+	settings := viper.AllSettings()
+	// push, share and capture targets can have tokens in them, and the
+	// read-later service's and the sync servers' passwords and tokens are in
+	// their sections
+	delete(settings, "push")
+	delete(settings, "share")
+	delete(settings, "capture")
+	delete(settings, "readlater")
+	delete(settings, "greader")
+	delete(settings, "miniflux")
+	delete(settings, "nextcloud")
+	log.Println(settings)
+
+	fetcher, err := newFetcher(fetch.NewStats())
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
 	}
+	backends, sync, err := loadBackends(&fetcher)
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+	groups := config.Groups()
+	feedUrls := listFeeds(backends, groups, fetcher.Timeout)
 
-	return m, tea.Batch(cmds...)
-}
+	pausedFeeds := config.Set("pausedFeeds")
+	notifyFeeds := config.Set("notifyFeeds")
+	notifyAll, notifyOverrides, err := config.Notifications()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+	notifyMinScore, notifyOnScore := config.NotifyMinScore()
+	priorityFeeds := config.Set("priorityFeeds")
+	feedUrls = pinPriorityFeeds(feedUrls, priorityFeeds)
 
-func getFeedLengthOrZero(feed gofeed.Feed) int {
-	if feed.Len()-1 > 0 {
-		return feed.Len() - 1
-	} else {
-		return 0
+	// the feeds are fetched once the reader is up, so they all start out
+	// as placeholders
+	var feedSlice []gofeed.Feed
+	for _, feedUrl := range feedUrls {
+		feedSlice = append(feedSlice, fetch.Placeholder(feedUrl))
 	}
-}
 
-func assembleHeader(title string, m model) string {
-	return lipgloss.NewStyle().
-		Bold(true).
-		Background(lipgloss.Color(m.accent)).
-		Foreground(lipgloss.Color(m.textColor)).
-		PaddingLeft(m.horzPadding).
-		PaddingRight(m.horzPadding).
-		PaddingTop(m.vertPadding).
-		PaddingBottom(m.vertPadding).
-		Render(title)
-}
+	readState, err := loadReadState()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
 
-func assembleFooter(authors []string, publishedTime time.Time, m model) string {
-	var genericHorzPaddedStyle = lipgloss.NewStyle().
-		Background(lipgloss.Color(m.backgroundColor)).
-		PaddingLeft(m.horzPadding).
-		PaddingRight(m.horzPadding)
-
-	var progressFormattedStr = genericHorzPaddedStyle.Copy().
-		Bold(true).
-		Background(lipgloss.Color(m.accent)).
-		Foreground(lipgloss.Color(m.textColor)).
-		Render(fmt.Sprintf("%3.f%%", m.viewport.ScrollPercent()*100))
-
-	var articleCounterFormattedStr = genericHorzPaddedStyle.
-		Render(
-			fmt.Sprintf("%d/%d articles", m.feedIndex, getFeedLengthOrZero(m.feedSlice[m.feedSliceIndex])),
-		)
-
-	var authorsFormattedStr = genericHorzPaddedStyle.Copy().
-		Align(lipgloss.Right).
-		BorderLeft(true).
-		BorderLeftForeground(lipgloss.Color(m.textColor)).
-		Render(strings.Join(authors, ", "))
-
-	var timeFormattedStr = genericHorzPaddedStyle.Copy().
-		Align(lipgloss.Right).
-		BorderLeft(true).
-		BorderLeftForeground(lipgloss.Color(m.textColor)).
-		Render(
-			"Last updated " + publishedTime.Local().Format("2006-01-02 15:04:05 MST"),
-		)
-
-	// since the max width is passed into this function, create some whitespace
-	// to fill out the extra space.
-	consumedWidth := lipgloss.Width(progressFormattedStr) +
-		lipgloss.Width(articleCounterFormattedStr) +
-		lipgloss.Width(authorsFormattedStr) +
-		lipgloss.Width(timeFormattedStr)
-	// the empty str in Render() will be turned into spaces as per bubble's
-	// whitespace docs.
-	spacerStr := lipgloss.NewStyle().
-		Background(lipgloss.Color(m.backgroundColor)).
-		Width(m.viewport.Width - consumedWidth).
-		Render("")
-
-	return lipgloss.JoinHorizontal(
-		lipgloss.Bottom,
-		progressFormattedStr,
-		articleCounterFormattedStr,
-		spacerStr,
-		authorsFormattedStr,
-		timeFormattedStr,
-	)
-}
+	progress, err := loadProgress()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
 
-func (m model) View() string {
-	if !m.ready {
-		return "\n Loading content"
+	stars, err := loadStars()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
 	}
 
-	// return the help first if that's what was requested
-	if m.help.ShowAll {
-		helpView := m.help.View(defaultKeyMap)
-		return helpView
+	feedColors, err := config.FeedColors()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
 	}
 
-	if m.feedIndex < m.feedSlice[m.feedSliceIndex].Len() {
-		item := m.feedSlice[m.feedSliceIndex].Items[m.feedIndex]
-		var authorNames []string
-		for _, x := range item.Authors {
-			authorNames = append(authorNames, x.Name)
-		}
+	installCommands, err := config.InstallCommands()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
 
-		// try to pick a sensible "last updated" date
-		lastUpdatedDate := time.Unix(0, 0) // default to unix epoch
-		if item.PublishedParsed != nil {
-			lastUpdatedDate = *item.PublishedParsed
-		}
-		if item.UpdatedParsed != nil {
-			lastUpdatedDate = *item.UpdatedParsed
-		}
+	timezone, err := config.Timezone()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+	if _, err := config.FeedTimezones(); err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
 
-		return fmt.Sprintf("%s\n%s\n%s",
-			assembleHeader(item.Title, m),
-			m.viewport.View(),
-			assembleFooter(authorNames, lastUpdatedDate, m),
-		)
-	} else {
-		return fmt.Sprintf("%s\n%s\n%s",
-			assembleHeader("No content", m),
-			m.viewport.View(),
-			assembleFooter(nil, time.Unix(0, 0), m),
-		)
+	expireAfter, err := config.ExpireAfter()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
 	}
-}
 
-func main() {
-	// write everything to logfile
-	logFile, err := os.OpenFile(
-		"golang-rss-client.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644,
-	)
+	feedLanguages, err := config.FeedLanguages()
 	if err != nil {
 		log.Fatal(err)
 		os.Exit(1)
 	}
-	// close the logfile after we exit
-	defer logFile.Close()
-	// switch over to logFile output
-	log.SetOutput(logFile)
 
-	// defaults for color in reader
-	viper.SetDefault("accent", "33")
-	viper.SetDefault("textColor", "15")
-	viper.SetDefault("backgroundColor", "233")
-	viper.SetDefault("horzPadding", 2)
-	viper.SetDefault("vertPadding", 0)
-	viper.SetDefault("fetchTimeout", 15)
-	defaultFeedUrls := []string{"https://github.com/homielabs.atom"}
-	viper.SetDefault("feedUrls", defaultFeedUrls)
-
-	// config file locations
-	viper.SetConfigName("golang-rss-client.yml")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath("/etc/golang-rss-client/")
-	viper.AddConfigPath("$HOME/golang-rss-client/")
-	viper.AddConfigPath(".")
-
-	// read env vars
-	viper.SetEnvPrefix("golangrssclient")
-	viper.BindEnv("accent")
-	viper.BindEnv("textColor")
-	viper.BindEnv("backgroundColor")
-	viper.BindEnv("horzPadding")
-	viper.BindEnv("vertPadding")
-
-	if err := viper.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-			// Config file not found; ignore error since we have defaults
-			log.Println("Found no configs on disk")
-		} else {
-			// Config file was found but another error was produced
+	pressReleases, pressReleaseFeeds, err := config.PressReleases()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+	paywallDomains, paywallArchiveUrl, err := config.Paywall()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	scoreRules, err := loadScoreRules()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	var pushTargets []push.Target
+	if err := viper.UnmarshalKey("push", &pushTargets); err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+	for _, target := range pushTargets {
+		if err := target.Validate(); err != nil {
 			log.Fatal(err)
 			os.Exit(1)
 		}
 	}
 
-	log.Println(viper.AllSettings())
+	var shareTargets []share.Target
+	if err := viper.UnmarshalKey("share", &shareTargets); err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+	for _, target := range shareTargets {
+		if err := target.Validate(); err != nil {
+			log.Fatal(err)
+			os.Exit(1)
+		}
+	}
 
-	// parse the feeds
-	feedUrls := viper.GetStringSlice("feedUrls")
-	var feedSlice []gofeed.Feed
+	var captureConfig capture.Config
+	if err := viper.UnmarshalKey("capture", &captureConfig); err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+	// viper leaves the defaults out of a section that's partly set
+	captureConfig.Org.Keyword = viper.GetString("capture.org.keyword")
+	if err := captureConfig.Validate(); err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
 
-	feedParser := gofeed.NewParser()
-	for _, feedUrl := range feedUrls {
-		// create a timeout
-		fetchTimeout := viper.GetInt("fetchTimeout")
-		ctx, cancel := context.WithTimeout(
-			context.Background(), time.Duration(fetchTimeout)*time.Second,
-		)
-		defer cancel()
-		// parse the feed
-		feed, err := feedParser.ParseURLWithContext(feedUrl, ctx)
-		// bug out if necessary
+	var readLaterConfig readlater.Config
+	if err := viper.UnmarshalKey("readLater", &readLaterConfig); err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+	if err := readLaterConfig.Validate(); err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	var readingLogConfig readinglog.Config
+	if err := viper.UnmarshalKey("readingLog", &readingLogConfig); err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+	readingLogConfig.File = viper.GetString("readingLog.file")
+	readingLogConfig.CommitEvery = viper.GetInt("readingLog.commitEvery")
+	if err := readingLogConfig.Validate(); err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+	var readingLog *readinglog.Log
+	if readingLogConfig.Repo != "" {
+		readingLog, err = readinglog.New(readingLogConfig)
 		if err != nil {
 			log.Fatal(err)
 			os.Exit(1)
 		}
-		feedSlice = append(feedSlice, *feed)
-	}
-
-	// define a starter model
-	starter_model := model{
-		feedIndex:         0,
-		help:              help.NewModel(),
-		markdownConverter: *md.NewConverter("", true, nil),
-		accent:            viper.GetString("accent"),
-		textColor:         viper.GetString("textColor"),
-		backgroundColor:   viper.GetString("backgroundColor"),
-		horzPadding:       viper.GetInt("horzPadding"),
-		vertPadding:       viper.GetInt("vertPadding"),
-		fetchTimeout:      viper.GetInt("fetchTimeout"),
-		feedSlice:         feedSlice,
-		feedSliceIndex:    0,
-	}
-	// create the bubbletea program with the starter model
-	p := tea.NewProgram(
-		starter_model,
-		tea.WithAltScreen(),
-		tea.WithMouseCellMotion(),
-	)
+	}
+
+	downloadDir, err := config.DownloadDir()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	theme, err := config.Theme()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	if err := ui.RemapKeys(viper.GetStringMapStringSlice("keys")); err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	quietHours, err := config.QuietHours()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
 
-	if err := p.Start(); err != nil {
+	var markdown render.MarkdownConfig
+	if err := viper.UnmarshalKey("markdown", &markdown); err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+	markdownConverter, feedConverters, err := markdown.Converters(viper.GetBool("lowBandwidth"))
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	err = ui.Run(ui.Options{
+		Feeds:                   feedSlice,
+		FeedUrls:                feedUrls,
+		PausedFeeds:             pausedFeeds,
+		FollowedAuthors:         viper.GetStringSlice("followedAuthors"),
+		NotifyFeeds:             notifyFeeds,
+		NotifyAll:               notifyAll,
+		NotifyOverrides:         notifyOverrides,
+		NotifyMinScore:          notifyMinScore,
+		NotifyOnScore:           notifyOnScore,
+		DesktopNotifications:    viper.GetBool("desktopNotifications"),
+		PushTargets:             pushTargets,
+		ShareTargets:            shareTargets,
+		Capture:                 captureConfig,
+		ReadLater:               readLaterConfig,
+		Languages:               config.Set("languages"),
+		FeedLanguages:           feedLanguages,
+		MutedLanguages:          config.Set("mutedLanguages"),
+		PressReleases:           pressReleases,
+		PressReleaseFeeds:       pressReleaseFeeds,
+		PaywallDomains:          paywallDomains,
+		PaywallArchiveURL:       paywallArchiveUrl,
+		PriorityFeeds:           priorityFeeds,
+		Groups:                  groups,
+		Fetcher:                 fetcher,
+		ReadState:               readState,
+		Stars:                   stars,
+		Progress:                progress,
+		ReadingLog:              readingLog,
+		Sync:                    sync,
+		Backends:                backends,
+		Backend:                 backends.Named(viper.GetString("backend")),
+		Converter:               markdownConverter,
+		FeedConverters:          feedConverters,
+		FeedDisplays:            markdown.Displays(),
+		FeedTitles:              config.FeedTitles(),
+		Theme:                   theme,
+		Accent:                  viper.GetString("accent"),
+		FeedColors:              feedColors,
+		InstallCommands:         installCommands,
+		TextColor:               viper.GetString("textColor"),
+		BackgroundColor:         viper.GetString("backgroundColor"),
+		HorzPadding:             viper.GetInt("horzPadding"),
+		VertPadding:             viper.GetInt("vertPadding"),
+		LowBandwidth:            viper.GetBool("lowBandwidth"),
+		Accessible:              viper.GetBool("accessible"),
+		ASCIIOnly:               viper.GetBool("asciiOnly"),
+		SetWindowTitle:          viper.GetBool("setWindowTitle"),
+		UpdateMovedFeeds:        viper.GetBool("updateMovedFeeds"),
+		RefreshInterval:         time.Duration(viper.GetInt("refreshInterval")) * time.Minute,
+		PriorityRefreshInterval: time.Duration(viper.GetInt("priorityRefreshInterval")) * time.Minute,
+		QuietHours:              quietHours,
+		Timezone:                timezone,
+		Score:                   scoreFunc(scoreRules, readState),
+		BrowserCommand:          viper.GetString("browserCommand"),
+		DownloadDir:             downloadDir,
+		PlayerCommand:           viper.GetString("player"),
+		ExpireAfter:             expireAfter,
+		MarkReadOnScroll:        viper.GetBool("markReadOnScroll"),
+		MarkReadAfter:           time.Duration(viper.GetInt("markReadAfter")) * time.Second,
+		QuietRefreshInterval:    time.Duration(viper.GetInt("quietRefreshInterval")) * time.Minute,
+	})
+	if err != nil {
 		log.Fatal(err)
 		os.Exit(1)
 	}
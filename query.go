@@ -0,0 +1,480 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/homielabs/golang-rss-client/internal/store"
+	"github.com/mmcdole/gofeed"
+)
+
+// The query language is a small boolean expression language over items:
+//
+//	feed == "lobsters" && unread && title contains "go"
+//	!(author == "bob") || published >= "2026-01-01"
+//	link matches "^https://github\.com/"
+//
+// Strings compare case-insensitively (except with matches, a regular
+// expression), and dates are ISO 8601 strings so they compare in order.
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// words that act as comparison operators rather than field names
+var wordOperators = map[string]bool{"contains": true, "matches": true}
+
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	runes := []rune(src)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{tokenLeftParen, "(", i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tokenRightParen, ")", i})
+			i++
+		case r == '"':
+			start := i
+			var sb strings.Builder
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				sb.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at %d", start)
+			}
+			i++
+			tokens = append(tokens, token{tokenString, sb.String(), start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			word := string(runes[start:i])
+			kind := tokenIdent
+			if wordOperators[word] {
+				kind = tokenOperator
+			}
+			tokens = append(tokens, token{kind, word, start})
+		default:
+			start := i
+			two := ""
+			if i+1 < len(runes) {
+				two = string(runes[i : i+2])
+			}
+			switch {
+			case two == "&&" || two == "||" || two == "==" || two == "!=" || two == "<=" || two == ">=":
+				tokens = append(tokens, token{tokenOperator, two, start})
+				i += 2
+			case r == '!' || r == '<' || r == '>':
+				tokens = append(tokens, token{tokenOperator, string(r), start})
+				i++
+			default:
+				return nil, fmt.Errorf("unexpected %q at %d", r, start)
+			}
+		}
+	}
+	return append(tokens, token{tokenEOF, "", len(runes)}), nil
+}
+
+// item fields available to queries, as strings or booleans
+type queryItem struct {
+	strings map[string]string
+	bools   map[string]bool
+}
+
+type queryValue struct {
+	isBool bool
+	b      bool
+	s      string
+}
+
+type queryExpr interface {
+	eval(item queryItem) (queryValue, error)
+}
+
+type literalExpr struct{ value queryValue }
+
+type fieldExpr struct{ name string }
+
+type notExpr struct{ operand queryExpr }
+
+type logicalExpr struct {
+	op          string
+	left, right queryExpr
+}
+
+type compareExpr struct {
+	op          string
+	left, right queryExpr
+	// compiled once for matches
+	pattern *regexp.Regexp
+}
+
+func (e literalExpr) eval(queryItem) (queryValue, error) { return e.value, nil }
+
+func (e fieldExpr) eval(item queryItem) (queryValue, error) {
+	if s, ok := item.strings[e.name]; ok {
+		return queryValue{s: s}, nil
+	}
+	if b, ok := item.bools[e.name]; ok {
+		return queryValue{isBool: true, b: b}, nil
+	}
+	return queryValue{}, fmt.Errorf("unknown field %q", e.name)
+}
+
+func evalBool(e queryExpr, item queryItem) (bool, error) {
+	v, err := e.eval(item)
+	if err != nil {
+		return false, err
+	}
+	if !v.isBool {
+		return false, fmt.Errorf("expected true/false, got %q", v.s)
+	}
+	return v.b, nil
+}
+
+func (e notExpr) eval(item queryItem) (queryValue, error) {
+	b, err := evalBool(e.operand, item)
+	return queryValue{isBool: true, b: !b}, err
+}
+
+func (e logicalExpr) eval(item queryItem) (queryValue, error) {
+	left, err := evalBool(e.left, item)
+	if err != nil {
+		return queryValue{}, err
+	}
+	// short-circuit like you'd expect
+	if e.op == "&&" && !left || e.op == "||" && left {
+		return queryValue{isBool: true, b: left}, nil
+	}
+	right, err := evalBool(e.right, item)
+	return queryValue{isBool: true, b: right}, err
+}
+
+func (e compareExpr) eval(item queryItem) (queryValue, error) {
+	left, err := e.left.eval(item)
+	if err != nil {
+		return queryValue{}, err
+	}
+	right, err := e.right.eval(item)
+	if err != nil {
+		return queryValue{}, err
+	}
+
+	if left.isBool || right.isBool {
+		if !left.isBool || !right.isBool || (e.op != "==" && e.op != "!=") {
+			return queryValue{}, fmt.Errorf("can't use %s with true/false values", e.op)
+		}
+		return queryValue{isBool: true, b: (left.b == right.b) == (e.op == "==")}, nil
+	}
+
+	if e.op == "matches" {
+		return queryValue{isBool: true, b: e.pattern.MatchString(left.s)}, nil
+	}
+
+	l, r := strings.ToLower(left.s), strings.ToLower(right.s)
+	var result bool
+	switch e.op {
+	case "==":
+		result = l == r
+	case "!=":
+		result = l != r
+	case "contains":
+		result = strings.Contains(l, r)
+	case "<":
+		result = l < r
+	case "<=":
+		result = l <= r
+	case ">":
+		result = l > r
+	case ">=":
+		result = l >= r
+	}
+	return queryValue{isBool: true, b: result}, nil
+}
+
+type queryParser struct {
+	tokens []token
+	pos    int
+}
+
+// parseQuery compiles a query expression.
+func parseQuery(src string) (queryExpr, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at %d", next.text, next.pos)
+	}
+	return expr, nil
+}
+
+func (p *queryParser) peek() token { return p.tokens[p.pos] }
+
+func (p *queryParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *queryParser) parseOr() (queryExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOperator && p.peek().text == "||" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalExpr{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (queryExpr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOperator && p.peek().text == "&&" {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalExpr{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseNot() (queryExpr, error) {
+	if p.peek().kind == tokenOperator && p.peek().text == "!" {
+		p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *queryParser) parseComparison() (queryExpr, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	op := p.peek()
+	if op.kind != tokenOperator {
+		return left, nil
+	}
+	switch op.text {
+	case "==", "!=", "<", "<=", ">", ">=", "contains", "matches":
+	default:
+		return left, nil
+	}
+	p.next()
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	compare := compareExpr{op: op.text, left: left, right: right}
+	if op.text == "matches" {
+		literal, ok := right.(literalExpr)
+		if !ok || literal.value.isBool {
+			return nil, fmt.Errorf("matches needs a string pattern at %d", op.pos)
+		}
+		compare.pattern, err = regexp.Compile(literal.value.s)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return compare, nil
+}
+
+func (p *queryParser) parseOperand() (queryExpr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return literalExpr{value: queryValue{s: t.text}}, nil
+	case tokenIdent:
+		switch t.text {
+		case "true":
+			return literalExpr{value: queryValue{isBool: true, b: true}}, nil
+		case "false":
+			return literalExpr{value: queryValue{isBool: true, b: false}}, nil
+		}
+		return fieldExpr{name: t.text}, nil
+	case tokenLeftParen:
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRightParen {
+			return nil, fmt.Errorf("expected ) at %d", closing.pos)
+		}
+		return expr, nil
+	case tokenEOF:
+		return nil, fmt.Errorf("unexpected end of query")
+	}
+	return nil, fmt.Errorf("unexpected %q at %d", t.text, t.pos)
+}
+
+// itemDate picks the most recent date an item carries.
+func itemDate(item *gofeed.Item) *time.Time {
+	if item.UpdatedParsed != nil {
+		return item.UpdatedParsed
+	}
+	return item.PublishedParsed
+}
+
+func newQueryItem(feedUrl string, feed *gofeed.Feed, item *gofeed.Item, state *store.ReadState) queryItem {
+	var authors []string
+	for _, author := range item.Authors {
+		authors = append(authors, author.Name)
+	}
+	published := ""
+	if date := itemDate(item); date != nil {
+		published = date.UTC().Format(time.RFC3339)
+	}
+	read := state.IsRead(item)
+	return queryItem{
+		strings: map[string]string{
+			"feed":        feed.Title,
+			"feedUrl":     feedUrl,
+			"title":       item.Title,
+			"link":        item.Link,
+			"author":      strings.Join(authors, ", "),
+			"description": item.Description,
+			"content":     item.Content,
+			"published":   published,
+			"guid":        item.GUID,
+			"categories":  strings.Join(item.Categories, ", "),
+		},
+		bools: map[string]bool{
+			"read":   read,
+			"unread": !read,
+		},
+	}
+}
+
+type queryResult struct {
+	Feed      string `json:"feed"`
+	Title     string `json:"title"`
+	Link      string `json:"link"`
+	Published string `json:"published"`
+	Unread    bool   `json:"unread"`
+}
+
+// runQuery implements `golang-rss-client query <expression>`, printing every
+// stored item the expression matches.
+func runQuery(args []string) int {
+	flags := flag.NewFlagSet("query", flag.ContinueOnError)
+	format := flags.String("format", "json", "output format: json or tsv")
+	fetch := flags.Bool("fetch", false, "fetch feeds instead of using the cached copies")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: golang-rss-client query [flags] <expression>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 || (*format != "json" && *format != "tsv") {
+		flags.Usage()
+		return 2
+	}
+
+	expr, err := parseQuery(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "query:", err)
+		return 2
+	}
+	state, err := loadReadState()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	feeds, err := loadSubscribedFeeds(*fetch)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	results := []queryResult{}
+	for _, subscription := range feeds {
+		for _, item := range subscription.feed.Items {
+			fields := newQueryItem(subscription.url, subscription.feed, item, state)
+			matched, err := evalBool(expr, fields)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "query:", err)
+				return 2
+			}
+			if matched {
+				results = append(results, queryResult{
+					Feed:      fields.strings["feed"],
+					Title:     item.Title,
+					Link:      item.Link,
+					Published: fields.strings["published"],
+					Unread:    fields.bools["unread"],
+				})
+			}
+		}
+	}
+
+	if *format == "tsv" {
+		// tabs and newlines would break the columns
+		clean := strings.NewReplacer("\t", " ", "\n", " ")
+		for _, r := range results {
+			fmt.Printf("%s\t%s\t%t\t%s\t%s\n",
+				clean.Replace(r.Feed), r.Published, r.Unread, clean.Replace(r.Title), r.Link,
+			)
+		}
+		return 0
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(results); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
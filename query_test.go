@@ -0,0 +1,130 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []token
+	}{
+		{`unread`, []token{{tokenIdent, "unread", 0}, {tokenEOF, "", 6}}},
+		{`title contains "go"`, []token{
+			{tokenIdent, "title", 0},
+			{tokenOperator, "contains", 6},
+			{tokenString, "go", 15},
+			{tokenEOF, "", 19},
+		}},
+		{`!(a>=b)`, []token{
+			{tokenOperator, "!", 0},
+			{tokenLeftParen, "(", 1},
+			{tokenIdent, "a", 2},
+			{tokenOperator, ">=", 3},
+			{tokenIdent, "b", 5},
+			{tokenRightParen, ")", 6},
+			{tokenEOF, "", 7},
+		}},
+		{`"say \"hi\""`, []token{{tokenString, `say "hi"`, 0}, {tokenEOF, "", 12}}},
+	}
+	for _, test := range tests {
+		t.Run(test.src, func(t *testing.T) {
+			got, err := tokenize(test.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("tokenize(%q) = %v, want %v", test.src, got, test.want)
+			}
+		})
+	}
+}
+
+func TestTokenizeErrors(t *testing.T) {
+	for _, src := range []string{`title == "go`, `a = b`, `a & b`, `#`} {
+		if _, err := tokenize(src); err == nil {
+			t.Errorf("tokenize(%q) didn't fail", src)
+		}
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	item := queryItem{
+		strings: map[string]string{
+			"feed":      "Lobsters",
+			"title":     "Go 1.18 is released",
+			"link":      "https://github.com/golang/go",
+			"author":    "bob",
+			"published": "2026-02-01T00:00:00Z",
+		},
+		bools: map[string]bool{"read": false, "unread": true},
+	}
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{`unread`, true},
+		{`read`, false},
+		{`!read`, true},
+		{`feed == "lobsters"`, true},
+		{`feed != "lobsters"`, false},
+		{`title contains "GO"`, true},
+		{`link matches "^https://github\\.com/"`, true},
+		{`link matches "^github"`, false},
+		{`published >= "2026-01-01"`, true},
+		{`published < "2026-01-01"`, false},
+		{`unread == true`, true},
+		{`unread && author == "alice"`, false},
+		{`unread && author == "alice" || feed == "lobsters"`, true},
+		{`!(author == "bob") || published >= "2027"`, false},
+		{`read && nosuchfield`, false},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			expr, err := parseQuery(test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := evalBool(expr, item)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	for _, query := range []string{
+		``,
+		`unread &&`,
+		`(unread`,
+		`unread)`,
+		`title matches author`,
+		`title matches "("`,
+		`title == "a" "b"`,
+	} {
+		if _, err := parseQuery(query); err == nil {
+			t.Errorf("parseQuery(%q) didn't fail", query)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	item := queryItem{
+		strings: map[string]string{"title": "t"},
+		bools:   map[string]bool{"unread": true},
+	}
+	for _, query := range []string{`title`, `nosuchfield`, `unread < true`, `unread == "x"`} {
+		expr, err := parseQuery(query)
+		if err != nil {
+			t.Fatalf("parseQuery(%q): %v", query, err)
+		}
+		if _, err := evalBool(expr, item); err == nil {
+			t.Errorf("%q evaluated without an error", query)
+		}
+	}
+}
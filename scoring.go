@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/homielabs/golang-rss-client/internal/store"
+	"github.com/homielabs/golang-rss-client/internal/ui"
+	"github.com/mmcdole/gofeed"
+	"github.com/spf13/viper"
+)
+
+// scoreRule adds to the score of every item matching a query.
+type scoreRule struct {
+	query queryExpr
+	src   string
+	score float64
+}
+
+// loadScoreRules reads the `scoreRules` section, a list of queries (in the
+// query subcommand's language) with the score items matching each get.
+func loadScoreRules() ([]scoreRule, error) {
+	var entries []struct {
+		Query string  `mapstructure:"query"`
+		Score float64 `mapstructure:"score"`
+	}
+	if err := viper.UnmarshalKey("scoreRules", &entries); err != nil {
+		return nil, err
+	}
+	var rules []scoreRule
+	for _, entry := range entries {
+		expr, err := parseQuery(entry.Query)
+		if err != nil {
+			return nil, fmt.Errorf("scoreRules: %q: %w", entry.Query, err)
+		}
+		rules = append(rules, scoreRule{query: expr, src: entry.Query, score: entry.Score})
+	}
+	return rules, nil
+}
+
+// scoreFunc totals the scores of the rules an item matches. A rule that
+// can't be evaluated (comparing a boolean field, say) is logged and skipped.
+func scoreFunc(rules []scoreRule, state *store.ReadState) ui.ScoreFunc {
+	if len(rules) == 0 {
+		return nil
+	}
+	return func(feedUrl string, feed *gofeed.Feed, item *gofeed.Item) float64 {
+		fields := newQueryItem(feedUrl, feed, item, state)
+		total := 0.0
+		for _, rule := range rules {
+			matched, err := evalBool(rule.query, fields)
+			if err != nil {
+				log.Printf("scoreRules: %q: %s", rule.src, err)
+				continue
+			}
+			if matched {
+				total += rule.score
+			}
+		}
+		return total
+	}
+}
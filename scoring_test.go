@@ -0,0 +1,66 @@
+package main
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/homielabs/golang-rss-client/internal/store"
+	"github.com/mmcdole/gofeed"
+	"github.com/spf13/viper"
+)
+
+func TestScoreRules(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	defer viper.Reset()
+	viper.Set("scoreRules", []map[string]interface{}{
+		{"query": `title contains "go"`, "score": 2},
+		{"query": `feed == "lobsters"`, "score": 1.5},
+		{"query": `author == "spammer"`, "score": -10},
+		// can't be evaluated, so it's skipped
+		{"query": `title`, "score": 100},
+	})
+	rules, err := loadScoreRules()
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err := store.LoadReadState(filepath.Join(t.TempDir(), "read.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	score := scoreFunc(rules, state)
+
+	feed := &gofeed.Feed{Title: "Lobsters"}
+	tests := []struct {
+		name string
+		item gofeed.Item
+		want float64
+	}{
+		{"no rules matched", gofeed.Item{Title: "Rust"}, 1.5},
+		{"rules added up", gofeed.Item{Title: "Go 1.18"}, 3.5},
+		{"negative scores", gofeed.Item{Title: "Go", Authors: []*gofeed.Person{{Name: "spammer"}}}, -6.5},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := score("http://x/feed", feed, &test.item); got != test.want {
+				t.Errorf("score %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestScoreRulesErrors(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("scoreRules", []map[string]interface{}{{"query": `title ==`, "score": 1}})
+	if _, err := loadScoreRules(); err == nil {
+		t.Error("a rule that doesn't parse loaded")
+	}
+
+	viper.Reset()
+	if rules, err := loadScoreRules(); err != nil || scoreFunc(rules, nil) != nil {
+		t.Error("no rules should mean no scoring")
+	}
+}
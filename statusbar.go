@@ -0,0 +1,265 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/homielabs/golang-rss-client/internal/config"
+	"github.com/mmcdole/gofeed"
+)
+
+type feedUnread struct {
+	Title  string `json:"title"`
+	Url    string `json:"url"`
+	Unread int    `json:"unread"`
+}
+
+type groupUnread struct {
+	Name   string `json:"name"`
+	Unread int    `json:"unread"`
+}
+
+type unreadSummary struct {
+	Unread int           `json:"unread"`
+	Feeds  []feedUnread  `json:"feeds,omitempty"`
+	Groups []groupUnread `json:"groups,omitempty"`
+}
+
+// otherGroup is what feeds in no group are counted under.
+const otherGroup = "other"
+
+// waybarOutput is the JSON a waybar (or polybar) custom module expects from
+// a script with "return-type": "json".
+type waybarOutput struct {
+	Text    string `json:"text"`
+	Tooltip string `json:"tooltip"`
+	Class   string `json:"class"`
+}
+
+func (s unreadSummary) waybar() waybarOutput {
+	output := waybarOutput{Text: fmt.Sprint(s.Unread), Class: "read"}
+	if s.Unread > 0 {
+		output.Class = "new"
+	}
+
+	var lines []string
+	for _, feed := range s.Feeds {
+		if feed.Unread > 0 {
+			lines = append(lines, fmt.Sprintf("%s: %d", feed.Title, feed.Unread))
+		}
+	}
+	// the groups, when counted, stand in for the feeds
+	if len(s.Groups) > 0 {
+		lines = nil
+		for _, group := range s.Groups {
+			if group.Unread > 0 {
+				lines = append(lines, fmt.Sprintf("%s: %d", group.Name, group.Unread))
+			}
+		}
+	}
+	output.Tooltip = strings.Join(lines, "\n")
+	if output.Tooltip == "" {
+		output.Tooltip = "No unread items"
+	}
+	return output
+}
+
+type subscribedFeed struct {
+	url  string
+	feed *gofeed.Feed
+	// groups are the groups the feed is in, if any
+	groups []string
+}
+
+// loadSubscribedFeeds loads every feed that isn't paused for the subcommands,
+// as the reader lists them from the configured backends, preferring the
+// cached copy of each unless refetch is set. Feeds are fetched side by side,
+// maxConcurrentFetches at a time. Feeds that can't be loaded are logged and
+// left out; only a fetcher or backend that can't be set up fails them all.
+func loadSubscribedFeeds(refetch bool) ([]subscribedFeed, error) {
+	paused := config.Set("pausedFeeds")
+	fetcher, err := newFetcher(nil)
+	if err != nil {
+		return nil, err
+	}
+	backends, _, err := loadBackends(&fetcher)
+	if err != nil {
+		return nil, err
+	}
+	groups := config.Groups()
+	var feedUrls []string
+	for _, feedUrl := range listFeeds(backends, groups, fetcher.Timeout) {
+		if !paused[feedUrl] {
+			feedUrls = append(feedUrls, feedUrl)
+		}
+	}
+	feedGroups := make(map[string][]string)
+	for _, name := range sortedGroupNames(groups) {
+		for _, feedUrl := range groups[name] {
+			feedGroups[feedUrl] = append(feedGroups[feedUrl], name)
+		}
+	}
+
+	// each goroutine fills in its own slot, keeping the config's order
+	loaded := make([]*gofeed.Feed, len(feedUrls))
+	var wg sync.WaitGroup
+	for i, feedUrl := range feedUrls {
+		if !refetch && fetcher.Cache != nil {
+			if feed, err := fetcher.Cache.Load(feedUrl); err == nil {
+				loaded[i] = feed
+				continue
+			}
+		}
+		wg.Add(1)
+		go func(i int, feedUrl string) {
+			defer wg.Done()
+			feed, err := fetcher.Fetch(context.Background(), feedUrl)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+			loaded[i] = feed
+		}(i, feedUrl)
+	}
+	wg.Wait()
+
+	var feeds []subscribedFeed
+	titles := config.FeedTitles()
+	for i, feed := range loaded {
+		if feed == nil {
+			continue
+		}
+		if title, ok := titles[feedUrls[i]]; ok {
+			feed.Title = title
+		}
+		feeds = append(feeds, subscribedFeed{url: feedUrls[i], feed: feed, groups: feedGroups[feedUrls[i]]})
+	}
+	return feeds, nil
+}
+
+// sortedGroupNames are the names of the groups in alphabetical order, as the
+// reader lists them.
+func sortedGroupNames(groups map[string][]string) []string {
+	var names []string
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// groupCounts adds up the unread items of the feeds in each group, feeds in
+// several groups counting towards each and feeds in none towards
+// otherGroup, which comes last.
+func groupCounts(feeds []subscribedFeed, unread []int) []groupUnread {
+	counts := make(map[string]int)
+	for i, subscription := range feeds {
+		if len(subscription.groups) == 0 {
+			counts[otherGroup] += unread[i]
+		}
+		for _, name := range subscription.groups {
+			counts[name] += unread[i]
+		}
+	}
+	other, hasOther := counts[otherGroup]
+	delete(counts, otherGroup)
+	var names []string
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var groups []groupUnread
+	for _, name := range names {
+		groups = append(groups, groupUnread{Name: name, Unread: counts[name]})
+	}
+	if hasOther {
+		groups = append(groups, groupUnread{Name: otherGroup, Unread: other})
+	}
+	return groups
+}
+
+// runStatus implements `golang-rss-client status`, printing the number of
+// unread items for status bars. It works from the feeds cached by the last
+// fetch unless told to fetch, so it's cheap to run every few seconds.
+func runStatus(args []string) int {
+	flags := flag.NewFlagSet("status", flag.ContinueOnError)
+	format := flags.String("format", "plain", "output format: plain, json or waybar")
+	asJSON := flags.Bool("json", false, "shorthand for --format json")
+	perFeed := flags.Bool("per-feed", false, "include a count for every feed")
+	perGroup := flags.Bool("per-group", false, "include a count for every group")
+	fetch := flags.Bool("fetch", false, "fetch feeds instead of using the cached copies")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *asJSON {
+		*format = "json"
+	}
+	switch *format {
+	case "plain", "json", "waybar":
+	default:
+		fmt.Fprintf(os.Stderr, "unknown format %q\n", *format)
+		return 2
+	}
+	// waybar's tooltip lists every feed (or group), whether or not --per-feed
+	// was given
+	if *format == "waybar" && !*perGroup {
+		*perFeed = true
+	}
+
+	state, err := loadReadState()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	feeds, err := loadSubscribedFeeds(*fetch)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	summary := unreadSummary{}
+	unreadCounts := make([]int, len(feeds))
+	for i, subscription := range feeds {
+		unread := state.UnreadCount(*subscription.feed)
+		unreadCounts[i] = unread
+		summary.Unread += unread
+		if *perFeed {
+			summary.Feeds = append(summary.Feeds, feedUnread{
+				Title: subscription.feed.Title, Url: subscription.url, Unread: unread,
+			})
+		}
+	}
+	if *perGroup {
+		summary.Groups = groupCounts(feeds, unreadCounts)
+	}
+
+	switch *format {
+	case "json":
+		if err := json.NewEncoder(os.Stdout).Encode(summary); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	case "waybar":
+		if err := json.NewEncoder(os.Stdout).Encode(summary.waybar()); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+	fmt.Println(summary.Unread)
+	for _, feed := range summary.Feeds {
+		fmt.Printf("%d\t%s\n", feed.Unread, feed.Title)
+	}
+	for _, group := range summary.Groups {
+		fmt.Printf("%d\t%s\n", group.Unread, group.Name)
+	}
+	return 0
+}
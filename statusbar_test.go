@@ -0,0 +1,43 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGroupCounts(t *testing.T) {
+	tests := []struct {
+		name   string
+		feeds  []subscribedFeed
+		unread []int
+		want   []groupUnread
+	}{
+		{"no feeds", nil, nil, nil},
+		{
+			"one group each",
+			[]subscribedFeed{{url: "a", groups: []string{"news"}}, {url: "b", groups: []string{"blogs"}}},
+			[]int{2, 3},
+			[]groupUnread{{"blogs", 3}, {"news", 2}},
+		},
+		{
+			"feed in several groups counts in each",
+			[]subscribedFeed{{url: "a", groups: []string{"go", "news"}}, {url: "b", groups: []string{"news"}}},
+			[]int{2, 3},
+			[]groupUnread{{"go", 2}, {"news", 5}},
+		},
+		{
+			"ungrouped feeds go last under other",
+			[]subscribedFeed{{url: "a"}, {url: "b", groups: []string{"zines"}}, {url: "c"}},
+			[]int{1, 4, 0},
+			[]groupUnread{{"zines", 4}, {"other", 1}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := groupCounts(test.feeds, test.unread)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}